@@ -0,0 +1,266 @@
+package snmpsimclient
+
+import (
+	"context"
+	"io"
+)
+
+//go:generate go run ./internal/mockgen -output snmpsimmock/mocks_gen.go
+
+/*
+ManagementAPI contains all methods of the ManagementClient. Code depending on it instead of the ManagementClient can be tested
+with the mock implementation in the snmpsimmock package.
+*/
+type ManagementAPI interface {
+	//authentication
+	SetUsernameAndPassword(username, password string) error
+
+	//labs
+	GetLabs(filter map[string]string) (Labs, error)
+	GetLabsContext(ctx context.Context, filter map[string]string) (Labs, error)
+	SearchLabs(filter LabFilter) (Labs, error)
+	SearchLabsContext(ctx context.Context, filter LabFilter) (Labs, error)
+	GetLab(id int) (Lab, error)
+	GetLabContext(ctx context.Context, id int) (Lab, error)
+	CreateLab(name string) (Lab, error)
+	CreateLabContext(ctx context.Context, name string) (Lab, error)
+	CreateLabWithTag(name string, tagId int) (Lab, error)
+	CreateLabWithTagContext(ctx context.Context, name string, tagId int) (Lab, error)
+	DeleteLab(id int) error
+	DeleteLabContext(ctx context.Context, id int) error
+	UpdateLab(id int, update LabUpdate) (Lab, error)
+	UpdateLabContext(ctx context.Context, id int, update LabUpdate) (Lab, error)
+	AddAgentToLab(labId, agentId int) error
+	AddAgentToLabContext(ctx context.Context, labId, agentId int) error
+	RemoveAgentFromLab(labId, agentId int) error
+	RemoveAgentFromLabContext(ctx context.Context, labId, agentId int) error
+	SetLabPower(labId int, power bool) error
+	SetLabPowerContext(ctx context.Context, labId int, power bool) error
+	AddTagToLab(labId, tagId int) error
+	AddTagToLabContext(ctx context.Context, labId, tagId int) error
+	RemoveTagFromLab(labId, tagId int) error
+	RemoveTagFromLabContext(ctx context.Context, labId, tagId int) error
+
+	//engines
+	GetEngines(filter map[string]string) (Engines, error)
+	GetEnginesContext(ctx context.Context, filter map[string]string) (Engines, error)
+	SearchEngines(filter EngineFilter) (Engines, error)
+	SearchEnginesContext(ctx context.Context, filter EngineFilter) (Engines, error)
+	GetEngine(id int) (Engine, error)
+	GetEngineContext(ctx context.Context, id int) (Engine, error)
+	CreateEngine(name, engineId string) (Engine, error)
+	CreateEngineContext(ctx context.Context, name, engineId string) (Engine, error)
+	CreateEngineWithTag(name, engineId string, tagId int) (Engine, error)
+	CreateEngineWithTagContext(ctx context.Context, name, engineId string, tagId int) (Engine, error)
+	DeleteEngine(id int) error
+	DeleteEngineContext(ctx context.Context, id int) error
+	UpdateEngine(id int, update EngineUpdate) (Engine, error)
+	UpdateEngineContext(ctx context.Context, id int, update EngineUpdate) (Engine, error)
+	AddUserToEngine(engineId, userId int) error
+	AddUserToEngineContext(ctx context.Context, engineId, userId int) error
+	RemoveUserFromEngine(engineId, userId int) error
+	RemoveUserFromEngineContext(ctx context.Context, engineId, userId int) error
+	AddEndpointToEngine(engineId, endpointId int) error
+	AddEndpointToEngineContext(ctx context.Context, engineId, endpointId int) error
+	RemoveEndpointFromEngine(engineId, endpointId int) error
+	RemoveEndpointFromEngineContext(ctx context.Context, engineId, endpointId int) error
+	AddTagToEngine(engineId, tagId int) error
+	AddTagToEngineContext(ctx context.Context, engineId, tagId int) error
+	RemoveTagFromEngine(engineId, tagId int) error
+	RemoveTagFromEngineContext(ctx context.Context, engineId, tagId int) error
+
+	//agents
+	GetAgents(filters map[string]string) (Agents, error)
+	GetAgentsContext(ctx context.Context, filters map[string]string) (Agents, error)
+	SearchAgents(filter AgentFilter) (Agents, error)
+	SearchAgentsContext(ctx context.Context, filter AgentFilter) (Agents, error)
+	GetAgent(id int) (Agent, error)
+	GetAgentContext(ctx context.Context, id int) (Agent, error)
+	CreateAgent(name, dataDir string) (Agent, error)
+	CreateAgentContext(ctx context.Context, name, dataDir string) (Agent, error)
+	CreateAgentWithTag(name, dataDir string, tagId int) (Agent, error)
+	CreateAgentWithTagContext(ctx context.Context, name, dataDir string, tagId int) (Agent, error)
+	DeleteAgent(id int) error
+	DeleteAgentContext(ctx context.Context, id int) error
+	UpdateAgent(id int, update AgentUpdate) (Agent, error)
+	UpdateAgentContext(ctx context.Context, id int, update AgentUpdate) (Agent, error)
+	AddEngineToAgent(agentId, engineId int) error
+	AddEngineToAgentContext(ctx context.Context, agentId, engineId int) error
+	RemoveEngineFromAgent(agentId, engineId int) error
+	RemoveEngineFromAgentContext(ctx context.Context, agentId, engineId int) error
+	AddSelectorToAgent(agentId, selectorId int) (Agent, error)
+	AddSelectorToAgentContext(ctx context.Context, agentId, selectorId int) (Agent, error)
+	RemoveSelectorFromAgent(agentId, selectorId int) (Agent, error)
+	RemoveSelectorFromAgentContext(ctx context.Context, agentId, selectorId int) (Agent, error)
+	AddTagToAgent(agentId, tagId int) error
+	AddTagToAgentContext(ctx context.Context, agentId, tagId int) error
+	RemoveTagFromAgent(agentId, tagId int) error
+	RemoveTagFromAgentContext(ctx context.Context, agentId, tagId int) error
+
+	//endpoints
+	GetEndpoints(filters map[string]string) (Endpoints, error)
+	GetEndpointsContext(ctx context.Context, filters map[string]string) (Endpoints, error)
+	SearchEndpoints(filter EndpointFilter) (Endpoints, error)
+	SearchEndpointsContext(ctx context.Context, filter EndpointFilter) (Endpoints, error)
+	GetEndpoint(id int) (Endpoint, error)
+	GetEndpointContext(ctx context.Context, id int) (Endpoint, error)
+	CreateEndpoint(name, address, protocol string) (Endpoint, error)
+	CreateEndpointContext(ctx context.Context, name, address, protocol string) (Endpoint, error)
+	CreateEndpointWithTag(name, address, protocol string, tagId int) (Endpoint, error)
+	CreateEndpointWithTagContext(ctx context.Context, name, address, protocol string, tagId int) (Endpoint, error)
+	DeleteEndpoint(id int) error
+	DeleteEndpointContext(ctx context.Context, id int) error
+	UpdateEndpoint(id int, update EndpointUpdate) (Endpoint, error)
+	UpdateEndpointContext(ctx context.Context, id int, update EndpointUpdate) (Endpoint, error)
+	AddTagToEndpoint(endpointId, tagId int) error
+	AddTagToEndpointContext(ctx context.Context, endpointId, tagId int) error
+	RemoveTagFromEndpoint(endpointId, tagId int) error
+	RemoveTagFromEndpointContext(ctx context.Context, endpointId, tagId int) error
+
+	//record files
+	GetRecordFiles() (Recordings, error)
+	GetRecordFilesContext(ctx context.Context) (Recordings, error)
+	UploadRecordFile(localPath, remotePath string) error
+	UploadRecordFileContext(ctx context.Context, localPath, remotePath string) error
+	UploadRecordFileString(recordContents *string, remotePath string) error
+	UploadRecordFileStringContext(ctx context.Context, recordContents *string, remotePath string) error
+	DeleteRecordFile(remotePath string) error
+	DeleteRecordFileContext(ctx context.Context, remotePath string) error
+	GetRecordFile(remotePath string) (string, error)
+	GetRecordFileContext(ctx context.Context, remotePath string) (string, error)
+	UploadRecording(ctx context.Context, remotePath string, r io.Reader, opts ...TransferOption) error
+	DownloadRecording(ctx context.Context, remotePath string, w io.Writer, opts ...TransferOption) error
+	SyncRecordings(localDir, remotePrefix string, opts SyncOptions) (SyncReport, error)
+	SyncRecordingsContext(ctx context.Context, localDir, remotePrefix string, opts SyncOptions) (SyncReport, error)
+
+	//users
+	CreateUser(user, name, authKey, authProto, privKey, privProto string) (User, error)
+	CreateUserContext(ctx context.Context, user, name, authKey, authProto, privKey, privProto string) (User, error)
+	CreateUserWithTag(user, name, authKey, authProto, privKey, privProto string, tagId int) (User, error)
+	CreateUserWithTagContext(ctx context.Context, user, name, authKey, authProto, privKey, privProto string, tagId int) (User, error)
+	GetUsers(filters map[string]string) (Users, error)
+	GetUsersContext(ctx context.Context, filters map[string]string) (Users, error)
+	SearchUsers(filter UserFilter) (Users, error)
+	SearchUsersContext(ctx context.Context, filter UserFilter) (Users, error)
+	GetUser(id int) (User, error)
+	GetUserContext(ctx context.Context, id int) (User, error)
+	DeleteUser(id int) error
+	DeleteUserContext(ctx context.Context, id int) error
+	UpdateUser(id int, update UserUpdate) (User, error)
+	UpdateUserContext(ctx context.Context, id int, update UserUpdate) (User, error)
+	AddTagToUser(userId, tagId int) error
+	AddTagToUserContext(ctx context.Context, userId, tagId int) error
+	RemoveTagFromUser(userId, tagId int) error
+	RemoveTagFromUserContext(ctx context.Context, userId, tagId int) error
+
+	//selectors
+	CreateSelector(comment, template string) (Selector, error)
+	CreateSelectorContext(ctx context.Context, comment, template string) (Selector, error)
+	CreateSelectorWithTag(comment, template string, tagId int) (Selector, error)
+	CreateSelectorWithTagContext(ctx context.Context, comment, template string, tagId int) (Selector, error)
+	GetSelectors() (Selectors, error)
+	GetSelectorsContext(ctx context.Context) (Selectors, error)
+	GetSelector(id int) (Selector, error)
+	GetSelectorContext(ctx context.Context, id int) (Selector, error)
+	DeleteSelector(id int) error
+	DeleteSelectorContext(ctx context.Context, id int) error
+	AddTagToSelector(selectorId, tagId int) error
+	AddTagToSelectorContext(ctx context.Context, selectorId, tagId int) error
+	RemoveTagFromSelector(selectorId, tagId int) error
+	RemoveTagFromSelectorContext(ctx context.Context, selectorId, tagId int) error
+
+	//tags
+	CreateTag(name, description string) (Tag, error)
+	CreateTagContext(ctx context.Context, name, description string) (Tag, error)
+	GetTag(id int) (Tag, error)
+	GetTagContext(ctx context.Context, id int) (Tag, error)
+	GetTags(filters map[string]string) (Tags, error)
+	GetTagsContext(ctx context.Context, filters map[string]string) (Tags, error)
+	SearchTags(filter TagFilter) (Tags, error)
+	SearchTagsContext(ctx context.Context, filter TagFilter) (Tags, error)
+	DeleteTag(id int) error
+	DeleteTagContext(ctx context.Context, id int) error
+	UpdateTag(id int, update TagUpdate) (Tag, error)
+	UpdateTagContext(ctx context.Context, id int, update TagUpdate) (Tag, error)
+	DeleteAllObjectsWithTag(tagId int) (Tag, error)
+	DeleteAllObjectsWithTagContext(ctx context.Context, tagId int) (Tag, error)
+
+	//pagers
+	ListLabs(ctx context.Context, opts ListOptions) *LabPager
+	ListEngines(ctx context.Context, opts ListOptions) *EnginePager
+	ListAgents(ctx context.Context, opts ListOptions) *AgentPager
+	ListEndpoints(ctx context.Context, opts ListOptions) *EndpointPager
+	ListUsers(ctx context.Context, opts ListOptions) *UserPager
+	ListTags(ctx context.Context, opts ListOptions) *TagPager
+
+	//selector routing
+	ResolveSelector(agent Agent, template string, request SelectorRequest) (SelectorRoute, error)
+	ResolveSelectorContext(ctx context.Context, agent Agent, template string, request SelectorRequest) (SelectorRoute, error)
+
+	//lab specs
+	ApplyLab(spec LabSpec) (Lab, error)
+	ApplyLabContext(ctx context.Context, spec LabSpec) (Lab, error)
+
+	//manifests
+	ExportLab(labId int) (Manifest, error)
+	ExportLabContext(ctx context.Context, labId int) (Manifest, error)
+	ApplyManifest(manifest Manifest) (Labs, error)
+	ApplyManifestContext(ctx context.Context, manifest Manifest) (Labs, error)
+
+	//transactions
+	NewTransaction() *Transaction
+	NewTransactionContext(ctx context.Context) *Transaction
+	InTransaction(build func(tx *Transaction) error) error
+	InTransactionContext(ctx context.Context, build func(tx *Transaction) error) error
+}
+
+/*
+MetricsAPI contains all methods of the MetricsClient. Code depending on it instead of the MetricsClient can be tested
+with the mock implementation in the snmpsimmock package.
+*/
+type MetricsAPI interface {
+	//authentication
+	SetUsernameAndPassword(username, password string) error
+	GetProcesses(filters map[string]string) (ProcessesMetrics, error)
+	GetProcessesContext(ctx context.Context, filters map[string]string) (ProcessesMetrics, error)
+	GetProcess(id int) (ProcessMetrics, error)
+	GetProcessContext(ctx context.Context, id int) (ProcessMetrics, error)
+	GetProcessEndpoints(id int) (ProcessEndpoints, error)
+	GetProcessEndpointsContext(ctx context.Context, id int) (ProcessEndpoints, error)
+	GetProcessEndpoint(processId int, endpointId int) (ProcessEndpoint, error)
+	GetProcessEndpointContext(ctx context.Context, processId int, endpointId int) (ProcessEndpoint, error)
+	GetProcessConsolePages(processId int) (Consoles, error)
+	GetProcessConsolePagesContext(ctx context.Context, processId int) (Consoles, error)
+	GetProcessConsolePage(processId int, pageId int) (Console, error)
+	GetProcessConsolePageContext(ctx context.Context, processId int, pageId int) (Console, error)
+	GetPackets(filters map[string]string) (PacketMetrics, error)
+	GetPacketsContext(ctx context.Context, filters map[string]string) (PacketMetrics, error)
+	SearchPackets(filter PacketFilter) (PacketMetrics, error)
+	SearchPacketsContext(ctx context.Context, filter PacketFilter) (PacketMetrics, error)
+	GetPacketFilters() (PacketFilters, error)
+	GetPacketFiltersContext(ctx context.Context) (PacketFilters, error)
+	GetPossibleValuesForPacketFilter(filter string) ([]string, error)
+	GetPossibleValuesForPacketFilterContext(ctx context.Context, filter string) ([]string, error)
+	GetMessages(filters map[string]string) (MessageMetrics, error)
+	GetMessagesContext(ctx context.Context, filters map[string]string) (MessageMetrics, error)
+	SearchMessages(filter MessageFilter) (MessageMetrics, error)
+	SearchMessagesContext(ctx context.Context, filter MessageFilter) (MessageMetrics, error)
+	GetMessageFilters() (MessageFilters, error)
+	GetMessageFiltersContext(ctx context.Context) (MessageFilters, error)
+	GetPossibleValuesForMessageFilter(filter string) ([]string, error)
+	GetPossibleValuesForMessageFilterContext(ctx context.Context, filter string) ([]string, error)
+
+	//pagers
+	ListProcesses(ctx context.Context, opts ListOptions) *ProcessPager
+
+	//poller
+	PollMetrics(ctx context.Context, opts PollOptions) (*MetricsPoller, error)
+
+	//filter catalog
+	FilterCatalog() *FilterCatalog
+}
+
+var (
+	_ ManagementAPI = (*ManagementClient)(nil)
+	_ MetricsAPI    = (*MetricsClient)(nil)
+)
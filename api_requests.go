@@ -0,0 +1,61 @@
+package snmpsimclient
+
+/*
+LabUpdate contains the attributes of a lab which are changed by UpdateLab. Nil fields are left unchanged.
+The power state is changed with SetLabPower.
+*/
+type LabUpdate struct {
+	Name *string `json:"name,omitempty"`
+}
+
+/*
+AgentUpdate contains the attributes of an agent which are changed by UpdateAgent. Nil fields are left unchanged.
+*/
+type AgentUpdate struct {
+	Name    *string `json:"name,omitempty"`
+	DataDir *string `json:"data_dir,omitempty"`
+}
+
+/*
+EngineUpdate contains the attributes of an engine which are changed by UpdateEngine. Nil fields are left unchanged.
+*/
+type EngineUpdate struct {
+	Name     *string `json:"name,omitempty"`
+	EngineId *string `json:"engine_id,omitempty"`
+}
+
+/*
+EndpointUpdate contains the attributes of an endpoint which are changed by UpdateEndpoint. Nil fields are left unchanged.
+*/
+type EndpointUpdate struct {
+	Name     *string `json:"name,omitempty"`
+	Address  *string `json:"address,omitempty"`
+	Protocol *string `json:"protocol,omitempty"`
+}
+
+/*
+UserUpdate contains the attributes of a user which are changed by UpdateUser. Nil fields are left unchanged.
+*/
+type UserUpdate struct {
+	User      *string `json:"user,omitempty"`
+	Name      *string `json:"name,omitempty"`
+	AuthKey   *string `json:"auth_key,omitempty"`
+	AuthProto *string `json:"auth_proto,omitempty"`
+	PrivKey   *string `json:"priv_key,omitempty"`
+	PrivProto *string `json:"priv_proto,omitempty"`
+}
+
+/*
+TagUpdate contains the attributes of a tag which are changed by UpdateTag. Nil fields are left unchanged.
+*/
+type TagUpdate struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+/*
+String returns a pointer to the given string, it is a helper for filling the fields of update structs.
+*/
+func String(s string) *string {
+	return &s
+}
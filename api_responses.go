@@ -114,10 +114,6 @@ type User struct {
 	Tags      Tags   `json:"tags"`
 }
 
-//TODO: not implemented int the api yet, there is only one default selector for snmpv2c and one for snmpv3.
-// We have to wait with the implementation until its implemented in the api.
-// The strucuture is like this in the api doc but it might be different in the real api, this has to be checked first before it can be used.
-
 /*
 Selectors is an array of selectors.
 */
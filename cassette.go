@@ -0,0 +1,314 @@
+package snmpsimclient
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+/*
+CassetteMode defines whether a cassette records real traffic or replays recorded traffic.
+*/
+type CassetteMode int
+
+const (
+	//CassetteRecord sends all requests to the api and writes them together with their responses to the cassette file
+	CassetteRecord CassetteMode = iota
+	//CassetteReplay answers all requests from the cassette file without sending them to the api
+	CassetteReplay
+)
+
+//cassetteVersion is the version of the cassette file format
+const cassetteVersion = 1
+
+//redactedValue replaces the values of redacted headers in cassette files
+const redactedValue = "REDACTED"
+
+//CassetteBodyBase64 is the encoding of bodies which are no valid UTF-8, e.g. compressed recordings
+const CassetteBodyBase64 = "base64"
+
+/*
+CassetteInteraction is a recorded request together with its response.
+*/
+type CassetteInteraction struct {
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+/*
+CassetteRequest is a recorded request. The path is the escaped path relative to the base url of the client,
+as built by the client for the request.
+*/
+type CassetteRequest struct {
+	Method  string      `json:"method"`
+	Path    string      `json:"path"`
+	Query   url.Values  `json:"query,omitempty"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+	//BodyEncoding is CassetteBodyBase64 if the body is no valid UTF-8 and was base64 encoded, it is empty otherwise.
+	BodyEncoding string `json:"body_encoding,omitempty"`
+}
+
+/*
+CassetteResponse is a recorded response.
+*/
+type CassetteResponse struct {
+	StatusCode int         `json:"status_code"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	//BodyEncoding is CassetteBodyBase64 if the body is no valid UTF-8 and was base64 encoded, it is empty otherwise.
+	BodyEncoding string `json:"body_encoding,omitempty"`
+}
+
+/*
+CassetteMissError is returned in replay mode if the cassette does not contain an unused interaction for a request.
+Requests failing with this error are not retried.
+*/
+type CassetteMissError struct {
+	Method string
+	Path   string
+}
+
+func (c *CassetteMissError) Error() string {
+	return "cassette contains no recorded interaction for " + c.Method + " " + c.Path
+}
+
+//cassetteFile is the content of a cassette file
+type cassetteFile struct {
+	Version      int                   `json:"version"`
+	Interactions []CassetteInteraction `json:"interactions"`
+}
+
+/*
+WithCassette records all requests of the client and their responses into the cassette file at the given path
+or replays them from it, depending on the mode. In record mode an existing cassette file is overwritten.
+In replay mode a request is answered with the first unused recorded interaction with the same method, path, query and body.
+The Authorization header containing the credentials set with SetUsernameAndPassword is always redacted before it is
+written to the cassette, the values of the given headers are redacted as well.
+*/
+func WithCassette(path string, mode CassetteMode, redactHeaders ...string) ClientOption {
+	return func(o *clientOptions) error {
+		if path == "" {
+			return errors.New("invalid cassette path")
+		}
+		redact := append([]string{"Authorization"}, redactHeaders...)
+		c := &cassette{path: path, mode: mode, redactHeaders: redact}
+		switch mode {
+		case CassetteRecord:
+		case CassetteReplay:
+			if err := c.load(); err != nil {
+				return err
+			}
+		default:
+			return errors.New("invalid cassette mode " + strconv.Itoa(int(mode)))
+		}
+		o.cassette = c
+		return nil
+	}
+}
+
+//cassette stores the interactions of one client
+type cassette struct {
+	path          string
+	mode          CassetteMode
+	redactHeaders []string
+
+	mtx          sync.Mutex
+	interactions []CassetteInteraction
+	used         []bool
+}
+
+func (c *cassette) load() error {
+	data, err := ioutil.ReadFile(c.path)
+	if err != nil {
+		return errors.Wrap(err, "error while reading cassette file")
+	}
+	var file cassetteFile
+	if err = json.Unmarshal(data, &file); err != nil {
+		return errors.Wrap(err, "error while decoding cassette file")
+	}
+	if file.Version != cassetteVersion {
+		return errors.New("unsupported cassette version " + strconv.Itoa(file.Version))
+	}
+	for i, interaction := range file.Interactions {
+		for _, encoding := range []string{interaction.Request.BodyEncoding, interaction.Response.BodyEncoding} {
+			if encoding != "" && encoding != CassetteBodyBase64 {
+				return errors.New("unsupported body encoding '" + encoding + "' of interaction " + strconv.Itoa(i))
+			}
+		}
+	}
+	c.interactions = file.Interactions
+	c.used = make([]bool, len(file.Interactions))
+	return nil
+}
+
+//save writes all interactions recorded so far, it has to be called with the lock held
+func (c *cassette) save() error {
+	data, err := json.MarshalIndent(cassetteFile{Version: cassetteVersion, Interactions: c.interactions}, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "error during marshal")
+	}
+	if err = ioutil.WriteFile(c.path, data, 0644); err != nil {
+		return errors.Wrap(err, "error while writing cassette file")
+	}
+	return nil
+}
+
+//redact returns a copy of the headers with the values of all redacted headers replaced
+func (c *cassette) redact(header http.Header) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+	result := header.Clone()
+	for _, name := range c.redactHeaders {
+		if values, ok := result[http.CanonicalHeaderKey(name)]; ok {
+			for i := range values {
+				values[i] = redactedValue
+			}
+		}
+	}
+	return result
+}
+
+//transport wraps the given round tripper, requests are matched relative to the given base url
+func (c *cassette) transport(next http.RoundTripper, baseUrl string) (http.RoundTripper, error) {
+	base, err := url.Parse(baseUrl)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid base url")
+	}
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &cassetteTransport{cassette: c, next: next, basePath: base.EscapedPath()}, nil
+}
+
+//cassetteTransport records or replays the requests sent through it
+type cassetteTransport struct {
+	cassette *cassette
+	next     http.RoundTripper
+	basePath string
+}
+
+func (t *cassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, errors.Wrap(err, "error while reading request body")
+		}
+	}
+	recorded := CassetteRequest{
+		Method: req.Method,
+		Path:   strings.TrimPrefix(req.URL.EscapedPath(), t.basePath),
+		Query:  req.URL.Query(),
+	}
+	recorded.Body, recorded.BodyEncoding = encodeCassetteBody(body)
+	if len(recorded.Query) == 0 {
+		recorded.Query = nil
+	}
+
+	if t.cassette.mode == CassetteReplay {
+		return t.replay(req, recorded)
+	}
+	return t.record(req, recorded, body)
+}
+
+func (t *cassetteTransport) replay(req *http.Request, recorded CassetteRequest) (*http.Response, error) {
+	c := t.cassette
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for i, interaction := range c.interactions {
+		if c.used[i] || !interaction.Request.matches(recorded) {
+			continue
+		}
+		body, err := decodeCassetteBody(interaction.Response.Body, interaction.Response.BodyEncoding)
+		if err != nil {
+			return nil, err
+		}
+		c.used[i] = true
+		header := interaction.Response.Headers.Clone()
+		if header == nil {
+			header = make(http.Header)
+		}
+		return &http.Response{
+			Status:        strconv.Itoa(interaction.Response.StatusCode) + " " + http.StatusText(interaction.Response.StatusCode),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          ioutil.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, &CassetteMissError{Method: recorded.Method, Path: recorded.Path}
+}
+
+func (t *cassetteTransport) record(req *http.Request, recorded CassetteRequest, body []byte) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	response, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	responseBody, err := ioutil.ReadAll(response.Body)
+	_ = response.Body.Close()
+	if err != nil {
+		return nil, errors.Wrap(err, "error while reading response body")
+	}
+	response.Body = ioutil.NopCloser(bytes.NewReader(responseBody))
+
+	c := t.cassette
+	recorded.Headers = c.redact(req.Header)
+	recordedResponse := CassetteResponse{
+		StatusCode: response.StatusCode,
+		Headers:    c.redact(response.Header),
+	}
+	recordedResponse.Body, recordedResponse.BodyEncoding = encodeCassetteBody(responseBody)
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.interactions = append(c.interactions, CassetteInteraction{Request: recorded, Response: recordedResponse})
+	if err = c.save(); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+//matches checks whether two requests have the same method, path, query and body
+func (r CassetteRequest) matches(other CassetteRequest) bool {
+	return r.Method == other.Method && r.Path == other.Path && r.Query.Encode() == other.Query.Encode() &&
+		r.Body == other.Body && r.BodyEncoding == other.BodyEncoding
+}
+
+//encodeCassetteBody returns the body as text, bodies which are no valid UTF-8 are base64 encoded
+func encodeCassetteBody(body []byte) (string, string) {
+	if utf8.Valid(body) {
+		return string(body), ""
+	}
+	return base64.StdEncoding.EncodeToString(body), CassetteBodyBase64
+}
+
+//decodeCassetteBody returns the original bytes of a recorded body
+func decodeCassetteBody(body, encoding string) ([]byte, error) {
+	if encoding != CassetteBodyBase64 {
+		return []byte(body), nil
+	}
+	b, err := base64.StdEncoding.DecodeString(body)
+	if err != nil {
+		return nil, errors.Wrap(err, "error while decoding recorded body")
+	}
+	return b, nil
+}
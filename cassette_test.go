@@ -0,0 +1,122 @@
+package snmpsimclient
+
+import (
+	"bytes"
+	"context"
+	"github.com/inexio/snmpsim-restapi-go-client/snmpsimtest"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestManagementClient_Cassette(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snmpsim-cassette")
+	if !assert.NoError(t, err, "error while creating temp dir") {
+		return
+	}
+	defer os.RemoveAll(dir)
+	cassettePath := filepath.Join(dir, "cassette.json")
+
+	server := snmpsimtest.NewServer()
+	server.SetBasicAuth("user", "secret-password")
+	serverUrl := server.URL
+
+	client, err := NewManagementClient(serverUrl, WithCassette(cassettePath, CassetteRecord))
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+	assert.NoError(t, client.SetUsernameAndPassword("user", "secret-password"))
+	lab, err := client.CreateLab("cassette lab")
+	if !assert.NoError(t, err, "error while creating lab") {
+		return
+	}
+	_, err = client.GetLabs(map[string]string{"name": "cassette lab"})
+	assert.NoError(t, err, "error while getting labs")
+	_, err = client.GetLab(lab.Id + 1)
+	assert.True(t, errors.Is(err, ErrNotFound), "error does not match ErrNotFound")
+	server.Close()
+
+	content, err := ioutil.ReadFile(cassettePath)
+	if !assert.NoError(t, err, "error while reading cassette") {
+		return
+	}
+	assert.False(t, strings.Contains(string(content), "Basic "), "credentials were not redacted")
+	assert.True(t, strings.Contains(string(content), redactedValue), "authorization header was not recorded")
+
+	client, err = NewManagementClient(serverUrl, WithCassette(cassettePath, CassetteReplay))
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+	replayed, err := client.CreateLab("cassette lab")
+	if assert.NoError(t, err, "error while replaying create lab") {
+		assert.Equal(t, lab, replayed)
+	}
+	labs, err := client.GetLabs(map[string]string{"name": "cassette lab"})
+	if assert.NoError(t, err, "error while replaying get labs") {
+		assert.Len(t, labs, 1)
+	}
+	_, err = client.GetLab(lab.Id + 1)
+	assert.True(t, errors.Is(err, ErrNotFound), "replayed error does not match ErrNotFound")
+
+	_, err = client.GetLabs(nil)
+	var missErr *CassetteMissError
+	assert.True(t, errors.As(err, &missErr), "unrecorded request does not return a CassetteMissError")
+
+	_, err = NewManagementClient(serverUrl, WithCassette(filepath.Join(dir, "missing.json"), CassetteReplay))
+	assert.Error(t, err, "replaying a missing cassette does not fail")
+}
+
+func TestManagementClient_CassetteRedactHeaders(t *testing.T) {
+	cassettePath := filepath.Join(t.TempDir(), "cassette.json")
+	server := snmpsimtest.NewServer()
+	defer server.Close()
+	server.SetBasicAuth("user", "secret-password")
+
+	client, err := NewManagementClient(server.URL, WithCassette(cassettePath, CassetteRecord, "X-Api-Key"))
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+	assert.NoError(t, client.SetUsernameAndPassword("user", "secret-password"))
+	client.resty.SetHeader("X-Api-Key", "secret-api-key")
+	_, err = client.GetLabs(nil)
+	assert.NoError(t, err, "error while getting labs")
+
+	content, err := ioutil.ReadFile(cassettePath)
+	if !assert.NoError(t, err, "error while reading cassette") {
+		return
+	}
+	assert.False(t, strings.Contains(string(content), "Basic "), "credentials were not redacted")
+	assert.False(t, strings.Contains(string(content), "secret-api-key"), "custom header was not redacted")
+}
+
+func TestManagementClient_CassetteBinaryBody(t *testing.T) {
+	cassettePath := filepath.Join(t.TempDir(), "cassette.json")
+	server := snmpsimtest.NewServer()
+	serverUrl := server.URL
+	content := []byte{0x42, 0x5a, 0x68, 0x39, 0xff, 0xfe, 0x00, 0x80, 0x81}
+	server.AddRecording("lab/public.snmprec.bz2", content)
+
+	client, err := NewManagementClient(serverUrl, WithCassette(cassettePath, CassetteRecord))
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+	ctx := context.Background()
+	assert.NoError(t, client.UploadRecording(ctx, "lab/private.snmprec.bz2", bytes.NewReader(content)), "error while uploading recording")
+	var recorded bytes.Buffer
+	assert.NoError(t, client.DownloadRecording(ctx, "lab/public.snmprec.bz2", &recorded), "error while downloading recording")
+	assert.Equal(t, content, recorded.Bytes())
+	server.Close()
+
+	client, err = NewManagementClient(serverUrl, WithCassette(cassettePath, CassetteReplay))
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+	assert.NoError(t, client.UploadRecording(ctx, "lab/private.snmprec.bz2", bytes.NewReader(content)), "error while replaying upload")
+	var replayed bytes.Buffer
+	assert.NoError(t, client.DownloadRecording(ctx, "lab/public.snmprec.bz2", &replayed), "error while replaying download")
+	assert.Equal(t, content, replayed.Bytes(), "replayed body differs from the recorded one")
+}
@@ -1,6 +1,7 @@
 package snmpsimclient
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/go-resty/resty/v2"
 	"github.com/pkg/errors"
@@ -25,8 +26,19 @@ type clientData struct {
 	username string
 	password string
 
-	resty   *resty.Client
-	useAuth bool
+	resty       *resty.Client
+	useAuth     bool
+	retryPolicy RetryPolicy
+	telemetry   *telemetry
+
+	validateRecordFiles bool
+}
+
+//apiResponse is a response of the api together with the requested path and the number of attempts it took to receive it
+type apiResponse struct {
+	*resty.Response
+	path     string
+	attempts int
 }
 
 /*
@@ -38,6 +50,55 @@ func (m *NotValidError) Error() string {
 	return "client was not created properly with the func New...Client(baseUrl string)"
 }
 
+//newClientData validates the base url and creates the shared client data with the given options applied
+func newClientData(baseUrl string, opts []ClientOption) (*clientData, error) {
+	if baseUrl == "" {
+		return nil, errors.New("invalid base url")
+	}
+
+	//if baseUrl does not end with an "/" it has to be added to the string
+	if lastChar := baseUrl[len(baseUrl)-1:]; lastChar != "/" {
+		baseUrl += "/"
+	}
+
+	var options clientOptions
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if err := opt(&options); err != nil {
+			return nil, errors.Wrap(err, "invalid client option")
+		}
+	}
+	restyClient, err := options.newResty()
+	if err != nil {
+		return nil, errors.Wrap(err, "error while creating http client")
+	}
+	if options.cassette != nil {
+		transport, err := options.cassette.transport(restyClient.GetClient().Transport, baseUrl)
+		if err != nil {
+			return nil, errors.Wrap(err, "error while creating cassette transport")
+		}
+		restyClient.SetTransport(transport)
+	}
+	retryPolicy := DefaultRetryPolicy()
+	if options.retryPolicy != nil {
+		retryPolicy = *options.retryPolicy
+	}
+	telemetry, err := newTelemetry(options.tracerProvider, options.meterProvider)
+	if err != nil {
+		return nil, errors.Wrap(err, "error while creating telemetry")
+	}
+	return &clientData{
+		baseUrl:             baseUrl,
+		resty:               restyClient,
+		useAuth:             false,
+		retryPolicy:         retryPolicy,
+		telemetry:           telemetry,
+		validateRecordFiles: options.validateRecordFiles,
+	}, nil
+}
+
 //isValid checks if the client object is valid
 func (c *client) isValid() bool {
 	return c.clientData != nil
@@ -62,8 +123,29 @@ func (c *client) SetUsernameAndPassword(username, password string) error {
 	return nil
 }
 
-func (c *client) request(method string, path string, body string, header, queryParams map[string]string) (*resty.Response, error) {
+func (c *client) request(ctx context.Context, method string, path string, body string, header, queryParams map[string]string) (*apiResponse, error) {
+	return c.send(ctx, method, path, header, queryParams, func(request *resty.Request) {
+		if body != "" {
+			request.SetBody(body)
+		}
+	}, true)
+}
+
+//send sends a request to the api. prepare sets the body and further settings of the request,
+//a request which is not retryable, e.g. because its body is a stream, is sent exactly once.
+func (c *client) send(ctx context.Context, method string, path string, header, queryParams map[string]string, prepare func(*resty.Request), retryable bool) (*apiResponse, error) {
+	if ctx == nil {
+		return nil, errors.New("invalid context")
+	}
+	switch method {
+	case "GET", "POST", "PUT", "DELETE":
+	default:
+		return nil, errors.New("invalid http method: " + method)
+	}
+
+	ctx, requestTelemetry := c.telemetry.start(ctx, method, path)
 	request := c.resty.R()
+	request.SetContext(ctx)
 	request.SetHeader("Content-Type", "application/json")
 
 	if header != nil {
@@ -74,64 +156,124 @@ func (c *client) request(method string, path string, body string, header, queryP
 		request.SetQueryParams(queryParams)
 	}
 
-	if body != "" {
-		request.SetBody(body)
-	}
+	prepare(request)
 
 	if c.useAuth {
 		request.SetBasicAuth(c.username, c.password)
 	}
 
 	var response *resty.Response
-	response = nil
-
 	var err error
-	err = nil
-
-	switch method {
-	case "GET":
-		response, err = request.Get(c.baseUrl + urlEscapePath(path))
-	case "POST":
-		response, err = request.Post(c.baseUrl + urlEscapePath(path))
-	case "PUT":
-		response, err = request.Put(c.baseUrl + urlEscapePath(path))
-	case "DELETE":
-		response, err = request.Delete(c.baseUrl + urlEscapePath(path))
-	default:
-		return nil, errors.New("invalid http method: " + method)
+	attempts := 0
+	for {
+		attempts++
+		response, err = request.Execute(method, c.baseUrl+urlEscapePath(path))
+		if !retryable || !c.retryPolicy.retry(method, attempts, response, err) || ctx.Err() != nil {
+			break
+		}
+		if c.retryPolicy.wait(ctx, attempts) != nil {
+			break
+		}
 	}
 	if err != nil {
-		return nil, errors.Wrap(err, "error during http request")
+		err = &RequestError{Method: method, Path: path, Attempts: attempts, Err: err}
+		requestTelemetry.end(ctx, 0, attempts, err)
+		return nil, err
 	}
-	return response, nil
+	requestTelemetry.end(ctx, response.StatusCode(), attempts, nil)
+	return &apiResponse{Response: response, path: path, attempts: attempts}, nil
 }
 
 //Http error handling
 
+var (
+	//ErrValidation is matched by http errors with the status code 400 or 422.
+	ErrValidation = errors.New("validation failed")
+	//ErrUnauthorized is matched by http errors with the status code 401.
+	ErrUnauthorized = errors.New("unauthorized")
+	//ErrForbidden is matched by http errors with the status code 403.
+	ErrForbidden = errors.New("forbidden")
+	//ErrNotFound is matched by http errors with the status code 404.
+	ErrNotFound = errors.New("not found")
+	//ErrConflict is matched by http errors with the status code 409, e.g. when a recording already exists.
+	ErrConflict = errors.New("conflict")
+	//ErrAlreadyExists is matched by http errors which report that an object already exists or is already linked or
+	//tagged. The api reports these with the status code 400, which also matches ErrValidation, recordings which already
+	//exist are reported with 409.
+	ErrAlreadyExists = errors.New("already exists")
+	//ErrServer is matched by http errors with a 5xx status code.
+	ErrServer = errors.New("server error")
+)
+
 /*
 HttpError represents an http error returned by the api.
+It can be matched against the sentinel errors ErrValidation, ErrUnauthorized, ErrForbidden, ErrNotFound, ErrConflict,
+ErrAlreadyExists and ErrServer using errors.Is.
 */
 type HttpError struct {
 	StatusCode int
 	Status     string
 	Body       *ErrorResponse
+	//Attempts is the number of times the request was sent
+	Attempts int
+	//Method and Path identify the failed request, the path is relative to the base url
+	Method string
+	Path   string
+	//Response is the original http response
+	Response *resty.Response
 }
 
 func (h HttpError) Error() string {
 	msg := "http error: status code: " + strconv.Itoa(h.StatusCode) + " // status: " + h.Status
+	if h.Method != "" {
+		msg += " // request: " + h.Method + " " + h.Path
+	}
 	if h.Body != nil {
 		msg += " // message: " + h.Body.Message
 	}
 	return msg
 }
 
-func getHttpError(response *resty.Response) error {
-	httpError := HttpError{
+/*
+Is reports whether the http error matches the given sentinel error.
+*/
+func (h HttpError) Is(target error) bool {
+	switch target {
+	case ErrValidation:
+		return h.StatusCode == 400 || h.StatusCode == 422
+	case ErrUnauthorized:
+		return h.StatusCode == 401
+	case ErrForbidden:
+		return h.StatusCode == 403
+	case ErrNotFound:
+		return h.StatusCode == 404
+	case ErrConflict:
+		return h.StatusCode == 409
+	case ErrAlreadyExists:
+		//duplicates are rejected like invalid requests, they are only told apart by the message of the api
+		return h.StatusCode == 409 || (h.StatusCode == 400 && h.Body != nil && strings.Contains(h.Body.Message, " already "))
+	case ErrServer:
+		return h.StatusCode >= 500 && h.StatusCode <= 599
+	}
+	return false
+}
+
+func getHttpError(response *apiResponse) error {
+	return getHttpErrorWithBody(response, response.Body())
+}
+
+//getHttpErrorWithBody creates the http error of a response whose body was not read by resty, e.g. a streamed response
+func getHttpErrorWithBody(response *apiResponse, body []byte) error {
+	httpError := &HttpError{
 		StatusCode: response.StatusCode(),
 		Status:     response.Status(),
+		Attempts:   response.attempts,
+		Method:     response.Request.Method,
+		Path:       response.path,
+		Response:   response.Response,
 	}
 	var errorResponse ErrorResponse
-	err := json.Unmarshal(response.Body(), &errorResponse)
+	err := json.Unmarshal(body, &errorResponse)
 	if err != nil {
 		return httpError
 	}
@@ -0,0 +1,332 @@
+package snmpsimclient
+
+import (
+	"context"
+	"crypto/x509"
+	"github.com/inexio/snmpsim-restapi-go-client/snmprec"
+	"github.com/inexio/snmpsim-restapi-go-client/snmpsimtest"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestManagementClient_Context(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client, err := NewManagementClient(server.URL)
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+
+	//Deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = client.SetLabPowerContext(ctx, 1, true)
+	if assert.Error(t, err, "no error returned after the deadline was exceeded") {
+		assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+	}
+
+	//Cancellation
+	ctx, cancel = context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+	_, err = client.GetLabsContext(ctx, nil)
+	if assert.Error(t, err, "no error returned after the context was cancelled") {
+		assert.Equal(t, context.Canceled, ctx.Err())
+	}
+}
+
+func TestMetricsClient_Context(t *testing.T) {
+	client, err := NewMetricsClient("http://127.0.0.1:1")
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.GetProcessConsolePagesContext(ctx, 1)
+	assert.Error(t, err, "no error returned for an already cancelled context")
+}
+
+func TestManagementClient_Options(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/snmpsim/mgmt/v1/labs/1/power/on" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		_, _ = w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	//Untrusted certificate
+	client, err := NewManagementClient(server.URL)
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+	_, err = client.GetLabs(nil)
+	assert.Error(t, err, "no error returned for an untrusted server certificate")
+
+	//Trusted root ca
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	client, err = NewManagementClient(server.URL, WithRootCAs(pool), WithTimeout(100*time.Millisecond))
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+	_, err = client.GetLabs(nil)
+	assert.NoError(t, err, "error while getting labs with a trusted root ca")
+	err = client.SetLabPower(1, true)
+	assert.Error(t, err, "no error returned after the timeout was exceeded")
+
+	//Insecure
+	client, err = NewManagementClient(server.URL, WithInsecureSkipVerify())
+	if assert.NoError(t, err, "error while creating a new api client") {
+		_, err = client.GetLabs(nil)
+		assert.NoError(t, err, "error while getting labs with insecure skip verify")
+	}
+
+	//Custom http client
+	client, err = NewManagementClient(server.URL, WithHTTPClient(server.Client()))
+	if assert.NoError(t, err, "error while creating a new api client") {
+		_, err = client.GetLabs(nil)
+		assert.NoError(t, err, "error while getting labs with a custom http client")
+	}
+
+	//Invalid options
+	_, err = NewManagementClient(server.URL, WithProxy("no-proxy"))
+	assert.Error(t, err, "no error returned for an invalid proxy url")
+	_, err = NewManagementClient(server.URL, WithTransport(roundTripperFunc(http.DefaultTransport.RoundTrip)), WithInsecureSkipVerify())
+	assert.Error(t, err, "no error returned for tls options with a custom round tripper")
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestManagementClient_Retry(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1)%3 != 0 {
+			w.WriteHeader(503)
+			return
+		}
+		if r.Method == "POST" {
+			w.WriteHeader(201)
+			_, _ = w.Write([]byte("{}"))
+			return
+		}
+		_, _ = w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	client, err := NewManagementClient(server.URL, WithRetryPolicy(policy))
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+
+	//GET is retried until it succeeds
+	_, err = client.GetLabs(nil)
+	assert.NoError(t, err, "error while getting labs")
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests), "unexpected number of requests")
+
+	//POST is not retried
+	atomic.StoreInt32(&requests, 0)
+	_, err = client.CreateLab("lab")
+	if assert.Error(t, err, "no error returned for a failed POST request") {
+		var httpErr *HttpError
+		if assert.True(t, errors.As(err, &httpErr), "unexpected error type") {
+			assert.Equal(t, 503, httpErr.StatusCode)
+			assert.Equal(t, 1, httpErr.Attempts)
+		}
+	}
+
+	//Attempts are limited
+	atomic.StoreInt32(&requests, 0)
+	policy.MaxAttempts = 2
+	client, err = NewManagementClient(server.URL, WithRetryPolicy(policy))
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+	_, err = client.GetLabs(nil)
+	if assert.Error(t, err, "no error returned after the max attempts were reached") {
+		var httpErr *HttpError
+		if assert.True(t, errors.As(err, &httpErr), "unexpected error type") {
+			assert.Equal(t, 2, httpErr.Attempts)
+		}
+	}
+
+	//Connection errors are retried
+	client, err = NewManagementClient("http://127.0.0.1:1", WithRetryPolicy(policy))
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+	_, err = client.GetLabs(nil)
+	if assert.Error(t, err, "no error returned for an unreachable server") {
+		var requestErr *RequestError
+		if assert.True(t, errors.As(err, &requestErr), "unexpected error type") {
+			assert.Equal(t, 2, requestErr.Attempts)
+		}
+	}
+}
+
+func TestManagementClient_HttpErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/snmpsim/mgmt/v1/labs/1":
+			w.WriteHeader(404)
+			_, _ = w.Write([]byte(`{"message": "lab not found", "status": 404}`))
+		case "/snmpsim/mgmt/v1/labs":
+			w.WriteHeader(409)
+		default:
+			w.WriteHeader(401)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewManagementClient(server.URL)
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+
+	_, err = client.GetLab(1)
+	if assert.Error(t, err, "no error returned for a missing lab") {
+		assert.True(t, errors.Is(err, ErrNotFound), "error does not match ErrNotFound")
+		assert.False(t, errors.Is(err, ErrConflict), "error matches ErrConflict")
+		var httpErr *HttpError
+		if assert.True(t, errors.As(err, &httpErr), "error is not a http error") {
+			assert.Equal(t, "GET", httpErr.Method)
+			assert.Equal(t, "snmpsim/mgmt/v1/labs/1", httpErr.Path)
+			if assert.NotNil(t, httpErr.Body) {
+				assert.Equal(t, "lab not found", httpErr.Body.Message)
+			}
+			if assert.NotNil(t, httpErr.Response) {
+				assert.Equal(t, 404, httpErr.Response.StatusCode())
+			}
+		}
+	}
+
+	_, err = client.CreateLab("lab")
+	assert.True(t, errors.Is(err, ErrConflict), "error does not match ErrConflict")
+
+	err = client.DeleteLab(2)
+	assert.True(t, errors.Is(err, ErrUnauthorized), "error does not match ErrUnauthorized")
+}
+
+func TestManagementClient_ErrAlreadyExists(t *testing.T) {
+	server := snmpsimtest.NewServer()
+	defer server.Close()
+	client, err := NewManagementClient(server.URL)
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+
+	lab, err := client.CreateLab("lab")
+	assert.NoError(t, err, "error while creating lab")
+	agent, err := client.CreateAgent("agent", "agent")
+	assert.NoError(t, err, "error while creating agent")
+	tag, err := client.CreateTag("tag", "")
+	assert.NoError(t, err, "error while creating tag")
+	_, err = client.CreateUser("user", "user", "", "", "", "")
+	assert.NoError(t, err, "error while creating user")
+	assert.NoError(t, client.AddAgentToLab(lab.Id, agent.Id), "error while adding agent to lab")
+	assert.NoError(t, client.AddTagToLab(lab.Id, tag.Id), "error while tagging lab")
+	content := "1.3.6.1.2.1.1.5.0|4|router\n"
+	assert.NoError(t, client.UploadRecordFileString(&content, "lab/public.snmprec"), "error while uploading record file")
+
+	_, err = client.CreateUser("user", "user", "", "", "", "")
+	assert.True(t, errors.Is(err, ErrAlreadyExists), "duplicate user does not match ErrAlreadyExists: %v", err)
+	assert.True(t, errors.Is(err, ErrValidation), "duplicate user does not match ErrValidation")
+	_, err = client.CreateTag("tag", "")
+	assert.True(t, errors.Is(err, ErrAlreadyExists), "duplicate tag does not match ErrAlreadyExists: %v", err)
+	err = client.AddAgentToLab(lab.Id, agent.Id)
+	assert.True(t, errors.Is(err, ErrAlreadyExists), "existing link does not match ErrAlreadyExists: %v", err)
+	err = client.AddTagToLab(lab.Id, tag.Id)
+	assert.True(t, errors.Is(err, ErrAlreadyExists), "existing tag does not match ErrAlreadyExists: %v", err)
+	err = client.UploadRecordFileString(&content, "lab/public.snmprec")
+	assert.True(t, errors.Is(err, ErrAlreadyExists), "existing recording does not match ErrAlreadyExists: %v", err)
+
+	//other invalid requests and missing objects do not match
+	err = client.AddAgentToLab(lab.Id, agent.Id+1)
+	assert.False(t, errors.Is(err, ErrAlreadyExists), "missing agent matches ErrAlreadyExists")
+	_, err = client.CreateEndpoint("endpoint", "invalid", "")
+	if assert.True(t, errors.Is(err, ErrValidation), "invalid endpoint does not match ErrValidation: %v", err) {
+		assert.False(t, errors.Is(err, ErrAlreadyExists), "invalid endpoint matches ErrAlreadyExists")
+	}
+}
+
+func TestManagementClient_UpdateRequest(t *testing.T) {
+	var method, path, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := ioutil.ReadAll(r.Body)
+		method, path, body = r.Method, r.URL.Path, string(data)
+		_, _ = w.Write([]byte(`{"id": 1, "name": "user", "auth_key": "newkey", "auth_proto": "md5"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewManagementClient(server.URL)
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+
+	user, err := client.UpdateUser(1, UserUpdate{AuthKey: String("newkey")})
+	if assert.NoError(t, err, "error while updating user") {
+		assert.Equal(t, "PUT", method)
+		assert.Equal(t, "/snmpsim/mgmt/v1/users/1", path)
+		assert.JSONEq(t, `{"auth_key": "newkey"}`, body)
+		assert.Equal(t, "newkey", user.AuthKey)
+		assert.Equal(t, "md5", user.AuthProto)
+	}
+
+	_, err = client.UpdateEndpoint(2, EndpointUpdate{Address: String("127.0.0.1:1162"), Protocol: String("")})
+	if assert.NoError(t, err, "error while updating endpoint") {
+		assert.Equal(t, "/snmpsim/mgmt/v1/endpoints/2", path)
+		assert.JSONEq(t, `{"address": "127.0.0.1:1162", "protocol": ""}`, body)
+	}
+}
+
+func TestManagementClient_RecordFileValidation(t *testing.T) {
+	server := snmpsimtest.NewServer()
+	defer server.Close()
+
+	client, err := NewManagementClient(server.URL, WithRecordFileValidation())
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+	valid := "1.3.6.1.2.1.1.1.0|4|test\n1.3.6.1.2.1.1.3.0|67|100\n"
+	assert.NoError(t, client.UploadRecordFileString(&valid, "valid.snmprec"))
+	_, ok := server.Recording("valid.snmprec")
+	assert.True(t, ok, "valid record file was not uploaded")
+
+	invalid := "1.3.6.1.2.1.1.3.0|67|-1\n1.3.6.1.2.1.1.1.0|4|unsorted\n"
+	err = client.UploadRecordFileString(&invalid, "invalid.snmprec")
+	var validationErr *snmprec.ValidationError
+	if assert.True(t, errors.As(err, &validationErr)) {
+		assert.Len(t, validationErr.Issues, 2)
+	}
+	_, ok = server.Recording("invalid.snmprec")
+	assert.False(t, ok, "invalid record file was uploaded")
+
+	//without the option the record file is uploaded as it is
+	client, err = NewManagementClient(server.URL)
+	if assert.NoError(t, err, "error while creating a new api client") {
+		assert.NoError(t, client.UploadRecordFileString(&invalid, "invalid.snmprec"))
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Snmpsim-exporter serves the metrics of a snmpsim metrics api in the prometheus format.
+
+Usage:
+
+	snmpsim-exporter -url http://127.0.0.1:8001 -listen :9786 -packet-labels local_address,protocol
+
+The metrics are served on /metrics, the username and password of the api can be given with the environment variables
+SNMPSIM_USERNAME and SNMPSIM_PASSWORD.
+*/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/inexio/snmpsim-restapi-go-client"
+	"github.com/inexio/snmpsim-restapi-go-client/snmpsimprom"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+func main() {
+	url := flag.String("url", "http://127.0.0.1:8001", "base url of the snmpsim metrics api")
+	listen := flag.String("listen", ":9786", "address the metrics are served on")
+	packetLabels := flag.String("packet-labels", "", "comma separated packet filters which are exported as labels, e.g. local_address,protocol. "+
+		"Every combination of their values is requested separately on each scrape, so avoid filters with many values like peer_address")
+	messageLabels := flag.String("message-labels", "", "comma separated message filters which are exported as labels, e.g. local_address,recording. "+
+		"Every combination of their values is requested separately on each scrape")
+	timeout := flag.Duration("timeout", snmpsimprom.DefaultTimeout, "timeout of a scrape")
+	maxCombinations := flag.Int("max-combinations", snmpsimprom.DefaultMaxCombinations, "maximum number of combinations of the values of the packet labels and of the message labels, scrapes with more combinations fail")
+	insecure := flag.Bool("insecure", false, "skip the verification of the tls certificate of the api")
+	flag.Parse()
+
+	var opts []snmpsimclient.ClientOption
+	if *insecure {
+		opts = append(opts, snmpsimclient.WithInsecureSkipVerify())
+	}
+	client, err := snmpsimclient.NewMetricsClient(*url, opts...)
+	if err != nil {
+		exit("error while creating the metrics client:", err)
+	}
+	if username := os.Getenv("SNMPSIM_USERNAME"); username != "" {
+		if err = client.SetUsernameAndPassword(username, os.Getenv("SNMPSIM_PASSWORD")); err != nil {
+			exit("error while setting username and password:", err)
+		}
+	}
+
+	collector, err := snmpsimprom.NewCollector(client, snmpsimprom.Options{
+		PacketLabels:  splitLabels(*packetLabels),
+		MessageLabels: splitLabels(*messageLabels),
+		Timeout:         *timeout,
+		MaxCombinations: *maxCombinations,
+	})
+	if err != nil {
+		exit("error while creating the collector:", err)
+	}
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	server := &http.Server{Addr: *listen, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	fmt.Println("serving metrics of", *url, "on", *listen+"/metrics")
+	if err = server.ListenAndServe(); err != nil {
+		exit("error while serving metrics:", err)
+	}
+}
+
+//splitLabels splits a comma separated list of labels, an empty list results in no labels
+func splitLabels(list string) []string {
+	var labels []string
+	for _, label := range strings.Split(list, ",") {
+		if label = strings.TrimSpace(label); label != "" {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+func exit(msg string, err error) {
+	fmt.Fprintln(os.Stderr, msg, err)
+	os.Exit(1)
+}
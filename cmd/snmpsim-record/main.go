@@ -0,0 +1,123 @@
+/*
+Snmpsim-record records the data of a SNMP agent into a snmprec file and optionally uploads it to the data root of snmpsim.
+
+Usage:
+
+	snmpsim-record -target 192.0.2.1 -community public -subtrees 1.3.6.1.2.1,1.3.6.1.4.1 > device.snmprec
+	snmpsim-record -target 192.0.2.1 -url http://127.0.0.1:8000 -remote-path lab/device.snmprec
+
+The username and password of the management api can be given with the environment variables SNMPSIM_USERNAME and
+SNMPSIM_PASSWORD, the passphrases of SNMP v3 with SNMP_AUTH_PASSPHRASE and SNMP_PRIV_PASSPHRASE.
+*/
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/inexio/snmpsim-restapi-go-client"
+	"github.com/inexio/snmpsim-restapi-go-client/snmpsimrecorder"
+	"github.com/soniah/gosnmp"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"strings"
+)
+
+var authProtocols = map[string]gosnmp.SnmpV3AuthProtocol{"": gosnmp.NoAuth, "MD5": gosnmp.MD5, "SHA": gosnmp.SHA}
+
+var privProtocols = map[string]gosnmp.SnmpV3PrivProtocol{"": gosnmp.NoPriv, "DES": gosnmp.DES, "AES": gosnmp.AES}
+
+func main() {
+	target := flag.String("target", "", "address of the agent, e.g. 192.0.2.1 or 192.0.2.1:1161")
+	version := flag.String("version", string(snmpsimrecorder.Version2c), "snmp version, 1, 2c or 3")
+	community := flag.String("community", "public", "community of snmp v1 and v2c")
+	subtrees := flag.String("subtrees", strings.Join(snmpsimrecorder.DefaultSubtrees, ","), "comma separated OIDs of the subtrees which are recorded")
+	bulkSize := flag.Uint("bulk-size", snmpsimrecorder.DefaultBulkSize, "max-repetitions of GETBULK requests")
+	retries := flag.Int("retries", 3, "number of retries of a request which timed out")
+	timeout := flag.Duration("timeout", snmpsimrecorder.DefaultTimeout, "timeout of a request")
+	user := flag.String("user", "", "user of snmp v3")
+	authProtocol := flag.String("auth-protocol", "", "authentication protocol of snmp v3, MD5 or SHA")
+	privProtocol := flag.String("priv-protocol", "", "privacy protocol of snmp v3, DES or AES")
+	contextName := flag.String("context", "", "context name of snmp v3")
+	output := flag.String("output", "-", "file the recording is written to, - writes it to stdout")
+	url := flag.String("url", "", "base url of the snmpsim management api the recording is uploaded to")
+	remotePath := flag.String("remote-path", "", "path of the uploaded recording inside of the data dir")
+	insecure := flag.Bool("insecure", false, "skip the verification of the tls certificate of the api")
+	flag.Parse()
+
+	auth, ok := authProtocols[strings.ToUpper(*authProtocol)]
+	if !ok {
+		exit("invalid auth protocol:", fmt.Errorf("%q", *authProtocol))
+	}
+	priv, ok := privProtocols[strings.ToUpper(*privProtocol)]
+	if !ok {
+		exit("invalid priv protocol:", fmt.Errorf("%q", *privProtocol))
+	}
+	if *bulkSize > 255 {
+		exit("invalid bulk size:", fmt.Errorf("%d is larger than 255", *bulkSize))
+	}
+	recorder, err := snmpsimrecorder.NewRecorder(snmpsimrecorder.Options{
+		Target:    *target,
+		Version:   snmpsimrecorder.Version(*version),
+		Community: *community,
+		V3: snmpsimrecorder.V3Options{
+			User:           *user,
+			AuthProtocol:   auth,
+			AuthPassphrase: os.Getenv("SNMP_AUTH_PASSPHRASE"),
+			PrivProtocol:   priv,
+			PrivPassphrase: os.Getenv("SNMP_PRIV_PASSPHRASE"),
+			ContextName:    *contextName,
+		},
+		Subtrees: strings.Split(*subtrees, ","),
+		BulkSize: uint8(*bulkSize),
+		Retries:  *retries,
+		Timeout:  *timeout,
+	})
+	if err != nil {
+		exit("error while creating the recorder:", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	var result snmpsimrecorder.Result
+	if *url != "" {
+		var opts []snmpsimclient.ClientOption
+		if *insecure {
+			opts = append(opts, snmpsimclient.WithInsecureSkipVerify())
+		}
+		client, err := snmpsimclient.NewManagementClient(*url, opts...)
+		if err != nil {
+			exit("error while creating the management client:", err)
+		}
+		if username := os.Getenv("SNMPSIM_USERNAME"); username != "" {
+			if err = client.SetUsernameAndPassword(username, os.Getenv("SNMPSIM_PASSWORD")); err != nil {
+				exit("error while setting username and password:", err)
+			}
+		}
+		if result, err = recorder.Upload(ctx, client, *remotePath); err != nil {
+			exit("error while recording:", err)
+		}
+		fmt.Fprintln(os.Stderr, "uploaded", len(result.File.Records()), "records to", *remotePath)
+	} else {
+		if result, err = recorder.Record(ctx); err != nil {
+			exit("error while recording:", err)
+		}
+		if *output == "-" {
+			_, err = result.File.WriteTo(os.Stdout)
+		} else {
+			err = ioutil.WriteFile(*output, result.File.Bytes(), 0644)
+		}
+		if err != nil {
+			exit("error while writing the recording:", err)
+		}
+	}
+	for _, oid := range result.Skipped {
+		fmt.Fprintln(os.Stderr, "skipped", oid+": the type of the value is not supported by snmprec files")
+	}
+}
+
+func exit(msg string, err error) {
+	fmt.Fprintln(os.Stderr, msg, err)
+	os.Exit(1)
+}
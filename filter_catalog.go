@@ -0,0 +1,304 @@
+package snmpsimclient
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+DefaultFilterCatalogTTL is the time the filter catalog of a MetricsClient caches the filters of the api.
+*/
+const DefaultFilterCatalogTTL = time.Minute
+
+/*
+MetricsFilter is a filter offered by the metrics api together with all values that can be used for it, both sorted.
+*/
+type MetricsFilter struct {
+	Name   string
+	Values []string
+}
+
+/*
+FilterError is returned if a filter map contains a filter or a value which is not offered by the metrics api.
+It matches ErrValidation using errors.Is.
+*/
+type FilterError struct {
+	//Activity is either "packets" or "messages"
+	Activity string
+	Filter   string
+	//Value is empty if the filter itself is unknown
+	Value string
+	//Known contains the known filters, or the known values of the filter if Value is set
+	Known []string
+}
+
+/*
+Error returns the error message.
+*/
+func (e *FilterError) Error() string {
+	if e.Value == "" {
+		return "unknown " + e.Activity + " filter '" + e.Filter + "', known filters are: " + strings.Join(e.Known, ", ")
+	}
+	return "unknown value '" + e.Value + "' for " + e.Activity + " filter '" + e.Filter + "', known values are: " + strings.Join(e.Known, ", ")
+}
+
+/*
+Is makes the error match ErrValidation.
+*/
+func (e *FilterError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+/*
+FilterCatalog loads the packet and message filters of the metrics api together with their possible values and caches
+them for a fixed time, so filter maps can be validated before GetPackets or GetMessages is called.
+If a filter map contains a filter or value which is unknown to the cached filters, they are reloaded once before
+the filter map is rejected. It is safe for concurrent use.
+*/
+type FilterCatalog struct {
+	client *MetricsClient
+	ttl    time.Duration
+	//now returns the current time, it is replaced in tests
+	now func() time.Time
+
+	mtx      sync.Mutex
+	packets  *filterSet
+	messages *filterSet
+}
+
+//filterSet contains the cached filters of one activity
+type filterSet struct {
+	loaded time.Time
+	names  []string
+	//values contains the sorted values of all filters whose values have been loaded
+	values map[string][]string
+}
+
+/*
+NewFilterCatalog creates a new FilterCatalog which caches the filters of the given client for the given time.
+A ttl of 0 disables caching.
+*/
+func NewFilterCatalog(client *MetricsClient, ttl time.Duration) (*FilterCatalog, error) {
+	if client == nil {
+		return nil, errors.New("invalid client")
+	}
+	if ttl < 0 {
+		return nil, errors.New("invalid ttl")
+	}
+	return &FilterCatalog{client: client, ttl: ttl, now: time.Now}, nil
+}
+
+/*
+PacketFilters returns all packet filters and their possible values sorted by name.
+*/
+func (f *FilterCatalog) PacketFilters() ([]MetricsFilter, error) {
+	return f.PacketFiltersContext(context.Background())
+}
+
+/*
+PacketFiltersContext is like PacketFilters but carries the given context through to the http requests.
+*/
+func (f *FilterCatalog) PacketFiltersContext(ctx context.Context) ([]MetricsFilter, error) {
+	return f.filters(ctx, packetsPath)
+}
+
+/*
+MessageFilters returns all message filters and their possible values sorted by name.
+*/
+func (f *FilterCatalog) MessageFilters() ([]MetricsFilter, error) {
+	return f.MessageFiltersContext(context.Background())
+}
+
+/*
+MessageFiltersContext is like MessageFilters but carries the given context through to the http requests.
+*/
+func (f *FilterCatalog) MessageFiltersContext(ctx context.Context) ([]MetricsFilter, error) {
+	return f.filters(ctx, messagesPath)
+}
+
+/*
+ValidatePacketFilters checks that all keys of the filter map are packet filters and all values are possible values of them.
+The returned error is a *FilterError if a filter or a value is unknown.
+*/
+func (f *FilterCatalog) ValidatePacketFilters(filters map[string]string) error {
+	return f.ValidatePacketFiltersContext(context.Background(), filters)
+}
+
+/*
+ValidatePacketFiltersContext is like ValidatePacketFilters but carries the given context through to the http requests.
+*/
+func (f *FilterCatalog) ValidatePacketFiltersContext(ctx context.Context, filters map[string]string) error {
+	return f.validate(ctx, packetsPath, filters)
+}
+
+/*
+ValidateMessageFilters checks that all keys of the filter map are message filters and all values are possible values of them.
+The returned error is a *FilterError if a filter or a value is unknown.
+*/
+func (f *FilterCatalog) ValidateMessageFilters(filters map[string]string) error {
+	return f.ValidateMessageFiltersContext(context.Background(), filters)
+}
+
+/*
+ValidateMessageFiltersContext is like ValidateMessageFilters but carries the given context through to the http requests.
+*/
+func (f *FilterCatalog) ValidateMessageFiltersContext(ctx context.Context, filters map[string]string) error {
+	return f.validate(ctx, messagesPath, filters)
+}
+
+/*
+Invalidate removes all cached filters, they are loaded again on the next call.
+*/
+func (f *FilterCatalog) Invalidate() {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.packets = nil
+	f.messages = nil
+}
+
+//filters returns all filters of the activity with their values
+func (f *FilterCatalog) filters(ctx context.Context, activityPath string) ([]MetricsFilter, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	set, err := f.load(ctx, activityPath)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]MetricsFilter, 0, len(set.names))
+	for _, name := range set.names {
+		values, err := f.values(ctx, activityPath, set, name)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, MetricsFilter{Name: name, Values: append(make([]string, 0, len(values)), values...)})
+	}
+	return result, nil
+}
+
+//validate checks the keys and values of the filter map, cached filters are reloaded once if a filter or value is unknown
+func (f *FilterCatalog) validate(ctx context.Context, activityPath string, filters map[string]string) error {
+	if len(filters) == 0 {
+		return nil
+	}
+	if f == nil {
+		return &NotValidError{}
+	}
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	cached := *f.cached(activityPath)
+	set, err := f.load(ctx, activityPath)
+	if err != nil {
+		return err
+	}
+	err = f.check(ctx, activityPath, set, filters)
+	var filterErr *FilterError
+	if set != cached || !errors.As(err, &filterErr) {
+		return err
+	}
+
+	//the cached filters may be outdated, e.g. if a new peer address showed up since they were loaded
+	*f.cached(activityPath) = nil
+	if set, err = f.load(ctx, activityPath); err != nil {
+		return err
+	}
+	return f.check(ctx, activityPath, set, filters)
+}
+
+//check checks the filter map against the given filters of the activity, f.mtx has to be held
+func (f *FilterCatalog) check(ctx context.Context, activityPath string, set *filterSet, filters map[string]string) error {
+	//filters are checked in a fixed order, so the same map always results in the same error
+	var keys []string
+	for key := range filters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if !containsSorted(set.names, key) {
+			return &FilterError{Activity: activityName(activityPath), Filter: key, Known: append([]string(nil), set.names...)}
+		}
+		values, err := f.values(ctx, activityPath, set, key)
+		if err != nil {
+			return err
+		}
+		if !containsSorted(values, filters[key]) {
+			return &FilterError{Activity: activityName(activityPath), Filter: key, Value: filters[key], Known: append([]string(nil), values...)}
+		}
+	}
+	return nil
+}
+
+//cached returns the cache of the filters of the activity, f.mtx has to be held
+func (f *FilterCatalog) cached(activityPath string) **filterSet {
+	if activityPath == messagesPath {
+		return &f.messages
+	}
+	return &f.packets
+}
+
+//load returns the cached filters of the activity or loads them if they are missing or expired, f.mtx has to be held
+func (f *FilterCatalog) load(ctx context.Context, activityPath string) (*filterSet, error) {
+	cached := f.cached(activityPath)
+	if *cached != nil && f.now().Sub((*cached).loaded) < f.ttl {
+		return *cached, nil
+	}
+
+	filterMap, err := f.client.filterMap(ctx, activityPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "error while loading filters")
+	}
+	set := &filterSet{loaded: f.now(), values: make(map[string][]string)}
+	for name, value := range filterMap {
+		set.names = append(set.names, name)
+		//the api may list the possible values of a filter directly in the filter map
+		if list, ok := value.([]interface{}); ok {
+			values := make([]string, 0, len(list))
+			for _, v := range list {
+				if s, ok := v.(string); ok {
+					values = append(values, s)
+				}
+			}
+			sort.Strings(values)
+			set.values[name] = values
+		}
+	}
+	sort.Strings(set.names)
+	*cached = set
+	return set, nil
+}
+
+//values returns the values of the filter, they are requested from the api if they were not part of the filter map
+func (f *FilterCatalog) values(ctx context.Context, activityPath string, set *filterSet, name string) ([]string, error) {
+	if values, ok := set.values[name]; ok {
+		return values, nil
+	}
+	var values []string
+	err := f.client.do(ctx, apiCall{
+		method:         "GET",
+		path:           activityPath + "/filters/" + name,
+		description:    "get filter values",
+		expectedStatus: 200,
+		result:         &values,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error while loading values of filter '"+name+"'")
+	}
+	sort.Strings(values)
+	set.values[name] = values
+	return values, nil
+}
+
+//activityName returns the name of the activity with the given path
+func activityName(activityPath string) string {
+	return activityPath[strings.LastIndex(activityPath, "/")+1:]
+}
+
+//containsSorted checks whether the sorted slice contains the value
+func containsSorted(sorted []string, value string) bool {
+	i := sort.SearchStrings(sorted, value)
+	return i < len(sorted) && sorted[i] == value
+}
@@ -0,0 +1,123 @@
+package snmpsimclient
+
+import (
+	"errors"
+	"github.com/inexio/snmpsim-restapi-go-client/snmpsimtest"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFilterCatalog(t *testing.T) {
+	server := snmpsimtest.NewServer()
+	defer server.Close()
+	server.AddPacketActivity(snmpsimtest.PacketActivity{Labels: map[string]string{"local_address": "127.0.0.1:1162", "protocol": "udpv4"}, Total: 1})
+	server.AddPacketActivity(snmpsimtest.PacketActivity{Labels: map[string]string{"local_address": "127.0.0.1:1161", "protocol": "udpv4"}, Total: 1})
+
+	var requests int32
+	transport := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&requests, 1)
+		return http.DefaultTransport.RoundTrip(r)
+	})
+	client, err := NewMetricsClient(server.URL, WithTransport(transport))
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+	catalog, err := NewFilterCatalog(client, time.Minute)
+	if !assert.NoError(t, err) {
+		return
+	}
+	now := time.Now()
+	catalog.now = func() time.Time { return now }
+
+	filters, err := catalog.PacketFilters()
+	if assert.NoError(t, err) {
+		assert.Equal(t, []MetricsFilter{
+			{Name: "local_address", Values: []string{"127.0.0.1:1161", "127.0.0.1:1162"}},
+			{Name: "peer_address", Values: []string{}},
+			{Name: "protocol", Values: []string{"udpv4"}},
+		}, filters)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests), "filters and values have to be loaded with a single request")
+
+	assert.NoError(t, catalog.ValidatePacketFilters(map[string]string{"local_address": "127.0.0.1:1161", "protocol": "udpv4"}))
+	assert.NoError(t, catalog.ValidatePacketFilters(nil))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests), "cached filters were loaded again")
+
+	//unknown filters and values are checked against reloaded filters once before they are rejected
+	err = catalog.ValidatePacketFilters(map[string]string{"engine_id": "0102"})
+	var filterErr *FilterError
+	if assert.True(t, errors.As(err, &filterErr)) {
+		assert.Equal(t, "engine_id", filterErr.Filter)
+		assert.Empty(t, filterErr.Value)
+		assert.Equal(t, []string{"local_address", "peer_address", "protocol"}, filterErr.Known)
+	}
+	assert.True(t, errors.Is(err, ErrValidation))
+
+	err = catalog.ValidatePacketFilters(map[string]string{"local_address": "127.0.0.1:1163"})
+	if assert.True(t, errors.As(err, &filterErr)) {
+		assert.Equal(t, "127.0.0.1:1163", filterErr.Value)
+		assert.Contains(t, err.Error(), "127.0.0.1:1161, 127.0.0.1:1162")
+	}
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests), "filters were not reloaded once per unknown filter or value")
+
+	//new values are known without waiting for the ttl to expire
+	server.AddPacketActivity(snmpsimtest.PacketActivity{Labels: map[string]string{"local_address": "127.0.0.1:1163"}, Total: 1})
+	assert.NoError(t, catalog.ValidatePacketFilters(map[string]string{"local_address": "127.0.0.1:1163"}))
+	assert.Equal(t, int32(4), atomic.LoadInt32(&requests))
+	assert.NoError(t, catalog.ValidatePacketFilters(map[string]string{"local_address": "127.0.0.1:1163"}))
+	assert.Equal(t, int32(4), atomic.LoadInt32(&requests), "cached filters were loaded again")
+
+	//filters are loaded again after the ttl has expired or the catalog has been invalidated
+	now = now.Add(time.Minute)
+	assert.NoError(t, catalog.ValidatePacketFilters(map[string]string{"local_address": "127.0.0.1:1163"}))
+	assert.Equal(t, int32(5), atomic.LoadInt32(&requests))
+	catalog.Invalidate()
+	assert.NoError(t, catalog.ValidatePacketFilters(map[string]string{"local_address": "127.0.0.1:1163"}))
+	assert.Equal(t, int32(6), atomic.LoadInt32(&requests))
+
+	err = catalog.ValidateMessageFilters(map[string]string{"pdu_type": "GetRequestPDU"})
+	if assert.True(t, errors.As(err, &filterErr)) {
+		assert.Equal(t, "messages", filterErr.Activity)
+	}
+
+	_, err = NewFilterCatalog(client, -time.Second)
+	assert.Error(t, err, "negative ttl was accepted")
+}
+
+func TestFilterCatalog_ValuesPerFilter(t *testing.T) {
+	//apis which do not list the values in the filter map are asked for the values of each filter
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path == "/snmpsim/metrics/v1/activity/messages/filters" {
+			_, _ = w.Write([]byte(`{"recording": "recording", "context_name": "context name"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`["public", "private"]`))
+	}))
+	defer server.Close()
+
+	client, err := NewMetricsClient(server.URL)
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+	filters, err := client.FilterCatalog().MessageFilters()
+	if assert.NoError(t, err) && assert.Len(t, filters, 2) {
+		assert.Equal(t, "context_name", filters[0].Name)
+		assert.Equal(t, []string{"private", "public"}, filters[0].Values)
+	}
+	assert.Equal(t, []string{
+		"/snmpsim/metrics/v1/activity/messages/filters",
+		"/snmpsim/metrics/v1/activity/messages/filters/context_name",
+		"/snmpsim/metrics/v1/activity/messages/filters/recording",
+	}, paths)
+
+	names, err := client.GetMessageFilters()
+	if assert.NoError(t, err) {
+		assert.Equal(t, MessageFilters{"context_name", "recording"}, names)
+	}
+}
@@ -0,0 +1,244 @@
+package snmpsimclient
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"net"
+	"reflect"
+	"strconv"
+)
+
+/*
+Filter is a typed search filter, e.g. a LabFilter or a PacketFilter. Empty fields of a filter are not used for the search.
+Filters are validated and converted into query parameters before the request is sent.
+*/
+type Filter interface {
+	//queryParams validates the filter and converts it into the query parameters of a request
+	queryParams() (map[string]string, error)
+}
+
+//tagFilter is implemented by filters which restrict the results to objects with a tag
+type tagFilter interface {
+	tag() int
+}
+
+/*
+LabFilter filters labs. If TagId is set, only labs with this tag are returned.
+*/
+type LabFilter struct {
+	Name  string
+	Power string
+	TagId int
+}
+
+func (f LabFilter) queryParams() (map[string]string, error) {
+	if f.Power != "" && f.Power != "on" && f.Power != "off" {
+		return nil, errors.New("invalid power filter '" + f.Power + "', it has to be 'on' or 'off'")
+	}
+	if err := validateTagId(f.TagId); err != nil {
+		return nil, err
+	}
+	return filterParams("name", f.Name, "power", f.Power), nil
+}
+
+func (f LabFilter) tag() int {
+	return f.TagId
+}
+
+/*
+EngineFilter filters engines. If TagId is set, only engines with this tag are returned.
+*/
+type EngineFilter struct {
+	Name     string
+	EngineId string
+	TagId    int
+}
+
+func (f EngineFilter) queryParams() (map[string]string, error) {
+	if err := validateTagId(f.TagId); err != nil {
+		return nil, err
+	}
+	return filterParams("name", f.Name, "engine_id", f.EngineId), nil
+}
+
+func (f EngineFilter) tag() int {
+	return f.TagId
+}
+
+/*
+AgentFilter filters agents. If TagId is set, only agents with this tag are returned.
+*/
+type AgentFilter struct {
+	Name    string
+	DataDir string
+	TagId   int
+}
+
+func (f AgentFilter) queryParams() (map[string]string, error) {
+	if err := validateTagId(f.TagId); err != nil {
+		return nil, err
+	}
+	return filterParams("name", f.Name, "data_dir", f.DataDir), nil
+}
+
+func (f AgentFilter) tag() int {
+	return f.TagId
+}
+
+/*
+EndpointFilter filters endpoints. The address has to be given as host:port. If TagId is set, only endpoints with this tag are returned.
+*/
+type EndpointFilter struct {
+	Name     string
+	Address  string
+	Protocol string
+	TagId    int
+}
+
+func (f EndpointFilter) queryParams() (map[string]string, error) {
+	if f.Address != "" {
+		if _, _, err := net.SplitHostPort(f.Address); err != nil {
+			return nil, errors.Wrap(err, "invalid address filter")
+		}
+	}
+	if f.Protocol != "" && f.Protocol != "udpv4" && f.Protocol != "udpv6" {
+		return nil, errors.New("invalid protocol filter '" + f.Protocol + "', it has to be 'udpv4' or 'udpv6'")
+	}
+	if err := validateTagId(f.TagId); err != nil {
+		return nil, err
+	}
+	return filterParams("name", f.Name, "address", f.Address, "protocol", f.Protocol), nil
+}
+
+func (f EndpointFilter) tag() int {
+	return f.TagId
+}
+
+/*
+UserFilter filters users. If TagId is set, only users with this tag are returned.
+*/
+type UserFilter struct {
+	User      string
+	Name      string
+	AuthProto string
+	PrivProto string
+	TagId     int
+}
+
+func (f UserFilter) queryParams() (map[string]string, error) {
+	if err := validateTagId(f.TagId); err != nil {
+		return nil, err
+	}
+	return filterParams("user", f.User, "name", f.Name, "auth_proto", f.AuthProto, "priv_proto", f.PrivProto), nil
+}
+
+func (f UserFilter) tag() int {
+	return f.TagId
+}
+
+/*
+TagFilter filters tags.
+*/
+type TagFilter struct {
+	Name string
+}
+
+func (f TagFilter) queryParams() (map[string]string, error) {
+	return filterParams("name", f.Name), nil
+}
+
+/*
+PacketFilter filters packet metrics. The filters have to be offered by the api, see FilterCatalog.
+*/
+type PacketFilter struct {
+	LocalAddress string
+	PeerAddress  string
+	Protocol     string
+}
+
+func (f PacketFilter) queryParams() (map[string]string, error) {
+	return filterParams("local_address", f.LocalAddress, "peer_address", f.PeerAddress, "protocol", f.Protocol), nil
+}
+
+/*
+MessageFilter filters message metrics. The filters have to be offered by the api, see FilterCatalog.
+*/
+type MessageFilter struct {
+	LocalAddress    string
+	PeerAddress     string
+	Protocol        string
+	EngineId        string
+	SecurityModel   string
+	SecurityLevel   string
+	ContextEngineId string
+	ContextName     string
+	PduType         string
+	Recording       string
+}
+
+func (f MessageFilter) queryParams() (map[string]string, error) {
+	return filterParams(
+		"local_address", f.LocalAddress,
+		"peer_address", f.PeerAddress,
+		"protocol", f.Protocol,
+		"engine_id", f.EngineId,
+		"security_model", f.SecurityModel,
+		"security_level", f.SecurityLevel,
+		"context_engine_id", f.ContextEngineId,
+		"context_name", f.ContextName,
+		"pdu_type", f.PduType,
+		"recording", f.Recording,
+	), nil
+}
+
+//filterParams creates query parameters from pairs of keys and values, empty values are left out
+func filterParams(pairs ...string) map[string]string {
+	params := make(map[string]string)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i+1] != "" {
+			params[pairs[i]] = pairs[i+1]
+		}
+	}
+	return params
+}
+
+func validateTagId(tagId int) error {
+	if tagId < 0 {
+		return errors.New("invalid tag id " + strconv.Itoa(tagId))
+	}
+	return nil
+}
+
+//applyTagFilter removes all objects without the tag of the filter from the result, which has to be a pointer to a slice
+//of objects with a Tags field. The api can not filter by tags, so this is done by the client.
+func applyTagFilter(filter Filter, result interface{}) {
+	tf, ok := filter.(tagFilter)
+	if !ok || tf.tag() == 0 {
+		return
+	}
+	slice := reflect.ValueOf(result).Elem()
+	filtered := reflect.MakeSlice(slice.Type(), 0, slice.Len())
+	for i := 0; i < slice.Len(); i++ {
+		tags, _ := slice.Index(i).FieldByName("Tags").Interface().(Tags)
+		for _, tag := range tags {
+			if tag.Id == tf.tag() {
+				filtered = reflect.Append(filtered, slice.Index(i))
+				break
+			}
+		}
+	}
+	slice.Set(filtered)
+}
+
+//search gets all objects of the resource matching the filter
+func (c *client) search(ctx context.Context, r resource, filter Filter, result interface{}) error {
+	query, err := filter.queryParams()
+	if err != nil {
+		return errors.Wrap(err, "invalid filter")
+	}
+	if err = c.list(ctx, r, query, result); err != nil {
+		return err
+	}
+	applyTagFilter(filter, result)
+	return nil
+}
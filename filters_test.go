@@ -0,0 +1,135 @@
+package snmpsimclient
+
+import (
+	"github.com/inexio/snmpsim-restapi-go-client/snmpsimtest"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFilter_queryParams(t *testing.T) {
+	params, err := LabFilter{Name: "lab", Power: "on", TagId: 1}.queryParams()
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[string]string{"name": "lab", "power": "on"}, params)
+	}
+	params, err = MessageFilter{ContextName: "public", PduType: "GetRequestPDU"}.queryParams()
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[string]string{"context_name": "public", "pdu_type": "GetRequestPDU"}, params)
+	}
+	params, err = EndpointFilter{}.queryParams()
+	if assert.NoError(t, err) {
+		assert.Empty(t, params)
+	}
+
+	for _, filter := range []Filter{
+		LabFilter{Power: "yes"},
+		LabFilter{TagId: -1},
+		EndpointFilter{Address: "127.0.0.1"},
+		EndpointFilter{Protocol: "tcp"},
+	} {
+		_, err = filter.queryParams()
+		assert.Error(t, err, "invalid filter %+v was accepted", filter)
+	}
+
+	_, err = ListOptions{Filter: LabFilter{Name: "a"}, Filters: map[string]string{"name": "b"}}.queryParams()
+	assert.Error(t, err, "filter set twice was accepted")
+}
+
+func TestManagementClient_SearchWithFilters(t *testing.T) {
+	server := snmpsimtest.NewServer()
+	defer server.Close()
+
+	client, err := NewManagementClient(server.URL)
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+	tag, err := client.CreateTag("tag", "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	labOn, err := client.CreateLabWithTag("on", tag.Id)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, client.SetLabPower(labOn.Id, true))
+	_, err = client.CreateLab("off")
+	assert.NoError(t, err)
+	_, err = client.CreateLab("on too")
+	assert.NoError(t, err)
+
+	labs, err := client.SearchLabs(LabFilter{Power: "off"})
+	if assert.NoError(t, err) && assert.Len(t, labs, 2) {
+		assert.Equal(t, "off", labs[0].Name)
+	}
+	labs, err = client.SearchLabs(LabFilter{TagId: tag.Id})
+	if assert.NoError(t, err) && assert.Len(t, labs, 1) {
+		assert.Equal(t, labOn.Id, labs[0].Id)
+	}
+	_, err = client.SearchLabs(LabFilter{Power: "maybe"})
+	assert.Error(t, err, "invalid filter was sent")
+
+	_, err = client.CreateEndpoint("endpoint", "127.0.0.1:1161", "udpv4")
+	assert.NoError(t, err)
+	endpoints, err := client.SearchEndpoints(EndpointFilter{Address: "127.0.0.1:1161"})
+	if assert.NoError(t, err) {
+		assert.Len(t, endpoints, 1)
+	}
+	endpoints, err = client.SearchEndpoints(EndpointFilter{Protocol: "udpv6"})
+	if assert.NoError(t, err) {
+		assert.Empty(t, endpoints)
+	}
+}
+
+func TestMetricsClient_SearchWithFilters(t *testing.T) {
+	server := snmpsimtest.NewServer()
+	defer server.Close()
+	server.AddPacketActivity(snmpsimtest.PacketActivity{Labels: map[string]string{"local_address": "127.0.0.1:1161"}, Total: 3})
+	server.AddPacketActivity(snmpsimtest.PacketActivity{Labels: map[string]string{"local_address": "127.0.0.1:1162"}, Total: 4})
+	server.AddMessageActivity(snmpsimtest.MessageActivity{Labels: map[string]string{"context_name": "public"}, Pdus: 2})
+
+	client, err := NewMetricsClient(server.URL)
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+	packets, err := client.SearchPackets(PacketFilter{LocalAddress: "127.0.0.1:1162"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(4), *packets.Total)
+	}
+	_, err = client.SearchMessages(MessageFilter{ContextName: "public"})
+	assert.NoError(t, err)
+
+	//values which are not offered by the api are rejected as well
+	_, err = client.SearchPackets(PacketFilter{LocalAddress: "127.0.0.1:1163"})
+	var filterErr *FilterError
+	if assert.True(t, errors.As(err, &filterErr), "unknown value does not return a FilterError") {
+		assert.Equal(t, "local_address", filterErr.Filter)
+		assert.Equal(t, "127.0.0.1:1163", filterErr.Value)
+	}
+	_, err = client.SearchMessages(MessageFilter{ContextName: "private"})
+	assert.True(t, errors.As(err, &filterErr), "unknown value does not return a FilterError")
+
+	//clients which were not created with NewMetricsClient are not valid
+	_, err = (&MetricsClient{}).SearchPackets(PacketFilter{LocalAddress: "127.0.0.1:1162"})
+	assert.IsType(t, &NotValidError{}, err)
+	_, err = (&MetricsClient{}).SearchMessages(MessageFilter{ContextName: "public"})
+	assert.IsType(t, &NotValidError{}, err)
+
+	//filters which are not offered by the api are rejected before the metrics are requested
+	var paths []string
+	limited := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		_, _ = w.Write([]byte(`{"local_address": "local address"}`))
+	}))
+	defer limited.Close()
+	client, err = NewMetricsClient(limited.URL)
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+	_, err = client.SearchMessages(MessageFilter{LocalAddress: "127.0.0.1:1161", ContextName: "public"})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "context_name")
+	}
+	assert.Equal(t, []string{"/snmpsim/metrics/v1/activity/messages/filters"}, paths)
+}
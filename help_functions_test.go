@@ -2,6 +2,7 @@ package snmpsimclient
 
 import (
 	"fmt"
+	"github.com/inexio/snmpsim-restapi-go-client/snmpsimtest"
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
@@ -34,14 +35,15 @@ type configMetricsApiTest struct {
 var configMetricsTest configMetricsApiTest
 
 type configManagementApiTest struct {
-	Http        httpConfig `mapstructure:"http"`
-	Protocol    string     `mapstructure:"protocol"`
-	Agent1      agentData  `mapstructure:"agent1"`
-	Agent2      agentData  `mapstructure:"agent2"`
-	RootDataDir string     `mapstructure:"rootDataDir"`
-	TestDataDir string
-	TestTagId   int
-	TestTagName string `mapstructure:"testTag"`
+	UseFakeServer bool       `mapstructure:"useFakeServer"`
+	Http          httpConfig `mapstructure:"http"`
+	Protocol      string     `mapstructure:"protocol"`
+	Agent1        agentData  `mapstructure:"agent1"`
+	Agent2        agentData  `mapstructure:"agent2"`
+	RootDataDir   string     `mapstructure:"rootDataDir"`
+	TestDataDir   string
+	TestTagId     int
+	TestTagName   string `mapstructure:"testTag"`
 }
 
 var configManagementTest configManagementApiTest
@@ -92,6 +94,16 @@ func init() {
 
 	configMetricsTest.TestDataDir = testDataDir
 
+	//fake control plane
+	if configManagementTest.UseFakeServer {
+		fakeServer := snmpsimtest.NewServer()
+		fakeServer.SetBasicAuth(configManagementTest.Http.AuthUsername, configManagementTest.Http.AuthPassword)
+		configManagementTest.Http.BaseUrl = fakeServer.URL
+		configMetricsTest.Http.BaseUrl = fakeServer.URL
+		configMetricsTest.Http.AuthUsername = configManagementTest.Http.AuthUsername
+		configMetricsTest.Http.AuthPassword = configManagementTest.Http.AuthPassword
+	}
+
 	//tags
 	//Create a new api client
 	client, err := NewManagementClient(configManagementTest.Http.BaseUrl)
@@ -144,6 +156,13 @@ func init() {
 	}
 }
 
+//skipWithFakeServer skips tests which send snmp requests to the simulated agents, the fake control plane does not run any
+func skipWithFakeServer(t *testing.T) {
+	if configManagementTest.UseFakeServer {
+		t.Skip("skipping " + t.Name() + ", it needs a running snmpsim control plane")
+	}
+}
+
 /*
 HELPER FUNCTIONS FOR PERFORMING API CALLS AND CHECKING IF THEY WHERE SUCCESSFUL
 */
@@ -0,0 +1,348 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+//clientPackage is the name of the package the interfaces are defined in
+const clientPackage = "snmpsimclient"
+
+//method is a method of an api interface
+type method struct {
+	name    string
+	params  []param
+	results []string
+}
+
+//param is a named parameter of a method
+type param struct {
+	name     string
+	typ      string
+	variadic bool
+}
+
+//generate creates the source of the mocks for the given interfaces, mocks maps interface names to mock names
+func generate(src []byte, mocks map[string]string) ([]byte, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "", src, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var interfaceNames []string
+	for name := range mocks {
+		interfaceNames = append(interfaceNames, name)
+	}
+	sort.Strings(interfaceNames)
+
+	var body bytes.Buffer
+	for _, interfaceName := range interfaceNames {
+		methods, err := interfaceMethods(file, interfaceName)
+		if err != nil {
+			return nil, err
+		}
+		writeMock(&body, interfaceName, mocks[interfaceName], methods)
+	}
+
+	imports, err := usedImports(file, body.String())
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by internal/mockgen. DO NOT EDIT.\n\n")
+	buf.WriteString("package snmpsimmock\n\n")
+	buf.WriteString("import (\n")
+	for _, path := range imports {
+		buf.WriteString("\t" + strconv.Quote(path) + "\n")
+	}
+	buf.WriteString(")\n")
+	buf.Write(body.Bytes())
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("error while formatting generated code: %v", err)
+	}
+	return formatted, nil
+}
+
+//usedImports returns the import paths of the packages used by the generated code, the mocks always use the context
+//package and the client package
+func usedImports(file *ast.File, code string) ([]string, error) {
+	generated, err := parser.ParseFile(token.NewFileSet(), "", "package snmpsimmock\n"+code, 0)
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing generated code: %v", err)
+	}
+	used := make(map[string]bool)
+	ast.Inspect(generated, func(node ast.Node) bool {
+		if selector, ok := node.(*ast.SelectorExpr); ok {
+			if x, ok := selector.X.(*ast.Ident); ok {
+				used[x.Name] = true
+			}
+		}
+		return true
+	})
+
+	imports := []string{"context", "github.com/inexio/snmpsim-restapi-go-client"}
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			return nil, err
+		}
+		name := path[strings.LastIndex(path, "/")+1:]
+		if spec.Name != nil {
+			return nil, fmt.Errorf("named import %s is not supported", path)
+		}
+		if path != "context" && used[name] {
+			imports = append(imports, path)
+		}
+	}
+	sort.Strings(imports)
+	return imports, nil
+}
+
+//interfaceMethods returns the methods of the interface with the given name in the order of their declaration
+func interfaceMethods(file *ast.File, interfaceName string) ([]method, error) {
+	var iface *ast.InterfaceType
+	ast.Inspect(file, func(node ast.Node) bool {
+		if spec, ok := node.(*ast.TypeSpec); ok && spec.Name.Name == interfaceName {
+			iface, _ = spec.Type.(*ast.InterfaceType)
+		}
+		return iface == nil
+	})
+	if iface == nil {
+		return nil, fmt.Errorf("interface %s not found", interfaceName)
+	}
+
+	var methods []method
+	for _, field := range iface.Methods.List {
+		funcType, ok := field.Type.(*ast.FuncType)
+		if !ok || len(field.Names) != 1 {
+			return nil, fmt.Errorf("interface %s embeds other interfaces, this is not supported", interfaceName)
+		}
+		m := method{name: field.Names[0].Name}
+		for _, p := range fieldList(funcType.Params) {
+			typ, err := typeString(p.Type)
+			if err != nil {
+				return nil, err
+			}
+			_, variadic := p.Type.(*ast.Ellipsis)
+			if variadic {
+				typ = "[]" + strings.TrimPrefix(typ, "...")
+			}
+			names := p.Names
+			if len(names) == 0 {
+				names = []*ast.Ident{{Name: "_"}}
+			}
+			for _, name := range names {
+				paramName := name.Name
+				if paramName == "_" {
+					paramName = "arg" + strconv.Itoa(len(m.params))
+				}
+				m.params = append(m.params, param{name: paramName, typ: typ, variadic: variadic})
+			}
+		}
+		for _, r := range fieldList(funcType.Results) {
+			typ, err := typeString(r.Type)
+			if err != nil {
+				return nil, err
+			}
+			count := len(r.Names)
+			if count == 0 {
+				count = 1
+			}
+			for i := 0; i < count; i++ {
+				m.results = append(m.results, typ)
+			}
+		}
+		methods = append(methods, m)
+	}
+	return methods, nil
+}
+
+func fieldList(list *ast.FieldList) []*ast.Field {
+	if list == nil {
+		return nil
+	}
+	return list.List
+}
+
+//typeString prints a type expression, types of the client package are qualified with the package name
+func typeString(expr ast.Expr) (string, error) {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if unicode.IsUpper([]rune(t.Name)[0]) {
+			return clientPackage + "." + t.Name, nil
+		}
+		return t.Name, nil
+	case *ast.SelectorExpr:
+		x, ok := t.X.(*ast.Ident)
+		if !ok {
+			return "", fmt.Errorf("unsupported type %T", t.X)
+		}
+		return x.Name + "." + t.Sel.Name, nil
+	case *ast.StarExpr:
+		x, err := typeString(t.X)
+		return "*" + x, err
+	case *ast.Ellipsis:
+		elt, err := typeString(t.Elt)
+		return "..." + elt, err
+	case *ast.ArrayType:
+		if t.Len != nil {
+			return "", fmt.Errorf("arrays are not supported")
+		}
+		elt, err := typeString(t.Elt)
+		return "[]" + elt, err
+	case *ast.MapType:
+		key, err := typeString(t.Key)
+		if err != nil {
+			return "", err
+		}
+		value, err := typeString(t.Value)
+		return "map[" + key + "]" + value, err
+	case *ast.InterfaceType:
+		if len(fieldList(t.Methods)) != 0 {
+			return "", fmt.Errorf("non empty interface types are not supported")
+		}
+		return "interface{}", nil
+	case *ast.FuncType:
+		var params, results []string
+		for _, p := range fieldList(t.Params) {
+			typ, err := typeString(p.Type)
+			if err != nil {
+				return "", err
+			}
+			for i := 0; i < len(p.Names) || i == 0; i++ {
+				params = append(params, typ)
+			}
+		}
+		for _, r := range fieldList(t.Results) {
+			typ, err := typeString(r.Type)
+			if err != nil {
+				return "", err
+			}
+			for i := 0; i < len(r.Names) || i == 0; i++ {
+				results = append(results, typ)
+			}
+		}
+		s := "func(" + strings.Join(params, ", ") + ")"
+		switch len(results) {
+		case 0:
+		case 1:
+			s += " " + results[0]
+		default:
+			s += " (" + strings.Join(results, ", ") + ")"
+		}
+		return s, nil
+	}
+	return "", fmt.Errorf("unsupported type %T", expr)
+}
+
+//writeMock writes the mock type, its constructor and all its methods
+func writeMock(buf *bytes.Buffer, interfaceName, mockName string, methods []method) {
+	resultsVar := strings.ToLower(mockName[:1]) + mockName[1:] + "Results"
+	byName := make(map[string]method)
+	for _, m := range methods {
+		byName[m.name] = m
+	}
+
+	fmt.Fprintf(buf, "\n/*\n%s is a mock implementation of snmpsimclient.%s.\n", mockName, interfaceName)
+	fmt.Fprintf(buf, "Calls of a method and its Context variant are recorded under the name of the method without the Context suffix.\n*/\n")
+	fmt.Fprintf(buf, "type %s struct {\n\tmock\n}\n\n", mockName)
+	fmt.Fprintf(buf, "/*\nNew%s creates a new %s without any configured results.\n*/\n", mockName, mockName)
+	fmt.Fprintf(buf, "func New%s() *%s {\n\treturn &%s{mock{results: %s}}\n}\n\n", mockName, mockName, mockName, resultsVar)
+	fmt.Fprintf(buf, "var _ snmpsimclient.%s = (*%s)(nil)\n\n", interfaceName, mockName)
+
+	fmt.Fprintf(buf, "//%s contains the number of results of all mocked methods\n", resultsVar)
+	fmt.Fprintf(buf, "var %s = map[string]int{\n", resultsVar)
+	for _, m := range methods {
+		if recordedName(m, byName) == m.name {
+			fmt.Fprintf(buf, "\t%q: %d,\n", m.name, len(m.results))
+		}
+	}
+	buf.WriteString("}\n")
+
+	for _, m := range methods {
+		writeMethod(buf, mockName, m, byName)
+	}
+}
+
+//isContextVariant checks whether the method is the Context variant of another method
+func isContextVariant(m method, byName map[string]method) bool {
+	if !strings.HasSuffix(m.name, "Context") || len(m.params) == 0 || m.params[0].typ != "context.Context" {
+		return false
+	}
+	_, ok := byName[strings.TrimSuffix(m.name, "Context")]
+	return ok
+}
+
+//recordedName returns the name calls of the method are recorded under
+func recordedName(m method, byName map[string]method) string {
+	if isContextVariant(m, byName) {
+		return strings.TrimSuffix(m.name, "Context")
+	}
+	return m.name
+}
+
+func writeMethod(buf *bytes.Buffer, mockName string, m method, byName map[string]method) {
+	var params, args []string
+	for _, p := range m.params {
+		typ := p.typ
+		arg := p.name
+		if p.variadic {
+			typ = "..." + strings.TrimPrefix(typ, "[]")
+			arg += "..."
+		}
+		params = append(params, p.name+" "+typ)
+		args = append(args, arg)
+	}
+	results := strings.Join(m.results, ", ")
+	if len(m.results) > 1 {
+		results = "(" + results + ")"
+	}
+	fmt.Fprintf(buf, "\nfunc (m *%s) %s(%s) %s {\n", mockName, m.name, strings.Join(params, ", "), results)
+
+	//the method without context delegates to its Context variant like the real client does
+	if variant, ok := byName[m.name+"Context"]; ok && isContextVariant(variant, byName) {
+		call := fmt.Sprintf("m.%sContext(%s)", m.name, strings.Join(append([]string{"context.Background()"}, args...), ", "))
+		if len(m.results) == 0 {
+			fmt.Fprintf(buf, "\t%s\n}\n", call)
+		} else {
+			fmt.Fprintf(buf, "\treturn %s\n}\n", call)
+		}
+		return
+	}
+
+	ctx := "context.Background()"
+	recorded := args
+	if len(m.params) != 0 && m.params[0].typ == "context.Context" {
+		ctx = m.params[0].name
+		recorded = args[1:]
+	}
+	var recordedArgs []string
+	for _, arg := range recorded {
+		recordedArgs = append(recordedArgs, strings.TrimSuffix(arg, "..."))
+	}
+	callArgs := append([]string{ctx, strconv.Quote(recordedName(m, byName))}, recordedArgs...)
+	if len(m.results) == 0 {
+		fmt.Fprintf(buf, "\tm.called(%s)\n}\n", strings.Join(callArgs, ", "))
+		return
+	}
+	fmt.Fprintf(buf, "\tresults := m.called(%s)\n", strings.Join(callArgs, ", "))
+	var names []string
+	for i, typ := range m.results {
+		fmt.Fprintf(buf, "\tvar r%d %s\n", i, typ)
+		names = append(names, "r"+strconv.Itoa(i))
+	}
+	for i := range m.results {
+		fmt.Fprintf(buf, "\tresults.assign(%d, &r%d)\n", i, i)
+	}
+	fmt.Fprintf(buf, "\treturn %s\n}\n", strings.Join(names, ", "))
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"testing"
+)
+
+func TestGenerate_UpToDate(t *testing.T) {
+	src, err := ioutil.ReadFile("../../api.go")
+	if !assert.NoError(t, err, "error while reading api interfaces") {
+		return
+	}
+	generated, err := generate(src, map[string]string{
+		"ManagementAPI": "ManagementClient",
+		"MetricsAPI":    "MetricsClient",
+	})
+	if !assert.NoError(t, err, "error while generating mocks") {
+		return
+	}
+	current, err := ioutil.ReadFile("../../snmpsimmock/mocks_gen.go")
+	if !assert.NoError(t, err, "error while reading generated mocks") {
+		return
+	}
+	assert.Equal(t, string(generated), string(current), "mocks are outdated, run go generate")
+}
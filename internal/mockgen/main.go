@@ -0,0 +1,39 @@
+/*
+Mockgen generates the mock implementations of the ManagementAPI and MetricsAPI interfaces in the snmpsimmock package.
+
+It is run with go generate from the root of the module:
+
+	go generate ./...
+*/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+func main() {
+	source := flag.String("source", "api.go", "file containing the api interfaces")
+	output := flag.String("output", "snmpsimmock/mocks_gen.go", "file the mocks are written to")
+	flag.Parse()
+
+	src, err := ioutil.ReadFile(*source)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error while reading source file:", err)
+		os.Exit(1)
+	}
+	generated, err := generate(src, map[string]string{
+		"ManagementAPI": "ManagementClient",
+		"MetricsAPI":    "MetricsClient",
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error while generating mocks:", err)
+		os.Exit(1)
+	}
+	if err = ioutil.WriteFile(*output, generated, 0644); err != nil {
+		fmt.Fprintln(os.Stderr, "error while writing mocks:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,747 @@
+package snmpsimclient
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"reflect"
+)
+
+/*
+LabSpec describes the desired state of a whole lab. It is applied to the api with ApplyLab.
+All objects are identified by their name (users by their user identifier), so the names have to be unique per object kind.
+*/
+type LabSpec struct {
+	Name string `json:"name" yaml:"name"`
+	//Power is the desired power state ("on" or "off"). If it is empty, the power state is not changed.
+	Power  string      `json:"power,omitempty" yaml:"power,omitempty"`
+	Agents []AgentSpec `json:"agents,omitempty" yaml:"agents,omitempty"`
+	Tags   []string    `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+/*
+AgentSpec describes the desired state of an agent inside of a LabSpec.
+*/
+type AgentSpec struct {
+	Name    string       `json:"name" yaml:"name"`
+	DataDir string       `json:"data_dir,omitempty" yaml:"data_dir,omitempty"`
+	Engines []EngineSpec `json:"engines,omitempty" yaml:"engines,omitempty"`
+	Tags    []string     `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+/*
+EngineSpec describes the desired state of an engine inside of an AgentSpec.
+*/
+type EngineSpec struct {
+	Name string `json:"name" yaml:"name"`
+	//EngineId is the snmp engine id. If it is empty, it is generated by the api and not compared to the existing engine.
+	EngineId  string         `json:"engine_id,omitempty" yaml:"engine_id,omitempty"`
+	Endpoints []EndpointSpec `json:"endpoints,omitempty" yaml:"endpoints,omitempty"`
+	Users     []UserSpec     `json:"users,omitempty" yaml:"users,omitempty"`
+	Tags      []string       `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+/*
+EndpointSpec describes the desired state of an endpoint inside of an EngineSpec.
+*/
+type EndpointSpec struct {
+	Name     string   `json:"name" yaml:"name"`
+	Address  string   `json:"address" yaml:"address"`
+	Protocol string   `json:"protocol,omitempty" yaml:"protocol,omitempty"`
+	Tags     []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+/*
+UserSpec describes the desired state of an user inside of an EngineSpec.
+*/
+type UserSpec struct {
+	User      string   `json:"user" yaml:"user"`
+	Name      string   `json:"name" yaml:"name"`
+	AuthKey   string   `json:"auth_key,omitempty" yaml:"auth_key,omitempty"`
+	AuthProto string   `json:"auth_proto,omitempty" yaml:"auth_proto,omitempty"`
+	PrivKey   string   `json:"priv_key,omitempty" yaml:"priv_key,omitempty"`
+	PrivProto string   `json:"priv_proto,omitempty" yaml:"priv_proto,omitempty"`
+	Tags      []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+/*
+ApplyLab converges the lab described by the given spec. It compares the spec with the current state of the api and only makes the
+necessary calls: missing objects are created, objects whose attributes differ are updated, links are added and removed and objects
+that belonged to the lab before but are neither part of the spec nor used anywhere else anymore are deleted.
+Tags listed in the spec are created if necessary and added to the objects, other tags of the objects are kept.
+Applying the same spec twice does not change anything. The resulting lab is returned.
+*/
+func (c *ManagementClient) ApplyLab(spec LabSpec) (Lab, error) {
+	return c.ApplyLabContext(context.Background(), spec)
+}
+
+/*
+ApplyLabContext is like ApplyLab but carries the given context through to the http requests.
+*/
+func (c *ManagementClient) ApplyLabContext(ctx context.Context, spec LabSpec) (Lab, error) {
+	if !c.isValid() {
+		return Lab{}, &NotValidError{}
+	}
+
+	desired, err := spec.flatten()
+	if err != nil {
+		return Lab{}, errors.Wrap(err, "invalid lab spec")
+	}
+
+	current, err := c.getLabState(ctx)
+	if err != nil {
+		return Lab{}, err
+	}
+
+	var lab Lab
+	labExists := false
+	for _, l := range current.labs {
+		if l.Name == spec.Name {
+			lab = l
+			labExists = true
+			break
+		}
+	}
+	if !labExists {
+		lab, err = c.CreateLabContext(ctx, spec.Name)
+		if err != nil {
+			return Lab{}, errors.Wrap(err, "error while creating lab '"+spec.Name+"'")
+		}
+	}
+
+	//objects of the lab before applying the spec, they are candidates for deletion
+	previous := newLabObjects()
+	for _, agent := range lab.Agents {
+		previous.addAgent(agent)
+	}
+
+	a := labApplier{client: c, ctx: ctx, current: current}
+	if err = a.ensureObjects(desired); err != nil {
+		return Lab{}, err
+	}
+	if err = a.reconcileLinks(lab, desired); err != nil {
+		return Lab{}, err
+	}
+	if err = a.prune(previous, desired); err != nil {
+		return Lab{}, err
+	}
+	if err = a.ensureTags(lab, spec.Tags, desired); err != nil {
+		return Lab{}, err
+	}
+
+	if spec.Power != "" && spec.Power != lab.Power {
+		if err = c.SetLabPowerContext(ctx, lab.Id, spec.Power == "on"); err != nil {
+			return Lab{}, errors.Wrap(err, "error while setting lab power")
+		}
+	}
+
+	lab, err = c.GetLabContext(ctx, lab.Id)
+	if err != nil {
+		return Lab{}, errors.Wrap(err, "error while getting applied lab")
+	}
+	return lab, nil
+}
+
+//labObjects contains the objects of a lab, grouped by kind and indexed by name. The names are also kept in order of appearance.
+type labObjects struct {
+	agents    map[string]AgentSpec
+	engines   map[string]EngineSpec
+	endpoints map[string]EndpointSpec
+	users     map[string]UserSpec
+
+	agentNames    []string
+	engineNames   []string
+	endpointNames []string
+	userNames     []string
+}
+
+func newLabObjects() labObjects {
+	return labObjects{
+		agents:    make(map[string]AgentSpec),
+		engines:   make(map[string]EngineSpec),
+		endpoints: make(map[string]EndpointSpec),
+		users:     make(map[string]UserSpec),
+	}
+}
+
+//addAgent adds an existing agent and all of its linked objects
+func (o *labObjects) addAgent(agent Agent) {
+	o.addAgentSpec(AgentSpec{Name: agent.Name, DataDir: agent.DataDir})
+	for _, engine := range agent.Engines {
+		o.addEngineSpec(EngineSpec{Name: engine.Name})
+		for _, endpoint := range engine.Endpoints {
+			o.addEndpointSpec(EndpointSpec{Name: endpoint.Name})
+		}
+		for _, user := range engine.Users {
+			o.addUserSpec(UserSpec{User: user.User})
+		}
+	}
+}
+
+func (o *labObjects) addAgentSpec(agent AgentSpec) {
+	if _, ok := o.agents[agent.Name]; !ok {
+		o.agentNames = append(o.agentNames, agent.Name)
+	}
+	o.agents[agent.Name] = agent
+}
+
+func (o *labObjects) addEngineSpec(engine EngineSpec) {
+	if _, ok := o.engines[engine.Name]; !ok {
+		o.engineNames = append(o.engineNames, engine.Name)
+	}
+	o.engines[engine.Name] = engine
+}
+
+func (o *labObjects) addEndpointSpec(endpoint EndpointSpec) {
+	if _, ok := o.endpoints[endpoint.Name]; !ok {
+		o.endpointNames = append(o.endpointNames, endpoint.Name)
+	}
+	o.endpoints[endpoint.Name] = endpoint
+}
+
+func (o *labObjects) addUserSpec(user UserSpec) {
+	if _, ok := o.users[user.User]; !ok {
+		o.userNames = append(o.userNames, user.User)
+	}
+	o.users[user.User] = user
+}
+
+//flatten validates the spec and groups all its objects by kind, defaults are filled in like the create functions do
+func (s LabSpec) flatten() (labObjects, error) {
+	o := newLabObjects()
+	if s.Name == "" {
+		return o, errors.New("invalid lab name")
+	}
+	if s.Power != "" && s.Power != "on" && s.Power != "off" {
+		return o, errors.New("invalid power state '" + s.Power + "'")
+	}
+	for _, agent := range s.Agents {
+		if agent.Name == "" {
+			return o, errors.New("invalid agent name")
+		}
+		if agent.DataDir == "" {
+			agent.DataDir = "."
+		}
+		if _, ok := o.agents[agent.Name]; ok {
+			return o, errors.New("agent '" + agent.Name + "' is defined more than once")
+		}
+		o.addAgentSpec(agent)
+		for _, engine := range agent.Engines {
+			if engine.Name == "" {
+				return o, errors.New("invalid engine name")
+			}
+			if _, ok := o.engines[engine.Name]; ok {
+				return o, errors.New("engine '" + engine.Name + "' is defined more than once")
+			}
+			o.addEngineSpec(engine)
+			for _, endpoint := range engine.Endpoints {
+				if endpoint.Name == "" {
+					return o, errors.New("invalid endpoint name")
+				}
+				if endpoint.Address == "" {
+					return o, errors.New("invalid address for endpoint '" + endpoint.Name + "'")
+				}
+				if endpoint.Protocol == "" {
+					endpoint.Protocol = "udpv4"
+				}
+				if _, ok := o.endpoints[endpoint.Name]; ok {
+					return o, errors.New("endpoint '" + endpoint.Name + "' is defined more than once")
+				}
+				o.addEndpointSpec(endpoint)
+			}
+			for _, user := range engine.Users {
+				if user.User == "" || user.Name == "" {
+					return o, errors.New("invalid user")
+				}
+				if user.AuthProto == "" {
+					user.AuthProto = "none"
+				}
+				if user.PrivProto == "" {
+					user.PrivProto = "none"
+				}
+				if existing, ok := o.users[user.User]; ok && !reflect.DeepEqual(existing, user) {
+					return o, errors.New("user '" + user.User + "' is defined more than once with different attributes")
+				}
+				o.addUserSpec(user)
+			}
+		}
+	}
+	return o, nil
+}
+
+//labState is the current state of the api, indexed by name
+type labState struct {
+	labs      Labs
+	agents    map[string]Agent
+	engines   map[string]Engine
+	endpoints map[string]Endpoint
+	users     map[string]User
+}
+
+func (c *ManagementClient) getLabState(ctx context.Context) (*labState, error) {
+	labs, err := c.GetLabsContext(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "error while getting labs")
+	}
+	agents, err := c.GetAgentsContext(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "error while getting agents")
+	}
+	engines, err := c.GetEnginesContext(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "error while getting engines")
+	}
+	endpoints, err := c.GetEndpointsContext(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "error while getting endpoints")
+	}
+	users, err := c.GetUsersContext(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "error while getting users")
+	}
+
+	state := labState{
+		labs:      labs,
+		agents:    make(map[string]Agent),
+		engines:   make(map[string]Engine),
+		endpoints: make(map[string]Endpoint),
+		users:     make(map[string]User),
+	}
+	for _, agent := range agents {
+		state.agents[agent.Name] = agent
+	}
+	for _, engine := range engines {
+		state.engines[engine.Name] = engine
+	}
+	for _, endpoint := range endpoints {
+		state.endpoints[endpoint.Name] = endpoint
+	}
+	for _, user := range users {
+		state.users[user.User] = user
+	}
+	return &state, nil
+}
+
+//labApplier makes the calls necessary to converge the current state to a LabSpec
+type labApplier struct {
+	client  *ManagementClient
+	ctx     context.Context
+	current *labState
+}
+
+//ensureObjects creates missing objects and updates objects whose attributes differ from the spec
+func (a *labApplier) ensureObjects(desired labObjects) error {
+	c, ctx := a.client, a.ctx
+
+	for _, name := range desired.endpointNames {
+		spec := desired.endpoints[name]
+		existing, ok := a.current.endpoints[name]
+		if ok && existing.Address == spec.Address && existing.Protocol == spec.Protocol {
+			continue
+		}
+		if ok {
+			update := EndpointUpdate{Address: String(spec.Address), Protocol: String(spec.Protocol)}
+			if _, err := c.UpdateEndpointContext(ctx, existing.Id, update); err != nil {
+				return errors.Wrap(err, "error while updating endpoint '"+name+"'")
+			}
+			existing.Address, existing.Protocol = spec.Address, spec.Protocol
+			a.current.endpoints[name] = existing
+			continue
+		}
+		endpoint, err := c.CreateEndpointContext(ctx, spec.Name, spec.Address, spec.Protocol)
+		if err != nil {
+			return errors.Wrap(err, "error while creating endpoint '"+name+"'")
+		}
+		a.current.endpoints[name] = endpoint
+	}
+
+	for _, name := range desired.userNames {
+		spec := desired.users[name]
+		existing, ok := a.current.users[name]
+		if ok && existing.Name == spec.Name && existing.AuthKey == spec.AuthKey && existing.AuthProto == spec.AuthProto &&
+			existing.PrivKey == spec.PrivKey && existing.PrivProto == spec.PrivProto {
+			continue
+		}
+		if ok {
+			update := UserUpdate{
+				Name:      String(spec.Name),
+				AuthKey:   String(spec.AuthKey),
+				AuthProto: String(spec.AuthProto),
+				PrivKey:   String(spec.PrivKey),
+				PrivProto: String(spec.PrivProto),
+			}
+			if _, err := c.UpdateUserContext(ctx, existing.Id, update); err != nil {
+				return errors.Wrap(err, "error while updating user '"+name+"'")
+			}
+			existing.Name, existing.AuthKey, existing.AuthProto = spec.Name, spec.AuthKey, spec.AuthProto
+			existing.PrivKey, existing.PrivProto = spec.PrivKey, spec.PrivProto
+			a.current.users[name] = existing
+			continue
+		}
+		user, err := c.CreateUserContext(ctx, spec.User, spec.Name, spec.AuthKey, spec.AuthProto, spec.PrivKey, spec.PrivProto)
+		if err != nil {
+			return errors.Wrap(err, "error while creating user '"+name+"'")
+		}
+		a.current.users[name] = user
+	}
+
+	for _, name := range desired.engineNames {
+		spec := desired.engines[name]
+		existing, ok := a.current.engines[name]
+		if ok && (spec.EngineId == "" || existing.EngineId == spec.EngineId) {
+			continue
+		}
+		if ok {
+			if _, err := c.UpdateEngineContext(ctx, existing.Id, EngineUpdate{EngineId: String(spec.EngineId)}); err != nil {
+				return errors.Wrap(err, "error while updating engine '"+name+"'")
+			}
+			existing.EngineId = spec.EngineId
+			a.current.engines[name] = existing
+			continue
+		}
+		engine, err := c.CreateEngineContext(ctx, spec.Name, spec.EngineId)
+		if err != nil {
+			return errors.Wrap(err, "error while creating engine '"+name+"'")
+		}
+		a.current.engines[name] = engine
+	}
+
+	for _, name := range desired.agentNames {
+		spec := desired.agents[name]
+		existing, ok := a.current.agents[name]
+		if ok && existing.DataDir == spec.DataDir {
+			continue
+		}
+		if ok {
+			if _, err := c.UpdateAgentContext(ctx, existing.Id, AgentUpdate{DataDir: String(spec.DataDir)}); err != nil {
+				return errors.Wrap(err, "error while updating agent '"+name+"'")
+			}
+			existing.DataDir = spec.DataDir
+			a.current.agents[name] = existing
+			continue
+		}
+		agent, err := c.CreateAgentContext(ctx, spec.Name, spec.DataDir)
+		if err != nil {
+			return errors.Wrap(err, "error while creating agent '"+name+"'")
+		}
+		a.current.agents[name] = agent
+	}
+	return nil
+}
+
+//reconcileLinks removes all links which are not part of the spec and adds the missing ones afterwards
+func (a *labApplier) reconcileLinks(lab Lab, desired labObjects) error {
+	c, ctx := a.client, a.ctx
+
+	//current links of the lab
+	for _, agent := range lab.Agents {
+		if _, ok := desired.agents[agent.Name]; !ok && a.current.agents[agent.Name].Id == agent.Id {
+			if err := c.RemoveAgentFromLabContext(ctx, lab.Id, agent.Id); err != nil {
+				return errors.Wrap(err, "error while removing agent '"+agent.Name+"' from lab")
+			}
+		}
+	}
+	for _, name := range desired.agentNames {
+		agent := a.current.agents[name]
+		for _, engine := range agent.Engines {
+			if !desired.agentHasEngine(name, engine.Name) && a.current.engines[engine.Name].Id == engine.Id {
+				if err := c.RemoveEngineFromAgentContext(ctx, agent.Id, engine.Id); err != nil {
+					return errors.Wrap(err, "error while removing engine '"+engine.Name+"' from agent '"+name+"'")
+				}
+			}
+		}
+	}
+	for _, name := range desired.engineNames {
+		engine := a.current.engines[name]
+		for _, endpoint := range engine.Endpoints {
+			if !desired.engineHasEndpoint(name, endpoint.Name) && a.current.endpoints[endpoint.Name].Id == endpoint.Id {
+				if err := c.RemoveEndpointFromEngineContext(ctx, engine.Id, endpoint.Id); err != nil {
+					return errors.Wrap(err, "error while removing endpoint '"+endpoint.Name+"' from engine '"+name+"'")
+				}
+			}
+		}
+		for _, user := range engine.Users {
+			if !desired.engineHasUser(name, user.User) && a.current.users[user.User].Id == user.Id {
+				if err := c.RemoveUserFromEngineContext(ctx, engine.Id, user.Id); err != nil {
+					return errors.Wrap(err, "error while removing user '"+user.User+"' from engine '"+name+"'")
+				}
+			}
+		}
+	}
+
+	//missing links
+	for _, name := range desired.engineNames {
+		engine := a.current.engines[name]
+		for _, spec := range desired.engines[name].Endpoints {
+			endpoint := a.current.endpoints[spec.Name]
+			if !endpointExistsInEngine(engine, endpoint.Id) {
+				if err := c.AddEndpointToEngineContext(ctx, engine.Id, endpoint.Id); err != nil {
+					return errors.Wrap(err, "error while adding endpoint '"+spec.Name+"' to engine '"+name+"'")
+				}
+			}
+		}
+		for _, spec := range desired.engines[name].Users {
+			user := a.current.users[spec.User]
+			if !userExistsInEngine(engine, user.Id) {
+				if err := c.AddUserToEngineContext(ctx, engine.Id, user.Id); err != nil {
+					return errors.Wrap(err, "error while adding user '"+spec.User+"' to engine '"+name+"'")
+				}
+			}
+		}
+	}
+	for _, name := range desired.agentNames {
+		agent := a.current.agents[name]
+		for _, spec := range desired.agents[name].Engines {
+			engine := a.current.engines[spec.Name]
+			if !engineExistsInAgent(agent, engine.Id) {
+				if err := c.AddEngineToAgentContext(ctx, agent.Id, engine.Id); err != nil {
+					return errors.Wrap(err, "error while adding engine '"+spec.Name+"' to agent '"+name+"'")
+				}
+			}
+		}
+		if !agentExistsInLab(lab, agent.Id) {
+			if err := c.AddAgentToLabContext(ctx, lab.Id, agent.Id); err != nil {
+				return errors.Wrap(err, "error while adding agent '"+name+"' to lab")
+			}
+		}
+	}
+	return nil
+}
+
+//prune deletes objects which belonged to the lab before, are not part of the spec and are not used anywhere else
+func (a *labApplier) prune(previous, desired labObjects) error {
+	c, ctx := a.client, a.ctx
+
+	state, err := c.getLabState(ctx)
+	if err != nil {
+		return err
+	}
+
+	deletedAgents := make(map[int]bool)
+	for _, name := range previous.agentNames {
+		agent, ok := state.agents[name]
+		if _, keep := desired.agents[name]; keep || !ok {
+			continue
+		}
+		used := false
+		for _, lab := range state.labs {
+			used = used || agentExistsInLab(lab, agent.Id)
+		}
+		if used {
+			continue
+		}
+		if err := c.DeleteAgentContext(ctx, agent.Id); err != nil {
+			return errors.Wrap(err, "error while deleting agent '"+name+"'")
+		}
+		deletedAgents[agent.Id] = true
+	}
+
+	deletedEngines := make(map[int]bool)
+	for _, name := range previous.engineNames {
+		engine, ok := state.engines[name]
+		if _, keep := desired.engines[name]; keep || !ok {
+			continue
+		}
+		used := false
+		for _, agent := range state.agents {
+			used = used || (!deletedAgents[agent.Id] && engineExistsInAgent(agent, engine.Id))
+		}
+		if used {
+			continue
+		}
+		if err := c.DeleteEngineContext(ctx, engine.Id); err != nil {
+			return errors.Wrap(err, "error while deleting engine '"+name+"'")
+		}
+		deletedEngines[engine.Id] = true
+	}
+
+	for _, name := range previous.endpointNames {
+		endpoint, ok := state.endpoints[name]
+		if _, keep := desired.endpoints[name]; keep || !ok {
+			continue
+		}
+		used := false
+		for _, engine := range state.engines {
+			used = used || (!deletedEngines[engine.Id] && endpointExistsInEngine(engine, endpoint.Id))
+		}
+		if used {
+			continue
+		}
+		if err := c.DeleteEndpointContext(ctx, endpoint.Id); err != nil {
+			return errors.Wrap(err, "error while deleting endpoint '"+name+"'")
+		}
+	}
+
+	for _, name := range previous.userNames {
+		user, ok := state.users[name]
+		if _, keep := desired.users[name]; keep || !ok {
+			continue
+		}
+		used := false
+		for _, engine := range state.engines {
+			used = used || (!deletedEngines[engine.Id] && userExistsInEngine(engine, user.Id))
+		}
+		if used {
+			continue
+		}
+		if err := c.DeleteUserContext(ctx, user.Id); err != nil {
+			return errors.Wrap(err, "error while deleting user '"+name+"'")
+		}
+	}
+	return nil
+}
+
+//ensureTags creates missing tags and adds them to the objects of the lab
+func (a *labApplier) ensureTags(lab Lab, labTags []string, desired labObjects) error {
+	c, ctx := a.client, a.ctx
+
+	tags, err := c.GetTagsContext(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "error while getting tags")
+	}
+	tagIds := make(map[string]int)
+	for _, tag := range tags {
+		tagIds[tag.Name] = tag.Id
+	}
+
+	//missingTags returns the ids of the given tag names which are not in the existing tags, missing tags are created
+	missingTags := func(names []string, existing Tags) ([]int, error) {
+		var ids []int
+		for _, name := range names {
+			id, ok := tagIds[name]
+			if !ok {
+				tag, err := c.CreateTagContext(ctx, name, "")
+				if err != nil {
+					return nil, errors.Wrap(err, "error while creating tag '"+name+"'")
+				}
+				id = tag.Id
+				tagIds[name] = id
+			}
+			tagged := false
+			for _, tag := range existing {
+				tagged = tagged || tag.Id == id
+			}
+			if !tagged {
+				ids = append(ids, id)
+			}
+		}
+		return ids, nil
+	}
+
+	ids, err := missingTags(labTags, lab.Tags)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err = c.AddTagToLabContext(ctx, lab.Id, id); err != nil {
+			return errors.Wrap(err, "error while adding tag to lab")
+		}
+	}
+	for _, name := range desired.agentNames {
+		agent := a.current.agents[name]
+		if ids, err = missingTags(desired.agents[name].Tags, agent.Tags); err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if err = c.AddTagToAgentContext(ctx, agent.Id, id); err != nil {
+				return errors.Wrap(err, "error while adding tag to agent '"+name+"'")
+			}
+		}
+	}
+	for _, name := range desired.engineNames {
+		engine := a.current.engines[name]
+		if ids, err = missingTags(desired.engines[name].Tags, engine.Tags); err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if err = c.AddTagToEngineContext(ctx, engine.Id, id); err != nil {
+				return errors.Wrap(err, "error while adding tag to engine '"+name+"'")
+			}
+		}
+	}
+	for _, name := range desired.endpointNames {
+		endpoint := a.current.endpoints[name]
+		if ids, err = missingTags(desired.endpoints[name].Tags, endpoint.Tags); err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if err = c.AddTagToEndpointContext(ctx, endpoint.Id, id); err != nil {
+				return errors.Wrap(err, "error while adding tag to endpoint '"+name+"'")
+			}
+		}
+	}
+	for _, name := range desired.userNames {
+		user := a.current.users[name]
+		if ids, err = missingTags(desired.users[name].Tags, user.Tags); err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if err = c.AddTagToUserContext(ctx, user.Id, id); err != nil {
+				return errors.Wrap(err, "error while adding tag to user '"+name+"'")
+			}
+		}
+	}
+	return nil
+}
+
+func (o labObjects) agentHasEngine(agentName, engineName string) bool {
+	for _, engine := range o.agents[agentName].Engines {
+		if engine.Name == engineName {
+			return true
+		}
+	}
+	return false
+}
+
+func (o labObjects) engineHasEndpoint(engineName, endpointName string) bool {
+	for _, endpoint := range o.engines[engineName].Endpoints {
+		if endpoint.Name == endpointName {
+			return true
+		}
+	}
+	return false
+}
+
+func (o labObjects) engineHasUser(engineName, userIdentifier string) bool {
+	for _, user := range o.engines[engineName].Users {
+		if user.User == userIdentifier {
+			return true
+		}
+	}
+	return false
+}
+
+func agentExistsInLab(lab Lab, agentId int) bool {
+	for _, agent := range lab.Agents {
+		if agent.Id == agentId {
+			return true
+		}
+	}
+	return false
+}
+
+func engineExistsInAgent(agent Agent, engineId int) bool {
+	for _, engine := range agent.Engines {
+		if engine.Id == engineId {
+			return true
+		}
+	}
+	return false
+}
+
+func endpointExistsInEngine(engine Engine, endpointId int) bool {
+	for _, endpoint := range engine.Endpoints {
+		if endpoint.Id == endpointId {
+			return true
+		}
+	}
+	return false
+}
+
+func userExistsInEngine(engine Engine, userId int) bool {
+	for _, user := range engine.Users {
+		if user.Id == userId {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package snmpsimclient
+
+import (
+	"github.com/pkg/errors"
+	"strconv"
+)
+
+//query parameters of the control plane for paging and sorting list requests
+const (
+	pageParam    = "page"
+	perPageParam = "per_page"
+	sortByParam  = "sort_by"
+	orderParam   = "order"
+)
+
+/*
+DefaultPageSize is the number of objects per page requested by pagers if ListOptions.PerPage is not set.
+*/
+const DefaultPageSize = 100
+
+/*
+ListOptions controls filtering, paging and sorting of list requests.
+*/
+type ListOptions struct {
+	//Filter restricts the list to objects matching the typed filter, e.g. an AgentFilter for ListAgents.
+	//A tag id of the filter is applied by the client, so pages may contain fewer objects than requested.
+	Filter Filter
+	//Filters restricts the list to objects whose attributes match, like the filter maps of the Get... methods.
+	Filters map[string]string
+	//Page is the number of the requested page starting at 1, pagers start at this page. 0 requests the first page.
+	Page int
+	//PerPage is the number of objects per page. Pagers use DefaultPageSize if it is 0.
+	PerPage int
+	//SortBy is the name of the attribute the list is sorted by, e.g. "name".
+	SortBy string
+	//Descending reverses the sort order.
+	Descending bool
+}
+
+//queryParams validates the options and converts them into the query parameters of a list request
+func (o ListOptions) queryParams() (map[string]string, error) {
+	if o.Page < 0 {
+		return nil, errors.New("invalid page " + strconv.Itoa(o.Page))
+	}
+	if o.PerPage < 0 {
+		return nil, errors.New("invalid page size " + strconv.Itoa(o.PerPage))
+	}
+	if o.Descending && o.SortBy == "" {
+		return nil, errors.New("descending order requires an attribute to sort by")
+	}
+
+	params := make(map[string]string)
+	if o.Filter != nil {
+		filterParams, err := o.Filter.queryParams()
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid filter")
+		}
+		for key, value := range filterParams {
+			params[key] = value
+		}
+	}
+	for key, value := range o.Filters {
+		switch key {
+		case pageParam, perPageParam, sortByParam, orderParam:
+			return nil, errors.New("invalid filter '" + key + "', use the fields of the list options for paging and sorting")
+		}
+		if _, ok := params[key]; ok {
+			return nil, errors.New("filter '" + key + "' is set by the typed filter and the filter map")
+		}
+		params[key] = value
+	}
+	if o.Page != 0 {
+		params[pageParam] = strconv.Itoa(o.Page)
+	}
+	if o.PerPage != 0 {
+		params[perPageParam] = strconv.Itoa(o.PerPage)
+	}
+	if o.SortBy != "" {
+		params[sortByParam] = o.SortBy
+		params[orderParam] = "asc"
+		if o.Descending {
+			params[orderParam] = "desc"
+		}
+	}
+	return params, nil
+}
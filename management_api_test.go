@@ -1,6 +1,7 @@
 package snmpsimclient
 
 import (
+	"github.com/pkg/errors"
 	"github.com/soniah/gosnmp"
 	"github.com/stretchr/testify/assert"
 	"strconv"
@@ -9,6 +10,7 @@ import (
 )
 
 func TestManagementClient_buildUpSetupAndTestIt(t *testing.T) {
+	skipWithFakeServer(t)
 	community := "public"
 	//	Agent 1
 	//Agent
@@ -76,7 +78,7 @@ func TestManagementClient_buildUpSetupAndTestIt(t *testing.T) {
 	//TODO: remove this when its possible to overwrite files
 	err = client.DeleteRecordFile(remoteRecordFilePath1)
 	if err != nil {
-		if err, ok := err.(HttpError); assert.True(t, ok, "unknown error returned while deleting record file") {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "unknown error returned while deleting record file") {
 			if !assert.True(t, err.StatusCode == 404, "http error code for deleting record file is not 404! error: "+err.Error()) {
 				return
 			}
@@ -99,7 +101,7 @@ func TestManagementClient_buildUpSetupAndTestIt(t *testing.T) {
 	//TODO: remove this when its possible to overwrite files
 	err = client.DeleteRecordFile(remoteRecordFilePath2)
 	if err != nil {
-		if err, ok := err.(HttpError); assert.True(t, ok, "unknown error returned while deleting record file") {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "unknown error returned while deleting record file") {
 			if !assert.True(t, err.StatusCode == 404, "http error code for deleting record file is not 404! error: "+err.Error()) {
 				return
 			}
@@ -441,7 +443,7 @@ func TestManagementClient_UploadRecordFileString_DeleteRecordFile(t *testing.T)
 	//init cleanup
 	err = client.DeleteRecordFile(remotePathFile1)
 	if err != nil {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error while initial delete is not a http error"+err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error while initial delete is not a http error"+err.Error()) {
 			if !assert.True(t, err.StatusCode == 404, "cleanup delete error != 404") {
 				return
 			}
@@ -451,7 +453,7 @@ func TestManagementClient_UploadRecordFileString_DeleteRecordFile(t *testing.T)
 	}
 	err = client.DeleteRecordFile(remotePathFile2)
 	if err != nil {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error while initial delete is not a http error"+err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error while initial delete is not a http error"+err.Error()) {
 			if !assert.True(t, err.StatusCode == 404, "cleanup delete error != 404") {
 				return
 			}
@@ -486,7 +488,7 @@ func TestManagementClient_UploadRecordFileString_DeleteRecordFile(t *testing.T)
 		invalidRecord := "invalid\record\file"
 		err = client.UploadRecordFileString(&invalidRecord, "invalid/record/file.snmprec")
 		if assert.Error(t, err, "no error when uploading invalid record file") {
-			if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+			if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 				assert.True(t, err.StatusCode == 400, "error != 400")
 			}
 		}
@@ -907,7 +909,7 @@ func TestManagementClient_Agent_Failures(t *testing.T) {
 		//Create Agent with invalid data dir
 		_, err = client.CreateAgent("name", "test-CreateAgent_Failure")
 		if assert.Error(t, err, "no error when an agent with an invalid data dir was created") {
-			if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+			if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 				assert.True(t, err.StatusCode == 404, "error != 404")
 			}
 		}
@@ -916,7 +918,7 @@ func TestManagementClient_Agent_Failures(t *testing.T) {
 	//Get Invalid Agent
 	_, err = client.GetAgent(-1)
 	if assert.Error(t, err, "no error when trying to get an invalid agent") {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 			assert.True(t, err.StatusCode == 404, "error != 404")
 		}
 	}
@@ -924,7 +926,7 @@ func TestManagementClient_Agent_Failures(t *testing.T) {
 	//Delete invalid agent
 	err = client.DeleteAgent(-1)
 	if assert.Error(t, err, "no error when a non existent agent was deleted") {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 			assert.True(t, err.StatusCode == 404, "error != 404")
 		}
 	}
@@ -941,7 +943,7 @@ func TestManagementClient_Agent_Failures(t *testing.T) {
 		//add invalid engine to agent
 		err = client.AddEngineToAgent(agent.Id, -1)
 		if assert.Error(t, err, "no error when an invalid engine id was added to an agent") {
-			if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+			if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 				assert.True(t, err.StatusCode == 404, "error != 404" , err.Error())
 			}
 		}
@@ -957,7 +959,7 @@ func TestManagementClient_Agent_Failures(t *testing.T) {
 	//add valid engine to invalid agent
 	err = client.AddEngineToAgent(-1, engine.Id)
 	if assert.Error(t, err, "no error when an existent engine was added to a non existing agent") {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 			assert.True(t, err.StatusCode == 400, "error != 400")
 		}
 	}
@@ -972,7 +974,7 @@ func TestManagementClient_Agent_Failures(t *testing.T) {
 	//add already attached engine to agent
 	err = client.AddEngineToAgent(agent.Id, engine.Id)
 	if assert.Error(t, err, "no error when an engine was added twice to an agent") {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 			assert.True(t, err.StatusCode == 400, "error != 400")
 		}
 	}
@@ -980,7 +982,7 @@ func TestManagementClient_Agent_Failures(t *testing.T) {
 	//remove non existing engine from non existing agent
 	err = client.RemoveEngineFromAgent(-1, -1)
 	if assert.Error(t, err, "no error when removing non existing engine from non existing agent") {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 			assert.True(t, err.StatusCode == 404, "error != 404")
 		}
 	}
@@ -988,7 +990,7 @@ func TestManagementClient_Agent_Failures(t *testing.T) {
 	//remove non existing engine from exisiting agent
 	err = client.RemoveEngineFromAgent(agent.Id, -1)
 	if assert.Error(t, err, "no error when removing non existing engine from existing agent") {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 			assert.True(t, err.StatusCode == 404, "error != 404", err.Error())
 		}
 	}
@@ -996,7 +998,7 @@ func TestManagementClient_Agent_Failures(t *testing.T) {
 	//remove existing engine from non exisiting agent
 	err = client.RemoveEngineFromAgent(-1, engine.Id)
 	if assert.Error(t, err, "no error when removing existing engine from non existing agent") {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 			assert.True(t, err.StatusCode == 400, "error != 400", err.Error())
 		}
 	}
@@ -1007,7 +1009,7 @@ func TestManagementClient_Agent_Failures(t *testing.T) {
 	}
 	err = client.RemoveEngineFromAgent(agent.Id, engine.Id)
 	if assert.Error(t, err, "no error when removing an engine from an agent that is not attached to the agent") {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 			assert.True(t, err.StatusCode == 404, "error != 404", err.Error())
 		}
 	}
@@ -1030,7 +1032,7 @@ func TestManagementClient_Lab_Failures(t *testing.T) {
 	//Get Invalid Lab
 	_, err = client.GetLab(-1)
 	if assert.Error(t, err, "no error when trying to get an invalid lab") {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 			assert.True(t, err.StatusCode == 404, "error != 404")
 		}
 	}
@@ -1038,7 +1040,7 @@ func TestManagementClient_Lab_Failures(t *testing.T) {
 	//Delete invalid lab
 	err = client.DeleteLab(-1)
 	if assert.Error(t, err, "no error when a non existent lab was deleted") {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 			assert.True(t, err.StatusCode == 404, "error != 404")
 		}
 	}
@@ -1055,7 +1057,7 @@ func TestManagementClient_Lab_Failures(t *testing.T) {
 		//add invalid agent to lab
 		err = client.AddAgentToLab(lab.Id, -1)
 		if assert.Error(t, err, "no error when an invalid agent id was added to an lab") {
-			if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+			if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 				assert.True(t, err.StatusCode == 404, "error != 404", err.Error())
 			}
 		}
@@ -1071,7 +1073,7 @@ func TestManagementClient_Lab_Failures(t *testing.T) {
 	//add valid agent to invalid lab
 	err = client.AddAgentToLab(-1, agent.Id)
 	if assert.Error(t, err, "no error when an existent agent was added to a non existing lab") {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 			assert.True(t, err.StatusCode == 400, "error != 400")
 		}
 	}
@@ -1086,7 +1088,7 @@ func TestManagementClient_Lab_Failures(t *testing.T) {
 	//add already attached agent to lab
 	err = client.AddAgentToLab(lab.Id, agent.Id)
 	if assert.Error(t, err, "no error when an agent was added twice to an lab") {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 			assert.True(t, err.StatusCode == 400, "error != 400")
 		}
 	}
@@ -1094,7 +1096,7 @@ func TestManagementClient_Lab_Failures(t *testing.T) {
 	//remove non existing agent from non existing lab
 	err = client.RemoveAgentFromLab(-1, -1)
 	if assert.Error(t, err, "no error when removing non existing agent from non existing lab") {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 			assert.True(t, err.StatusCode == 404, "error != 404")
 		}
 	}
@@ -1102,7 +1104,7 @@ func TestManagementClient_Lab_Failures(t *testing.T) {
 	//remove non existing agent from exisiting lab
 	err = client.RemoveAgentFromLab(lab.Id, -1)
 	if assert.Error(t, err, "no error when removing non existing agent from existing lab") {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 			assert.True(t, err.StatusCode == 404, "error != 404", err.Error())
 		}
 	}
@@ -1110,7 +1112,7 @@ func TestManagementClient_Lab_Failures(t *testing.T) {
 	//remove existing agent from non exisiting lab
 	err = client.RemoveAgentFromLab(-1, agent.Id)
 	if assert.Error(t, err, "no error when removing existing agent from non existing lab") {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 			assert.True(t, err.StatusCode == 400, "error != 400", err.Error())
 		}
 	}
@@ -1121,7 +1123,7 @@ func TestManagementClient_Lab_Failures(t *testing.T) {
 	}
 	err = client.RemoveAgentFromLab(lab.Id, agent.Id)
 	if assert.Error(t, err, "no error when removing an agent from an lab that is not attached to the lab") {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 			assert.True(t, err.StatusCode == 404, "error != 404", err.Error())
 		}
 	}
@@ -1146,7 +1148,7 @@ func TestManagementClient_Engine_Failures(t *testing.T) {
 		//Create Engine with invalid params
 		_, err = client.CreateEngine("name", "this is not a valid engine id")
 		if assert.Error(t, err, "no error when an engine with an invalid engine id was created") {
-			if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+			if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 				assert.True(t, err.StatusCode == 404, "error != 404")
 			}
 		}
@@ -1155,7 +1157,7 @@ func TestManagementClient_Engine_Failures(t *testing.T) {
 	//Get Invalid Engine
 	_, err = client.GetEngine(-1)
 	if assert.Error(t, err, "no error when trying to get an invalid engine") {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 			assert.True(t, err.StatusCode == 404, "error != 404")
 		}
 	}
@@ -1163,7 +1165,7 @@ func TestManagementClient_Engine_Failures(t *testing.T) {
 	//Delete invalid engine
 	err = client.DeleteEngine(-1)
 	if assert.Error(t, err, "no error when a non existent engine was deleted") {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 			assert.True(t, err.StatusCode == 404, "error != 404")
 		}
 	}
@@ -1179,7 +1181,7 @@ func TestManagementClient_Engine_Failures(t *testing.T) {
 	/*
 		_, err = client.CreateEngine("test-Engine_Failures-engine1", "010203040507080B")
 		if assert.Error(t, err, "no error when an engine was created twice") {
-			if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+			if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 				assert.True(t, err.StatusCode == 404, "error != 404")
 			}
 		}
@@ -1190,7 +1192,7 @@ func TestManagementClient_Engine_Failures(t *testing.T) {
 		//add invalid user to engine
 		err = client.AddUserToEngine(engine.Id, -1)
 		if assert.Error(t, err, "no error when an invalid user id was added to an engine") {
-			if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+			if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 				assert.True(t, err.StatusCode == 404, "error != 404" , err.Error())
 			}
 		}
@@ -1206,7 +1208,7 @@ func TestManagementClient_Engine_Failures(t *testing.T) {
 	//add valid user to invalid engine
 	err = client.AddUserToEngine(-1, user.Id)
 	if assert.Error(t, err, "no error when an existent user was added to a non existing engine") {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 			assert.True(t, err.StatusCode == 400, "error != 400")
 		}
 	}
@@ -1221,7 +1223,7 @@ func TestManagementClient_Engine_Failures(t *testing.T) {
 	//add already attached user to engine
 	err = client.AddUserToEngine(engine.Id, user.Id)
 	if assert.Error(t, err, "no error when an user was added twice to an engine") {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 			assert.True(t, err.StatusCode == 400, "error != 400")
 		}
 	}
@@ -1229,7 +1231,7 @@ func TestManagementClient_Engine_Failures(t *testing.T) {
 	//remove non existing user from non existing engine
 	err = client.RemoveUserFromEngine(-1, -1)
 	if assert.Error(t, err, "no error when removing non existing user from non existing engine") {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 			assert.True(t, err.StatusCode == 404, "error != 404")
 		}
 	}
@@ -1237,7 +1239,7 @@ func TestManagementClient_Engine_Failures(t *testing.T) {
 	//remove non existing user from exisiting engine
 	err = client.RemoveUserFromEngine(engine.Id, -1)
 	if assert.Error(t, err, "no error when removing non existing user from existing engine") {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 			assert.True(t, err.StatusCode == 404, "error != 404", err.Error())
 		}
 	}
@@ -1245,7 +1247,7 @@ func TestManagementClient_Engine_Failures(t *testing.T) {
 	//remove existing user from non exisiting engine
 	err = client.RemoveUserFromEngine(-1, user.Id)
 	if assert.Error(t, err, "no error when removing existing user from non existing engine") {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 			assert.True(t, err.StatusCode == 400, "error != 400", err.Error())
 		}
 	}
@@ -1256,7 +1258,7 @@ func TestManagementClient_Engine_Failures(t *testing.T) {
 	}
 	err = client.RemoveUserFromEngine(engine.Id, user.Id)
 	if assert.Error(t, err, "no error when removing an user from an engine that is not attached to the engine") {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 			assert.True(t, err.StatusCode == 404, "error != 404", err.Error())
 		}
 	}
@@ -1266,7 +1268,7 @@ func TestManagementClient_Engine_Failures(t *testing.T) {
 		//add invalid user to engine
 		err = client.AddEndpointToEngine(engine.Id, -1)
 		if assert.Error(t, err, "no error when an invalid endpoint id was added to an engine") {
-			if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+			if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 				assert.True(t, err.StatusCode == 404, "error != 404" , err.Error())
 			}
 		}
@@ -1282,7 +1284,7 @@ func TestManagementClient_Engine_Failures(t *testing.T) {
 	//add valid endpoint to invalid engine
 	err = client.AddEndpointToEngine(-1, endpoint.Id)
 	if assert.Error(t, err, "no error when an existent endpoint was added to a non existing engine") {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 			assert.True(t, err.StatusCode == 400, "error != 400")
 		}
 	}
@@ -1297,7 +1299,7 @@ func TestManagementClient_Engine_Failures(t *testing.T) {
 	//add already attached endpoint to engine
 	err = client.AddEndpointToEngine(engine.Id, endpoint.Id)
 	if assert.Error(t, err, "no error when an endpoint was added twice to an engine") {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 			assert.True(t, err.StatusCode == 400, "error != 400")
 		}
 	}
@@ -1305,7 +1307,7 @@ func TestManagementClient_Engine_Failures(t *testing.T) {
 	//remove non existing endpoint from non existing engine
 	err = client.RemoveEndpointFromEngine(-1, -1)
 	if assert.Error(t, err, "no error when removing non existing endpoint from non existing engine") {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 			assert.True(t, err.StatusCode == 404, "error != 404")
 		}
 	}
@@ -1313,7 +1315,7 @@ func TestManagementClient_Engine_Failures(t *testing.T) {
 	//remove non existing endpoint from exisiting engine
 	err = client.RemoveEndpointFromEngine(engine.Id, -1)
 	if assert.Error(t, err, "no error when removing non existing endpoint from existing engine") {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 			assert.True(t, err.StatusCode == 404, "error != 404", err.Error())
 		}
 	}
@@ -1321,7 +1323,7 @@ func TestManagementClient_Engine_Failures(t *testing.T) {
 	//remove existing endpoint from non exisiting engine
 	err = client.RemoveEndpointFromEngine(-1, endpoint.Id)
 	if assert.Error(t, err, "no error when removing existing endpoint from non existing engine") {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 			assert.True(t, err.StatusCode == 400, "error != 400", err.Error())
 		}
 	}
@@ -1332,7 +1334,7 @@ func TestManagementClient_Engine_Failures(t *testing.T) {
 	}
 	err = client.RemoveEndpointFromEngine(engine.Id, endpoint.Id)
 	if assert.Error(t, err, "no error when removing an endpoint from an engine that is not attached to the engine") {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 			assert.True(t, err.StatusCode == 404, "error != 404", err.Error())
 		}
 	}
@@ -1357,7 +1359,7 @@ func TestManagementClient_User_Failures(t *testing.T) {
 		//Create User with invalid params
 		_, err = client.CreateUser("test-User_Failures-user1", "test-User_Failures-user1")
 		if assert.Error(t, err, "no error when an user with invalid params was created") {
-			if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+			if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 				assert.True(t, err.StatusCode == 404, "error != 404")
 			}
 		}
@@ -1366,7 +1368,7 @@ func TestManagementClient_User_Failures(t *testing.T) {
 	//Get Invalid User
 	_, err = client.GetUser(-1)
 	if assert.Error(t, err, "no error when trying to get an invalid user") {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 			assert.True(t, err.StatusCode == 404, "error != 404")
 		}
 	}
@@ -1374,7 +1376,7 @@ func TestManagementClient_User_Failures(t *testing.T) {
 	//Delete invalid user
 	err = client.DeleteUser(-1)
 	if assert.Error(t, err, "no error when a non existent user was deleted") {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 			assert.True(t, err.StatusCode == 404, "error != 404")
 		}
 	}
@@ -1388,7 +1390,7 @@ func TestManagementClient_User_Failures(t *testing.T) {
 
 	_, err = client.CreateUserWithTag("test-User_Failures-user1", "test-User_Failures-user1", "", "", "", "", configManagementTest.TestTagId)
 	if assert.Error(t, err, "no error when creating a user twice") {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 			assert.True(t, err.StatusCode == 400, "error != 400")
 		}
 	}
@@ -1411,7 +1413,7 @@ func TestManagementClient_Endpoint_Failures(t *testing.T) {
 	//Create Endpoint with invalid input
 	_, err = client.CreateEndpointWithTag("test-Endpoint_Failures-endpoint1", "noAddress", "no valid protocol", configManagementTest.TestTagId)
 	if assert.Error(t, err, "no error when an endpoint with invalid params was created") {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 			assert.True(t, err.StatusCode == 400, "error != 400")
 		}
 	}
@@ -1419,7 +1421,7 @@ func TestManagementClient_Endpoint_Failures(t *testing.T) {
 	//Get Invalid Endpoint
 	_, err = client.GetEndpoint(-1)
 	if assert.Error(t, err, "no error when trying to get an invalid endpoint") {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 			assert.True(t, err.StatusCode == 404, "error != 404")
 		}
 	}
@@ -1427,7 +1429,7 @@ func TestManagementClient_Endpoint_Failures(t *testing.T) {
 	//Delete invalid endpoint
 	err = client.DeleteEndpoint(-1)
 	if assert.Error(t, err, "no error when a non existent endpoint was deleted") {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 			assert.True(t, err.StatusCode == 404, "error != 404")
 		}
 	}
@@ -1441,8 +1443,224 @@ func TestManagementClient_Endpoint_Failures(t *testing.T) {
 
 	_, err = client.CreateEndpoint("test-Endpoint_Failures-endpoint1", "1.1.1.1:9753", "udpv4")
 	if assert.Error(t, err, "no error when creating a endpoint twice") {
-		if err, ok := err.(HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "error is not a http error", err.Error()) {
 			assert.True(t, err.StatusCode == 400, "error != 400")
 		}
 	}
 }
+
+func TestManagementClient_ApplyLab(t *testing.T) {
+	//Create a new api client
+	client, err := NewManagementClient(configManagementTest.Http.BaseUrl)
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+	//Set configManagementTest.Http.AuthUsername and password
+	if configManagementTest.Http.AuthUsername != "" && configManagementTest.Http.AuthPassword != "" {
+		err = client.SetUsernameAndPassword(configManagementTest.Http.AuthUsername, configManagementTest.Http.AuthPassword)
+		if !assert.NoError(t, err, "error while creating a new api client") {
+			return
+		}
+	}
+
+	//Invalid spec
+	_, err = client.ApplyLab(LabSpec{Name: "test-ApplyLab", Agents: []AgentSpec{{Name: "test-ApplyLab-agent"}, {Name: "test-ApplyLab-agent"}}})
+	assert.Error(t, err, "no error returned for a spec with duplicate agents")
+
+	spec := LabSpec{
+		Name: "test-ApplyLab",
+		Agents: []AgentSpec{
+			{
+				Name:    "test-ApplyLab-agent",
+				DataDir: configManagementTest.RootDataDir + "test-ApplyLab-agent",
+				Engines: []EngineSpec{
+					{
+						Name:     "test-ApplyLab-engine",
+						EngineId: "010203040507080B",
+						Endpoints: []EndpointSpec{
+							{
+								Name:     "test-ApplyLab-endpoint",
+								Address:  configManagementTest.Agent1.EndpointAddress + ":" + strconv.Itoa(configManagementTest.Agent1.EndpointPort[0]),
+								Protocol: configManagementTest.Protocol,
+							},
+						},
+						Users: []UserSpec{
+							{
+								User: "test-ApplyLab-user",
+								Name: "test-ApplyLab-user",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	lab, err := client.ApplyLab(spec)
+	if !assert.NoError(t, err, "error while applying lab spec") {
+		return
+	}
+	//Clean up: apply an empty lab which deletes all objects and delete the lab afterwards
+	defer func() {
+		lab, err = client.ApplyLab(LabSpec{Name: spec.Name})
+		if assert.NoError(t, err, "error while applying empty lab spec") {
+			assert.Empty(t, lab.Agents, "agents left in empty lab")
+			err = deleteLabAndCheckForSuccess(t, client, lab)
+			assert.NoError(t, err, "error during delete lab")
+		}
+		engines, err := client.GetEngines(nil)
+		if assert.NoError(t, err, "error during GetEngines()") {
+			for _, engine := range engines {
+				assert.NotEqual(t, "test-ApplyLab-engine", engine.Name, "engine was not pruned")
+			}
+		}
+	}()
+
+	if assert.Len(t, lab.Agents, 1, "wrong number of agents in applied lab") {
+		agent := lab.Agents[0]
+		assert.Equal(t, spec.Agents[0].Name, agent.Name)
+		assert.Equal(t, spec.Agents[0].DataDir, agent.DataDir)
+		if assert.Len(t, agent.Engines, 1, "wrong number of engines in applied agent") {
+			engine := agent.Engines[0]
+			assert.Equal(t, spec.Agents[0].Engines[0].Name, engine.Name)
+			if assert.Len(t, engine.Endpoints, 1, "wrong number of endpoints in applied engine") {
+				assert.Equal(t, spec.Agents[0].Engines[0].Endpoints[0].Address, engine.Endpoints[0].Address)
+			}
+			assert.Len(t, engine.Users, 1, "wrong number of users in applied engine")
+		}
+	}
+
+	//Applying the same spec again does not change anything
+	sameLab, err := client.ApplyLab(spec)
+	if assert.NoError(t, err, "error while applying lab spec a second time") {
+		assert.Equal(t, lab, sameLab, "lab was changed by applying the same spec twice")
+	}
+
+	//Export the lab into a manifest
+	manifest, err := client.ExportLab(lab.Id)
+	if assert.NoError(t, err, "error while exporting lab") && assert.Len(t, manifest.Labs, 1) {
+		exported := manifest.Labs[0]
+		assert.Equal(t, spec.Name, exported.Name)
+		if assert.Len(t, exported.Agents, 1) && assert.Len(t, exported.Agents[0].Engines, 1) {
+			engine := exported.Agents[0].Engines[0]
+			assert.Equal(t, spec.Agents[0].Engines[0].EngineId, engine.EngineId)
+			assert.Equal(t, spec.Agents[0].Engines[0].Endpoints[0].Address, engine.Endpoints[0].Address)
+			assert.Equal(t, spec.Agents[0].Engines[0].Users[0].User, engine.Users[0].User)
+		}
+		//Applying the exported manifest does not change anything
+		labs, err := client.ApplyManifest(manifest)
+		if assert.NoError(t, err, "error while applying exported manifest") && assert.Len(t, labs, 1) {
+			assert.Equal(t, lab, labs[0], "lab was changed by applying the exported manifest")
+		}
+	}
+
+	//Change the data dir of the agent and remove the user
+	spec.Agents[0].DataDir = configManagementTest.RootDataDir + "test-ApplyLab-agent-changed"
+	spec.Agents[0].Engines[0].Users = nil
+	lab, err = client.ApplyLab(spec)
+	if assert.NoError(t, err, "error while applying changed lab spec") && assert.Len(t, lab.Agents, 1) {
+		assert.Equal(t, spec.Agents[0].DataDir, lab.Agents[0].DataDir)
+		if assert.Len(t, lab.Agents[0].Engines, 1) {
+			assert.Empty(t, lab.Agents[0].Engines[0].Users, "user was not removed from engine")
+		}
+	}
+	users, err := client.GetUsers(nil)
+	if assert.NoError(t, err, "error during GetUsers()") {
+		for _, user := range users {
+			assert.NotEqual(t, "test-ApplyLab-user", user.User, "user was not pruned")
+		}
+	}
+}
+
+func TestManagementClient_Update(t *testing.T) {
+	//Create a new api client
+	client, err := NewManagementClient(configManagementTest.Http.BaseUrl)
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+	//Set configManagementTest.Http.AuthUsername and password
+	if configManagementTest.Http.AuthUsername != "" && configManagementTest.Http.AuthPassword != "" {
+		err = client.SetUsernameAndPassword(configManagementTest.Http.AuthUsername, configManagementTest.Http.AuthPassword)
+		if !assert.NoError(t, err, "error while creating a new api client") {
+			return
+		}
+	}
+
+	//Lab
+	lab, err := createLabAndCheckForSuccess(t, client, "test-Update-lab")
+	if err != nil {
+		return
+	}
+	defer func() { _ = deleteLabAndCheckForSuccess(t, client, lab) }()
+	lab, err = client.UpdateLab(lab.Id, LabUpdate{Name: String("test-Update-lab-renamed")})
+	if assert.NoError(t, err, "error while updating lab") {
+		assert.Equal(t, "test-Update-lab-renamed", lab.Name)
+	}
+
+	//Agent
+	agent, err := createAgentAndCheckForSuccess(t, client, "test-Update-agent", configManagementTest.RootDataDir+"test-Update-agent")
+	if err != nil {
+		return
+	}
+	defer func() { _ = deleteAgentAndCheckForSuccess(t, client, agent) }()
+	agent, err = client.UpdateAgent(agent.Id, AgentUpdate{DataDir: String(configManagementTest.RootDataDir + "test-Update-agent-moved")})
+	if assert.NoError(t, err, "error while updating agent") {
+		assert.Equal(t, "test-Update-agent", agent.Name)
+		assert.Equal(t, configManagementTest.RootDataDir+"test-Update-agent-moved", agent.DataDir)
+	}
+
+	//Engine
+	engine, err := createEngineAndCheckForSuccess(t, client, "test-Update-engine", "010203040507080C")
+	if err != nil {
+		return
+	}
+	defer func() { _ = deleteEngineAndCheckForSuccess(t, client, engine) }()
+	engine, err = client.UpdateEngine(engine.Id, EngineUpdate{Name: String("test-Update-engine-renamed")})
+	if assert.NoError(t, err, "error while updating engine") {
+		assert.Equal(t, "test-Update-engine-renamed", engine.Name)
+		assert.Equal(t, "010203040507080C", engine.EngineId)
+	}
+
+	//Endpoint
+	endpoint, err := createEndpointAndCheckForSuccess(t, client, "test-Update-endpoint", configManagementTest.Agent1.EndpointAddress+":"+strconv.Itoa(configManagementTest.Agent1.EndpointPort[0]), configManagementTest.Protocol)
+	if err != nil {
+		return
+	}
+	defer func() { _ = deleteEndpointAndCheckForSuccess(t, client, endpoint) }()
+	address := configManagementTest.Agent1.EndpointAddress + ":" + strconv.Itoa(configManagementTest.Agent1.EndpointPort[0]+1)
+	endpoint, err = client.UpdateEndpoint(endpoint.Id, EndpointUpdate{Address: &address})
+	if assert.NoError(t, err, "error while updating endpoint") {
+		assert.Equal(t, address, endpoint.Address)
+		assert.Equal(t, configManagementTest.Protocol, endpoint.Protocol)
+	}
+
+	//User
+	user, err := createUserAndCheckForSuccess(t, client, "test-Update-user", "test-Update-user", "authkey1", "md5", "privkey1", "des")
+	if err != nil {
+		return
+	}
+	defer func() { _ = deleteUserAndCheckForSuccess(t, client, user) }()
+	user, err = client.UpdateUser(user.Id, UserUpdate{AuthKey: String("authkey2"), PrivKey: String("privkey2")})
+	if assert.NoError(t, err, "error while updating user") {
+		assert.Equal(t, "test-Update-user", user.Name)
+		assert.Equal(t, "authkey2", user.AuthKey)
+		assert.Equal(t, "md5", user.AuthProto)
+		assert.Equal(t, "privkey2", user.PrivKey)
+	}
+
+	//Tag
+	tag, err := createTagAndCheckForSuccess(t, client, "test-Update-tag", "description")
+	if err != nil {
+		return
+	}
+	defer func() { _ = deleteTagAndCheckForSuccess(t, client, tag) }()
+	tag, err = client.UpdateTag(tag.Id, TagUpdate{Description: String("new description")})
+	if assert.NoError(t, err, "error while updating tag") {
+		assert.Equal(t, "test-Update-tag", tag.Name)
+		assert.Equal(t, "new description", tag.Description)
+	}
+
+	//Invalid object
+	_, err = client.UpdateLab(-1, LabUpdate{Name: String("test-Update-lab-invalid")})
+	assert.True(t, errors.Is(err, ErrNotFound), "error does not match ErrNotFound")
+}
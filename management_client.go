@@ -1,10 +1,12 @@
 package snmpsimclient
 
 import (
+	"context"
 	"encoding/json"
-	"github.com/go-resty/resty/v2"
+	"github.com/inexio/snmpsim-restapi-go-client/snmprec"
 	"github.com/pkg/errors"
 	"io/ioutil"
+	"os"
 	"strconv"
 	"strings"
 )
@@ -18,19 +20,14 @@ type ManagementClient struct {
 
 /*
 NewManagementClient creates a new ManagementClient.
+Options can be given to configure the http transport, e.g. timeouts, tls or a proxy.
 */
-func NewManagementClient(baseUrl string) (*ManagementClient, error) {
-	if baseUrl == "" {
-		return nil, errors.New("invalid base url")
-	}
-
-	//if baseUrl does not end with an "/" it has to be added to the string
-	if lastChar := baseUrl[len(baseUrl)-1:]; lastChar != "/" {
-		baseUrl += "/"
+func NewManagementClient(baseUrl string, opts ...ClientOption) (*ManagementClient, error) {
+	clientData, err := newClientData(baseUrl, opts)
+	if err != nil {
+		return nil, err
 	}
-	clientData := clientData{baseUrl: baseUrl, resty: resty.New(), useAuth: false}
-	newClient := client{&clientData}
-	return &ManagementClient{newClient}, nil
+	return &ManagementClient{client{clientData}}, nil
 }
 
 /*
@@ -41,22 +38,34 @@ LABS
 GetLabs returns a list of labs, optionally filtered.
 */
 func (c *ManagementClient) GetLabs(filter map[string]string) (Labs, error) {
-	if !c.isValid() {
-		return nil, &NotValidError{}
-	}
+	return c.GetLabsContext(context.Background(), filter)
+}
 
-	response, err := c.request("GET", mgmtEndpointPath+"labs", "", nil, filter)
-	if err != nil {
-		return nil, errors.Wrap(err, "error during search labs request")
-	}
-	if response.StatusCode() != 200 {
-		return nil, getHttpError(response)
+/*
+GetLabsContext is like GetLabs but carries the given context through to the http request.
+*/
+func (c *ManagementClient) GetLabsContext(ctx context.Context, filter map[string]string) (Labs, error) {
+	var labs Labs
+	if err := c.list(ctx, labResource, filter, &labs); err != nil {
+		return nil, err
 	}
+	return labs, nil
+}
 
+/*
+SearchLabs returns all labs matching the given filter.
+*/
+func (c *ManagementClient) SearchLabs(filter LabFilter) (Labs, error) {
+	return c.SearchLabsContext(context.Background(), filter)
+}
+
+/*
+SearchLabsContext is like SearchLabs but carries the given context through to the http request.
+*/
+func (c *ManagementClient) SearchLabsContext(ctx context.Context, filter LabFilter) (Labs, error) {
 	var labs Labs
-	err = json.Unmarshal(response.Body(), &labs)
-	if err != nil {
-		return nil, errors.Wrap(err, "error during unmarshalling http response")
+	if err := c.search(ctx, labResource, filter, &labs); err != nil {
+		return nil, err
 	}
 	return labs, nil
 }
@@ -65,22 +74,16 @@ func (c *ManagementClient) GetLabs(filter map[string]string) (Labs, error) {
 GetLab returns the lab with the given id.
 */
 func (c *ManagementClient) GetLab(id int) (Lab, error) {
-	if !c.isValid() {
-		return Lab{}, &NotValidError{}
-	}
-
-	response, err := c.request("GET", mgmtEndpointPath+"labs/"+strconv.Itoa(id), "", nil, nil)
-	if err != nil {
-		return Lab{}, errors.Wrap(err, "error during get labs request")
-	}
-	if response.StatusCode() != 200 {
-		return Lab{}, getHttpError(response)
-	}
+	return c.GetLabContext(context.Background(), id)
+}
 
+/*
+GetLabContext is like GetLab but carries the given context through to the http request.
+*/
+func (c *ManagementClient) GetLabContext(ctx context.Context, id int) (Lab, error) {
 	var lab Lab
-	err = json.Unmarshal(response.Body(), &lab)
-	if err != nil {
-		return Lab{}, errors.Wrap(err, "error during unmarshalling http response")
+	if err := c.get(ctx, labResource, id, &lab); err != nil {
+		return Lab{}, err
 	}
 	return lab, nil
 }
@@ -89,21 +92,31 @@ func (c *ManagementClient) GetLab(id int) (Lab, error) {
 CreateLab creates a new lab.
 */
 func (c *ManagementClient) CreateLab(name string) (Lab, error) {
-	return c.createLab(&name, nil)
+	return c.CreateLabContext(context.Background(), name)
+}
+
+/*
+CreateLabContext is like CreateLab but carries the given context through to the http request.
+*/
+func (c *ManagementClient) CreateLabContext(ctx context.Context, name string) (Lab, error) {
+	return c.createLab(ctx, &name, nil)
 }
 
 /*
 CreateLabWithTag creates a new lab tagged with the given tag.
 */
 func (c *ManagementClient) CreateLabWithTag(name string, tagId int) (Lab, error) {
-	return c.createLab(&name, &tagId)
+	return c.CreateLabWithTagContext(context.Background(), name, tagId)
 }
 
-func (c *ManagementClient) createLab(name *string, tagId *int) (Lab, error) {
-	if !c.isValid() {
-		return Lab{}, &NotValidError{}
-	}
+/*
+CreateLabWithTagContext is like CreateLabWithTag but carries the given context through to the http request.
+*/
+func (c *ManagementClient) CreateLabWithTagContext(ctx context.Context, name string, tagId int) (Lab, error) {
+	return c.createLab(ctx, &name, &tagId)
+}
 
+func (c *ManagementClient) createLab(ctx context.Context, name *string, tagId *int) (Lab, error) {
 	if *name == "" {
 		return Lab{}, errors.New("invalid name")
 	}
@@ -113,29 +126,9 @@ func (c *ManagementClient) createLab(name *string, tagId *int) (Lab, error) {
 	}
 
 	params := requestParams{*name}
-	jsonString, err := json.Marshal(params)
-	if err != nil {
-		return Lab{}, errors.Wrap(err, "error during marshal")
-	}
-
-	path := mgmtEndpointPath + "labs"
-	if tagId != nil {
-		path = mgmtEndpointPath + "tags/" + strconv.Itoa(*tagId) + "/lab"
-	}
-
-	response, err := c.request("POST", path, string(jsonString), nil, nil)
-
-	if err != nil {
-		return Lab{}, errors.Wrap(err, "error during add lab request")
-	}
-	if response.StatusCode() != 201 { //TODO: right error code?
-		return Lab{}, getHttpError(response)
-	}
-
 	var lab Lab
-	err = json.Unmarshal(response.Body(), &lab)
-	if err != nil {
-		return Lab{}, errors.Wrap(err, "error during unmarshalling http response")
+	if err := c.create(ctx, labResource, tagId, params, &lab); err != nil {
+		return Lab{}, err
 	}
 	return lab, nil
 }
@@ -144,120 +137,111 @@ func (c *ManagementClient) createLab(name *string, tagId *int) (Lab, error) {
 DeleteLab deletes the Lab with the given id.
 */
 func (c *ManagementClient) DeleteLab(id int) error {
-	if !c.isValid() {
-		return &NotValidError{}
-	}
+	return c.DeleteLabContext(context.Background(), id)
+}
 
-	response, err := c.request("DELETE", mgmtEndpointPath+"labs/"+strconv.Itoa(id), "", nil, nil)
-	if err != nil {
-		return errors.Wrap(err, "error during request")
-	}
-	if response.StatusCode() != 204 {
-		return getHttpError(response)
+/*
+DeleteLabContext is like DeleteLab but carries the given context through to the http request.
+*/
+func (c *ManagementClient) DeleteLabContext(ctx context.Context, id int) error {
+	return c.delete(ctx, labResource, id)
+}
+
+/*
+UpdateLab changes the attributes of the lab with the given id which are set in the update and returns the updated lab.
+*/
+func (c *ManagementClient) UpdateLab(id int, update LabUpdate) (Lab, error) {
+	return c.UpdateLabContext(context.Background(), id, update)
+}
+
+/*
+UpdateLabContext is like UpdateLab but carries the given context through to the http request.
+*/
+func (c *ManagementClient) UpdateLabContext(ctx context.Context, id int, update LabUpdate) (Lab, error) {
+	var lab Lab
+	if err := c.update(ctx, labResource, id, update, &lab); err != nil {
+		return Lab{}, err
 	}
-	return nil
+	return lab, nil
 }
 
 /*
 AddAgentToLab adds an Agent to a Lab.
 */
 func (c *ManagementClient) AddAgentToLab(labId, agentId int) error {
-	if !c.isValid() {
-		return &NotValidError{}
-	}
-
-	response, err := c.request("PUT", mgmtEndpointPath+"labs/"+strconv.Itoa(labId)+"/agent/"+strconv.Itoa(agentId), "", nil, nil)
-	if err != nil {
-		return errors.Wrap(err, "error during request")
-	}
+	return c.AddAgentToLabContext(context.Background(), labId, agentId)
+}
 
-	if response.StatusCode() != 200 {
-		return getHttpError(response)
-	}
-	return nil
+/*
+AddAgentToLabContext is like AddAgentToLab but carries the given context through to the http request.
+*/
+func (c *ManagementClient) AddAgentToLabContext(ctx context.Context, labId, agentId int) error {
+	return c.link(ctx, labResource, labId, agentResource, agentId, nil)
 }
 
 /*
 RemoveAgentFromLab removes an Agent from a Lab.
 */
 func (c *ManagementClient) RemoveAgentFromLab(labId, agentId int) error {
-	if !c.isValid() {
-		return &NotValidError{}
-	}
-
-	response, err := c.request("DELETE", mgmtEndpointPath+"labs/"+strconv.Itoa(labId)+"/agent/"+strconv.Itoa(agentId), "", nil, nil)
-	if err != nil {
-		return errors.Wrap(err, "error during request")
-	}
+	return c.RemoveAgentFromLabContext(context.Background(), labId, agentId)
+}
 
-	if response.StatusCode() != 204 {
-		return getHttpError(response)
-	}
-	return nil
+/*
+RemoveAgentFromLabContext is like RemoveAgentFromLab but carries the given context through to the http request.
+*/
+func (c *ManagementClient) RemoveAgentFromLabContext(ctx context.Context, labId, agentId int) error {
+	return c.unlink(ctx, labResource, labId, agentResource, agentId)
 }
 
 /*
 SetLabPower activates or deactivates a lab.
 */
 func (c *ManagementClient) SetLabPower(labId int, power bool) error {
-	if !c.isValid() {
-		return &NotValidError{}
-	}
-
-	var labPowerState string
+	return c.SetLabPowerContext(context.Background(), labId, power)
+}
 
+/*
+SetLabPowerContext is like SetLabPower but carries the given context through to the http request.
+*/
+func (c *ManagementClient) SetLabPowerContext(ctx context.Context, labId int, power bool) error {
+	labPowerState := "off"
 	if power {
 		labPowerState = "on"
-	} else {
-		labPowerState = "off"
-	}
-
-	response, err := c.request("PUT", mgmtEndpointPath+"labs/"+strconv.Itoa(labId)+"/power/"+labPowerState, "", nil, nil)
-	if err != nil {
-		return errors.Wrap(err, "error during request")
 	}
-
-	if response.StatusCode() != 200 {
-		return getHttpError(response)
-	}
-
-	return nil
+	return c.do(ctx, apiCall{
+		method:         "PUT",
+		path:           labResource.objectPath(labId) + "/power/" + labPowerState,
+		description:    "set lab power",
+		expectedStatus: 200,
+	})
 }
 
 /*
 AddTagToLab adds a tag to a lab.
 */
 func (c *ManagementClient) AddTagToLab(labId, tagId int) error {
-	if !c.isValid() {
-		return &NotValidError{}
-	}
-	response, err := c.request("PUT", mgmtEndpointPath+"tags/"+strconv.Itoa(tagId)+"/lab/"+strconv.Itoa(labId), "", nil, nil)
-	if err != nil {
-		return errors.Wrap(err, "error during request")
-	}
+	return c.AddTagToLabContext(context.Background(), labId, tagId)
+}
 
-	if response.StatusCode() != 200 {
-		return getHttpError(response)
-	}
-	return nil
+/*
+AddTagToLabContext is like AddTagToLab but carries the given context through to the http request.
+*/
+func (c *ManagementClient) AddTagToLabContext(ctx context.Context, labId, tagId int) error {
+	return c.tag(ctx, labResource, labId, tagId)
 }
 
 /*
 RemoveTagFromLab removes a tag from a lab.
 */
 func (c *ManagementClient) RemoveTagFromLab(labId, tagId int) error {
-	if !c.isValid() {
-		return &NotValidError{}
-	}
-	response, err := c.request("DELETE", mgmtEndpointPath+"tags/"+strconv.Itoa(tagId)+"/lab/"+strconv.Itoa(labId), "", nil, nil)
-	if err != nil {
-		return errors.Wrap(err, "error during request")
-	}
+	return c.RemoveTagFromLabContext(context.Background(), labId, tagId)
+}
 
-	if response.StatusCode() != 200 {
-		return getHttpError(response)
-	}
-	return nil
+/*
+RemoveTagFromLabContext is like RemoveTagFromLab but carries the given context through to the http request.
+*/
+func (c *ManagementClient) RemoveTagFromLabContext(ctx context.Context, labId, tagId int) error {
+	return c.untag(ctx, labResource, labId, tagId)
 }
 
 /*
@@ -268,22 +252,34 @@ ENGINES
 GetEngines returns a list of all engines.
 */
 func (c *ManagementClient) GetEngines(filter map[string]string) (Engines, error) {
-	if !c.isValid() {
-		return nil, &NotValidError{}
-	}
+	return c.GetEnginesContext(context.Background(), filter)
+}
 
-	response, err := c.request("GET", mgmtEndpointPath+"engines", "", nil, filter)
-	if err != nil {
-		return nil, errors.Wrap(err, "error during get engines request")
-	}
-	if response.StatusCode() != 200 {
-		return nil, getHttpError(response)
+/*
+GetEnginesContext is like GetEngines but carries the given context through to the http request.
+*/
+func (c *ManagementClient) GetEnginesContext(ctx context.Context, filter map[string]string) (Engines, error) {
+	var engines Engines
+	if err := c.list(ctx, engineResource, filter, &engines); err != nil {
+		return nil, err
 	}
+	return engines, nil
+}
 
+/*
+SearchEngines returns all engines matching the given filter.
+*/
+func (c *ManagementClient) SearchEngines(filter EngineFilter) (Engines, error) {
+	return c.SearchEnginesContext(context.Background(), filter)
+}
+
+/*
+SearchEnginesContext is like SearchEngines but carries the given context through to the http request.
+*/
+func (c *ManagementClient) SearchEnginesContext(ctx context.Context, filter EngineFilter) (Engines, error) {
 	var engines Engines
-	err = json.Unmarshal(response.Body(), &engines)
-	if err != nil {
-		return nil, errors.Wrap(err, "error during unmarshalling http response")
+	if err := c.search(ctx, engineResource, filter, &engines); err != nil {
+		return nil, err
 	}
 	return engines, nil
 }
@@ -292,22 +288,16 @@ func (c *ManagementClient) GetEngines(filter map[string]string) (Engines, error)
 GetEngine returns the engine with the given id.
 */
 func (c *ManagementClient) GetEngine(id int) (Engine, error) {
-	if !c.isValid() {
-		return Engine{}, &NotValidError{}
-	}
-
-	response, err := c.request("GET", mgmtEndpointPath+"engines/"+strconv.Itoa(id), "", nil, nil)
-	if err != nil {
-		return Engine{}, errors.Wrap(err, "error during get labs request")
-	}
-	if response.StatusCode() != 200 {
-		return Engine{}, getHttpError(response)
-	}
+	return c.GetEngineContext(context.Background(), id)
+}
 
+/*
+GetEngineContext is like GetEngine but carries the given context through to the http request.
+*/
+func (c *ManagementClient) GetEngineContext(ctx context.Context, id int) (Engine, error) {
 	var engine Engine
-	err = json.Unmarshal(response.Body(), &engine)
-	if err != nil {
-		return Engine{}, errors.Wrap(err, "error during unmarshalling http response")
+	if err := c.get(ctx, engineResource, id, &engine); err != nil {
+		return Engine{}, err
 	}
 	return engine, nil
 }
@@ -316,20 +306,31 @@ func (c *ManagementClient) GetEngine(id int) (Engine, error) {
 CreateEngine creates a new engine.
 */
 func (c *ManagementClient) CreateEngine(name, engineId string) (Engine, error) {
-	return c.createEngine(&name, &engineId, nil)
+	return c.CreateEngineContext(context.Background(), name, engineId)
+}
+
+/*
+CreateEngineContext is like CreateEngine but carries the given context through to the http request.
+*/
+func (c *ManagementClient) CreateEngineContext(ctx context.Context, name, engineId string) (Engine, error) {
+	return c.createEngine(ctx, &name, &engineId, nil)
 }
 
 /*
 CreateEngineWithTag creates a new engine tagged with the given tag.
 */
 func (c *ManagementClient) CreateEngineWithTag(name, engineId string, tagId int) (Engine, error) {
-	return c.createEngine(&name, &engineId, &tagId)
+	return c.CreateEngineWithTagContext(context.Background(), name, engineId, tagId)
 }
 
-func (c *ManagementClient) createEngine(name, engineId *string, tagId *int) (Engine, error) {
-	if !c.isValid() {
-		return Engine{}, &NotValidError{}
-	}
+/*
+CreateEngineWithTagContext is like CreateEngineWithTag but carries the given context through to the http request.
+*/
+func (c *ManagementClient) CreateEngineWithTagContext(ctx context.Context, name, engineId string, tagId int) (Engine, error) {
+	return c.createEngine(ctx, &name, &engineId, &tagId)
+}
+
+func (c *ManagementClient) createEngine(ctx context.Context, name, engineId *string, tagId *int) (Engine, error) {
 	if *name == "" {
 		return Engine{}, errors.New("invalid name")
 	}
@@ -345,27 +346,9 @@ func (c *ManagementClient) createEngine(name, engineId *string, tagId *int) (Eng
 	}
 
 	params := requestParams{*name, *engineId}
-	jsonString, err := json.Marshal(params)
-	if err != nil {
-		return Engine{}, errors.Wrap(err, "error during marshal")
-	}
-
-	path := mgmtEndpointPath + "engines"
-	if tagId != nil {
-		path = mgmtEndpointPath + "tags/" + strconv.Itoa(*tagId) + "/engine"
-	}
-
-	response, err := c.request("POST", path, string(jsonString), nil, nil)
-	if err != nil {
-		return Engine{}, errors.Wrap(err, "error during request")
-	}
-	if response.StatusCode() != 201 {
-		return Engine{}, getHttpError(response)
-	}
 	var engine Engine
-	err = json.Unmarshal(response.Body(), &engine)
-	if err != nil {
-		return Engine{}, errors.Wrap(err, "error during unmarshalling http response")
+	if err := c.create(ctx, engineResource, tagId, params, &engine); err != nil {
+		return Engine{}, err
 	}
 	return engine, nil
 }
@@ -374,133 +357,116 @@ func (c *ManagementClient) createEngine(name, engineId *string, tagId *int) (Eng
 DeleteEngine deletes the engine with the given id.
 */
 func (c *ManagementClient) DeleteEngine(id int) error {
-	if !c.isValid() {
-		return &NotValidError{}
-	}
+	return c.DeleteEngineContext(context.Background(), id)
+}
 
-	response, err := c.request("DELETE", mgmtEndpointPath+"engines/"+strconv.Itoa(id), "", nil, nil)
-	if err != nil {
-		return errors.Wrap(err, "error during request")
-	}
-	if response.StatusCode() != 204 {
-		return getHttpError(response)
-	}
-	return nil
+/*
+DeleteEngineContext is like DeleteEngine but carries the given context through to the http request.
+*/
+func (c *ManagementClient) DeleteEngineContext(ctx context.Context, id int) error {
+	return c.delete(ctx, engineResource, id)
 }
 
 /*
-AddUserToEngine adds an User to an Engine
+UpdateEngine changes the attributes of the engine with the given id which are set in the update and returns the updated engine.
 */
-func (c *ManagementClient) AddUserToEngine(engineId, userId int) error {
-	if !c.isValid() {
-		return &NotValidError{}
-	}
+func (c *ManagementClient) UpdateEngine(id int, update EngineUpdate) (Engine, error) {
+	return c.UpdateEngineContext(context.Background(), id, update)
+}
 
-	response, err := c.request("PUT", mgmtEndpointPath+"engines/"+strconv.Itoa(engineId)+"/user/"+strconv.Itoa(userId), "", nil, nil)
-	if err != nil {
-		return errors.Wrap(err, "error during request")
+/*
+UpdateEngineContext is like UpdateEngine but carries the given context through to the http request.
+*/
+func (c *ManagementClient) UpdateEngineContext(ctx context.Context, id int, update EngineUpdate) (Engine, error) {
+	var engine Engine
+	if err := c.update(ctx, engineResource, id, update, &engine); err != nil {
+		return Engine{}, err
 	}
+	return engine, nil
+}
 
-	if response.StatusCode() != 200 {
-		return getHttpError(response)
-	}
+/*
+AddUserToEngine adds an User to an Engine
+*/
+func (c *ManagementClient) AddUserToEngine(engineId, userId int) error {
+	return c.AddUserToEngineContext(context.Background(), engineId, userId)
+}
 
-	return nil
+/*
+AddUserToEngineContext is like AddUserToEngine but carries the given context through to the http request.
+*/
+func (c *ManagementClient) AddUserToEngineContext(ctx context.Context, engineId, userId int) error {
+	return c.link(ctx, engineResource, engineId, userResource, userId, nil)
 }
 
 /*
 RemoveUserFromEngine removes an User from an Engine.
 */
 func (c *ManagementClient) RemoveUserFromEngine(engineId, userId int) error {
-	if !c.isValid() {
-		return &NotValidError{}
-	}
-
-	response, err := c.request("DELETE", mgmtEndpointPath+"engines/"+strconv.Itoa(engineId)+"/user/"+strconv.Itoa(userId), "", nil, nil)
-	if err != nil {
-		return errors.Wrap(err, "error during request")
-	}
-
-	if response.StatusCode() != 204 {
-		return getHttpError(response)
-	}
+	return c.RemoveUserFromEngineContext(context.Background(), engineId, userId)
+}
 
-	return nil
+/*
+RemoveUserFromEngineContext is like RemoveUserFromEngine but carries the given context through to the http request.
+*/
+func (c *ManagementClient) RemoveUserFromEngineContext(ctx context.Context, engineId, userId int) error {
+	return c.unlink(ctx, engineResource, engineId, userResource, userId)
 }
 
 /*
 AddEndpointToEngine adds an Endpoint to an Engine.
 */
 func (c *ManagementClient) AddEndpointToEngine(engineId, endpointId int) error {
-	if !c.isValid() {
-		return &NotValidError{}
-	}
-
-	response, err := c.request("PUT", mgmtEndpointPath+"engines/"+strconv.Itoa(engineId)+"/endpoint/"+strconv.Itoa(endpointId), "", nil, nil)
-	if err != nil {
-		return errors.Wrap(err, "error during request")
-	}
-
-	if response.StatusCode() != 200 {
-		return getHttpError(response)
-	}
+	return c.AddEndpointToEngineContext(context.Background(), engineId, endpointId)
+}
 
-	return nil
+/*
+AddEndpointToEngineContext is like AddEndpointToEngine but carries the given context through to the http request.
+*/
+func (c *ManagementClient) AddEndpointToEngineContext(ctx context.Context, engineId, endpointId int) error {
+	return c.link(ctx, engineResource, engineId, endpointResource, endpointId, nil)
 }
 
 /*
 RemoveEndpointFromEngine removes an Endpoint from an Engine.
 */
 func (c *ManagementClient) RemoveEndpointFromEngine(engineId, endpointId int) error {
-	if !c.isValid() {
-		return &NotValidError{}
-	}
-
-	response, err := c.request("DELETE", mgmtEndpointPath+"engines/"+strconv.Itoa(engineId)+"/endpoint/"+strconv.Itoa(endpointId), "", nil, nil)
-	if err != nil {
-		return errors.Wrap(err, "error during request")
-	}
+	return c.RemoveEndpointFromEngineContext(context.Background(), engineId, endpointId)
+}
 
-	if response.StatusCode() != 204 {
-		return getHttpError(response)
-	}
-	return nil
+/*
+RemoveEndpointFromEngineContext is like RemoveEndpointFromEngine but carries the given context through to the http request.
+*/
+func (c *ManagementClient) RemoveEndpointFromEngineContext(ctx context.Context, engineId, endpointId int) error {
+	return c.unlink(ctx, engineResource, engineId, endpointResource, endpointId)
 }
 
 /*
 AddTagToEngine adds a tag to a engine.
 */
 func (c *ManagementClient) AddTagToEngine(engineId, tagId int) error {
-	if !c.isValid() {
-		return &NotValidError{}
-	}
-	response, err := c.request("PUT", mgmtEndpointPath+"tags/"+strconv.Itoa(tagId)+"/engine/"+strconv.Itoa(engineId), "", nil, nil)
-	if err != nil {
-		return errors.Wrap(err, "error during request")
-	}
+	return c.AddTagToEngineContext(context.Background(), engineId, tagId)
+}
 
-	if response.StatusCode() != 200 {
-		return getHttpError(response)
-	}
-	return nil
+/*
+AddTagToEngineContext is like AddTagToEngine but carries the given context through to the http request.
+*/
+func (c *ManagementClient) AddTagToEngineContext(ctx context.Context, engineId, tagId int) error {
+	return c.tag(ctx, engineResource, engineId, tagId)
 }
 
 /*
 RemoveTagFromEngine removes a tag from a engine.
 */
 func (c *ManagementClient) RemoveTagFromEngine(engineId, tagId int) error {
-	if !c.isValid() {
-		return &NotValidError{}
-	}
-	response, err := c.request("DELETE", mgmtEndpointPath+"tags/"+strconv.Itoa(tagId)+"/engine/"+strconv.Itoa(engineId), "", nil, nil)
-	if err != nil {
-		return errors.Wrap(err, "error during request")
-	}
+	return c.RemoveTagFromEngineContext(context.Background(), engineId, tagId)
+}
 
-	if response.StatusCode() != 200 {
-		return getHttpError(response)
-	}
-	return nil
+/*
+RemoveTagFromEngineContext is like RemoveTagFromEngine but carries the given context through to the http request.
+*/
+func (c *ManagementClient) RemoveTagFromEngineContext(ctx context.Context, engineId, tagId int) error {
+	return c.untag(ctx, engineResource, engineId, tagId)
 }
 
 /*
@@ -511,22 +477,34 @@ AGENTS
 GetAgents returns a list of agents, optionally filtered.
 */
 func (c *ManagementClient) GetAgents(filters map[string]string) (Agents, error) {
-	if !c.isValid() {
-		return nil, &NotValidError{}
-	}
+	return c.GetAgentsContext(context.Background(), filters)
+}
 
-	response, err := c.request("GET", mgmtEndpointPath+"agents", "", nil, filters)
-	if err != nil {
-		return nil, errors.Wrap(err, "error during get agents request")
-	}
-	if response.StatusCode() != 200 {
-		return nil, getHttpError(response)
+/*
+GetAgentsContext is like GetAgents but carries the given context through to the http request.
+*/
+func (c *ManagementClient) GetAgentsContext(ctx context.Context, filters map[string]string) (Agents, error) {
+	var agents Agents
+	if err := c.list(ctx, agentResource, filters, &agents); err != nil {
+		return nil, err
 	}
+	return agents, nil
+}
+
+/*
+SearchAgents returns all agents matching the given filter.
+*/
+func (c *ManagementClient) SearchAgents(filter AgentFilter) (Agents, error) {
+	return c.SearchAgentsContext(context.Background(), filter)
+}
 
+/*
+SearchAgentsContext is like SearchAgents but carries the given context through to the http request.
+*/
+func (c *ManagementClient) SearchAgentsContext(ctx context.Context, filter AgentFilter) (Agents, error) {
 	var agents Agents
-	err = json.Unmarshal(response.Body(), &agents)
-	if err != nil {
-		return nil, errors.Wrap(err, "error during unmarshalling http response")
+	if err := c.search(ctx, agentResource, filter, &agents); err != nil {
+		return nil, err
 	}
 	return agents, nil
 }
@@ -535,22 +513,16 @@ func (c *ManagementClient) GetAgents(filters map[string]string) (Agents, error)
 GetAgent returns the agent with the given id.
 */
 func (c *ManagementClient) GetAgent(id int) (Agent, error) {
-	if !c.isValid() {
-		return Agent{}, &NotValidError{}
-	}
-
-	response, err := c.request("GET", mgmtEndpointPath+"agents/"+strconv.Itoa(id), "", nil, nil)
-	if err != nil {
-		return Agent{}, errors.Wrap(err, "error during get labs request")
-	}
-	if response.StatusCode() != 200 {
-		return Agent{}, getHttpError(response)
-	}
+	return c.GetAgentContext(context.Background(), id)
+}
 
+/*
+GetAgentContext is like GetAgent but carries the given context through to the http request.
+*/
+func (c *ManagementClient) GetAgentContext(ctx context.Context, id int) (Agent, error) {
 	var agent Agent
-	err = json.Unmarshal(response.Body(), &agent)
-	if err != nil {
-		return Agent{}, errors.Wrap(err, "error during unmarshalling http response")
+	if err := c.get(ctx, agentResource, id, &agent); err != nil {
+		return Agent{}, err
 	}
 	return agent, nil
 }
@@ -559,21 +531,31 @@ func (c *ManagementClient) GetAgent(id int) (Agent, error) {
 CreateAgent creates a new agent.
 */
 func (c *ManagementClient) CreateAgent(name, dataDir string) (Agent, error) {
-	return c.createAgent(&name, &dataDir, nil)
+	return c.CreateAgentContext(context.Background(), name, dataDir)
+}
+
+/*
+CreateAgentContext is like CreateAgent but carries the given context through to the http request.
+*/
+func (c *ManagementClient) CreateAgentContext(ctx context.Context, name, dataDir string) (Agent, error) {
+	return c.createAgent(ctx, &name, &dataDir, nil)
 }
 
 /*
 CreateAgentWithTag creates a new agent tagged with the given tag.
 */
 func (c *ManagementClient) CreateAgentWithTag(name, dataDir string, tagId int) (Agent, error) {
-	return c.createAgent(&name, &dataDir, &tagId)
+	return c.CreateAgentWithTagContext(context.Background(), name, dataDir, tagId)
 }
 
-func (c *ManagementClient) createAgent(name, dataDir *string, tagId *int) (Agent, error) {
-	if !c.isValid() {
-		return Agent{}, &NotValidError{}
-	}
+/*
+CreateAgentWithTagContext is like CreateAgentWithTag but carries the given context through to the http request.
+*/
+func (c *ManagementClient) CreateAgentWithTagContext(ctx context.Context, name, dataDir string, tagId int) (Agent, error) {
+	return c.createAgent(ctx, &name, &dataDir, &tagId)
+}
 
+func (c *ManagementClient) createAgent(ctx context.Context, name, dataDir *string, tagId *int) (Agent, error) {
 	if *name == "" {
 		return Agent{}, errors.New("invalid name")
 	}
@@ -588,28 +570,9 @@ func (c *ManagementClient) createAgent(name, dataDir *string, tagId *int) (Agent
 	}
 
 	params := requestParams{*name, *dataDir}
-	jsonString, err := json.Marshal(params)
-	if err != nil {
-		return Agent{}, errors.Wrap(err, "error during marshal")
-	}
-
-	path := mgmtEndpointPath + "agents"
-	if tagId != nil {
-		path = mgmtEndpointPath + "tags/" + strconv.Itoa(*tagId) + "/agent"
-	}
-
-	response, err := c.request("POST", path, string(jsonString), nil, nil)
-	if err != nil {
-		return Agent{}, errors.Wrap(err, "error during request")
-	}
-	if response.StatusCode() != 201 {
-		return Agent{}, getHttpError(response)
-	}
-
 	var agent Agent
-	err = json.Unmarshal(response.Body(), &agent)
-	if err != nil {
-		return Agent{}, errors.Wrap(err, "error during unmarshalling http response")
+	if err := c.create(ctx, agentResource, tagId, params, &agent); err != nil {
+		return Agent{}, err
 	}
 	return agent, nil
 }
@@ -618,110 +581,125 @@ func (c *ManagementClient) createAgent(name, dataDir *string, tagId *int) (Agent
 DeleteAgent deletes the agent with the given id.
 */
 func (c *ManagementClient) DeleteAgent(id int) error {
-	if !c.isValid() {
-		return &NotValidError{}
-	}
+	return c.DeleteAgentContext(context.Background(), id)
+}
 
-	response, err := c.request("DELETE", mgmtEndpointPath+"agents/"+strconv.Itoa(id), "", nil, nil)
-	if err != nil {
-		return errors.Wrap(err, "error during request")
-	}
-	if response.StatusCode() != 204 {
-		return getHttpError(response)
-	}
-	return nil
+/*
+DeleteAgentContext is like DeleteAgent but carries the given context through to the http request.
+*/
+func (c *ManagementClient) DeleteAgentContext(ctx context.Context, id int) error {
+	return c.delete(ctx, agentResource, id)
 }
 
 /*
-AddEngineToAgent adds an Engine to an Agent.
+UpdateAgent changes the attributes of the agent with the given id which are set in the update and returns the updated agent.
 */
-func (c *ManagementClient) AddEngineToAgent(agentId, engineId int) error {
-	if !c.isValid() {
-		return &NotValidError{}
-	}
+func (c *ManagementClient) UpdateAgent(id int, update AgentUpdate) (Agent, error) {
+	return c.UpdateAgentContext(context.Background(), id, update)
+}
 
-	response, err := c.request("PUT", mgmtEndpointPath+"agents/"+strconv.Itoa(agentId)+"/engine/"+strconv.Itoa(engineId), "", nil, nil)
-	if err != nil {
-		return errors.Wrap(err, "error during request")
+/*
+UpdateAgentContext is like UpdateAgent but carries the given context through to the http request.
+*/
+func (c *ManagementClient) UpdateAgentContext(ctx context.Context, id int, update AgentUpdate) (Agent, error) {
+	var agent Agent
+	if err := c.update(ctx, agentResource, id, update, &agent); err != nil {
+		return Agent{}, err
 	}
+	return agent, nil
+}
 
-	if response.StatusCode() != 200 {
-		return getHttpError(response)
-	}
+/*
+AddEngineToAgent adds an Engine to an Agent.
+*/
+func (c *ManagementClient) AddEngineToAgent(agentId, engineId int) error {
+	return c.AddEngineToAgentContext(context.Background(), agentId, engineId)
+}
 
-	return nil
+/*
+AddEngineToAgentContext is like AddEngineToAgent but carries the given context through to the http request.
+*/
+func (c *ManagementClient) AddEngineToAgentContext(ctx context.Context, agentId, engineId int) error {
+	return c.link(ctx, agentResource, agentId, engineResource, engineId, nil)
 }
 
 /*
 RemoveEngineFromAgent removes an Engine from an Agent.
 */
 func (c *ManagementClient) RemoveEngineFromAgent(agentId, engineId int) error {
-	if !c.isValid() {
-		return &NotValidError{}
-	}
-
-	response, err := c.request("DELETE", mgmtEndpointPath+"agents/"+strconv.Itoa(agentId)+"/engine/"+strconv.Itoa(engineId), "", nil, nil)
-	if err != nil {
-		return errors.Wrap(err, "error during request")
-	}
-
-	if response.StatusCode() != 204 {
-		return getHttpError(response)
-	}
+	return c.RemoveEngineFromAgentContext(context.Background(), agentId, engineId)
+}
 
-	return nil
+/*
+RemoveEngineFromAgentContext is like RemoveEngineFromAgent but carries the given context through to the http request.
+*/
+func (c *ManagementClient) RemoveEngineFromAgentContext(ctx context.Context, agentId, engineId int) error {
+	return c.unlink(ctx, agentResource, agentId, engineResource, engineId)
 }
 
 /*
 AddSelectorToAgent adds a Selector to an Agent.
 */
 func (c *ManagementClient) AddSelectorToAgent(agentId, selectorId int) (Agent, error) {
-	//TODO: Not implemented yet!!! Selectors are not implemented yet, see SELECTORS section
-	return Agent{}, errors.New("Not implemented yet")
+	return c.AddSelectorToAgentContext(context.Background(), agentId, selectorId)
+}
+
+/*
+AddSelectorToAgentContext is like AddSelectorToAgent but carries the given context through to the http request.
+*/
+func (c *ManagementClient) AddSelectorToAgentContext(ctx context.Context, agentId, selectorId int) (Agent, error) {
+	var agent Agent
+	if err := c.link(ctx, agentResource, agentId, selectorResource, selectorId, &agent); err != nil {
+		return Agent{}, err
+	}
+	return agent, nil
 }
 
 /*
 RemoveSelectorFromAgent removes a Selector from an Agent.
 */
 func (c *ManagementClient) RemoveSelectorFromAgent(agentId, selectorId int) (Agent, error) {
-	//TODO: Not implemented yet!!! Selectors are not implemented yet, see SELECTORS section
-	return Agent{}, errors.New("Not implemented yet")
+	return c.RemoveSelectorFromAgentContext(context.Background(), agentId, selectorId)
+}
+
+/*
+RemoveSelectorFromAgentContext is like RemoveSelectorFromAgent but carries the given context through to the http request.
+*/
+func (c *ManagementClient) RemoveSelectorFromAgentContext(ctx context.Context, agentId, selectorId int) (Agent, error) {
+	if err := c.unlink(ctx, agentResource, agentId, selectorResource, selectorId); err != nil {
+		return Agent{}, err
+	}
+
+	//the api does not return the agent after removing a selector
+	return c.GetAgentContext(ctx, agentId)
 }
 
 /*
 AddTagToAgent adds a tag to a agent.
 */
 func (c *ManagementClient) AddTagToAgent(agentId, tagId int) error {
-	if !c.isValid() {
-		return &NotValidError{}
-	}
-	response, err := c.request("PUT", mgmtEndpointPath+"tags/"+strconv.Itoa(tagId)+"/agent/"+strconv.Itoa(agentId), "", nil, nil)
-	if err != nil {
-		return errors.Wrap(err, "error during request")
-	}
+	return c.AddTagToAgentContext(context.Background(), agentId, tagId)
+}
 
-	if response.StatusCode() != 200 {
-		return getHttpError(response)
-	}
-	return nil
+/*
+AddTagToAgentContext is like AddTagToAgent but carries the given context through to the http request.
+*/
+func (c *ManagementClient) AddTagToAgentContext(ctx context.Context, agentId, tagId int) error {
+	return c.tag(ctx, agentResource, agentId, tagId)
 }
 
 /*
 RemoveTagFromAgent removes a tag from a agent.
 */
 func (c *ManagementClient) RemoveTagFromAgent(agentId, tagId int) error {
-	if !c.isValid() {
-		return &NotValidError{}
-	}
-	response, err := c.request("DELETE", mgmtEndpointPath+"tags/"+strconv.Itoa(tagId)+"/agent/"+strconv.Itoa(agentId), "", nil, nil)
-	if err != nil {
-		return errors.Wrap(err, "error during request")
-	}
+	return c.RemoveTagFromAgentContext(context.Background(), agentId, tagId)
+}
 
-	if response.StatusCode() != 200 {
-		return getHttpError(response)
-	}
-	return nil
+/*
+RemoveTagFromAgentContext is like RemoveTagFromAgent but carries the given context through to the http request.
+*/
+func (c *ManagementClient) RemoveTagFromAgentContext(ctx context.Context, agentId, tagId int) error {
+	return c.untag(ctx, agentResource, agentId, tagId)
 }
 
 /*
@@ -732,22 +710,34 @@ ENDPOINTS
 GetEndpoints returns a list of endpoints, optionally filtered.
 */
 func (c *ManagementClient) GetEndpoints(filters map[string]string) (Endpoints, error) {
-	if !c.isValid() {
-		return nil, &NotValidError{}
-	}
+	return c.GetEndpointsContext(context.Background(), filters)
+}
 
-	response, err := c.request("GET", mgmtEndpointPath+"endpoints", "", nil, filters)
-	if err != nil {
-		return nil, errors.Wrap(err, "error during get endpoints request")
-	}
-	if response.StatusCode() != 200 {
-		return nil, getHttpError(response)
+/*
+GetEndpointsContext is like GetEndpoints but carries the given context through to the http request.
+*/
+func (c *ManagementClient) GetEndpointsContext(ctx context.Context, filters map[string]string) (Endpoints, error) {
+	var endpoints Endpoints
+	if err := c.list(ctx, endpointResource, filters, &endpoints); err != nil {
+		return nil, err
 	}
+	return endpoints, nil
+}
 
+/*
+SearchEndpoints returns all endpoints matching the given filter.
+*/
+func (c *ManagementClient) SearchEndpoints(filter EndpointFilter) (Endpoints, error) {
+	return c.SearchEndpointsContext(context.Background(), filter)
+}
+
+/*
+SearchEndpointsContext is like SearchEndpoints but carries the given context through to the http request.
+*/
+func (c *ManagementClient) SearchEndpointsContext(ctx context.Context, filter EndpointFilter) (Endpoints, error) {
 	var endpoints Endpoints
-	err = json.Unmarshal(response.Body(), &endpoints)
-	if err != nil {
-		return nil, errors.Wrap(err, "error during unmarshalling http response")
+	if err := c.search(ctx, endpointResource, filter, &endpoints); err != nil {
+		return nil, err
 	}
 	return endpoints, nil
 }
@@ -756,22 +746,16 @@ func (c *ManagementClient) GetEndpoints(filters map[string]string) (Endpoints, e
 GetEndpoint returns the endpoint with the given id.
 */
 func (c *ManagementClient) GetEndpoint(id int) (Endpoint, error) {
-	if !c.isValid() {
-		return Endpoint{}, &NotValidError{}
-	}
-
-	response, err := c.request("GET", mgmtEndpointPath+"endpoints/"+strconv.Itoa(id), "", nil, nil)
-	if err != nil {
-		return Endpoint{}, errors.Wrap(err, "error during get labs request")
-	}
-	if response.StatusCode() != 200 {
-		return Endpoint{}, getHttpError(response)
-	}
+	return c.GetEndpointContext(context.Background(), id)
+}
 
+/*
+GetEndpointContext is like GetEndpoint but carries the given context through to the http request.
+*/
+func (c *ManagementClient) GetEndpointContext(ctx context.Context, id int) (Endpoint, error) {
 	var endpoint Endpoint
-	err = json.Unmarshal(response.Body(), &endpoint)
-	if err != nil {
-		return Endpoint{}, errors.Wrap(err, "error during unmarshalling http response")
+	if err := c.get(ctx, endpointResource, id, &endpoint); err != nil {
+		return Endpoint{}, err
 	}
 	return endpoint, nil
 }
@@ -780,21 +764,31 @@ func (c *ManagementClient) GetEndpoint(id int) (Endpoint, error) {
 CreateEndpoint creates a new endpoint.
 */
 func (c *ManagementClient) CreateEndpoint(name, address, protocol string) (Endpoint, error) {
-	return c.createEndpoint(&name, &address, &protocol, nil)
+	return c.CreateEndpointContext(context.Background(), name, address, protocol)
+}
+
+/*
+CreateEndpointContext is like CreateEndpoint but carries the given context through to the http request.
+*/
+func (c *ManagementClient) CreateEndpointContext(ctx context.Context, name, address, protocol string) (Endpoint, error) {
+	return c.createEndpoint(ctx, &name, &address, &protocol, nil)
 }
 
 /*
 CreateEndpointWithTag creates a new endpoint tagged with the given tag.
 */
 func (c *ManagementClient) CreateEndpointWithTag(name, address, protocol string, tagId int) (Endpoint, error) {
-	return c.createEndpoint(&name, &address, &protocol, &tagId)
+	return c.CreateEndpointWithTagContext(context.Background(), name, address, protocol, tagId)
 }
 
-func (c *ManagementClient) createEndpoint(name, address, protocol *string, tagId *int) (Endpoint, error) {
-	if !c.isValid() {
-		return Endpoint{}, &NotValidError{}
-	}
+/*
+CreateEndpointWithTagContext is like CreateEndpointWithTag but carries the given context through to the http request.
+*/
+func (c *ManagementClient) CreateEndpointWithTagContext(ctx context.Context, name, address, protocol string, tagId int) (Endpoint, error) {
+	return c.createEndpoint(ctx, &name, &address, &protocol, &tagId)
+}
 
+func (c *ManagementClient) createEndpoint(ctx context.Context, name, address, protocol *string, tagId *int) (Endpoint, error) {
 	if *name == "" {
 		return Endpoint{}, errors.New("invalid name")
 	}
@@ -814,85 +808,71 @@ func (c *ManagementClient) createEndpoint(name, address, protocol *string, tagId
 	}
 
 	params := requestParams{*name, *address, *protocol}
-	jsonString, err := json.Marshal(params)
-	if err != nil {
-		return Endpoint{}, errors.Wrap(err, "error during marshal")
-	}
-
-	path := mgmtEndpointPath + "endpoints"
-	if tagId != nil {
-		path = mgmtEndpointPath + "tags/" + strconv.Itoa(*tagId) + "/endpoint"
-	}
-
-	response, err := c.request("POST", path, string(jsonString), nil, nil)
-
-	if err != nil {
-		return Endpoint{}, errors.Wrap(err, "error during request")
-	}
-	if response.StatusCode() != 201 {
-		return Endpoint{}, getHttpError(response)
-	}
-
 	var endpoint Endpoint
-	err = json.Unmarshal(response.Body(), &endpoint)
-	if err != nil {
-		return Endpoint{}, errors.Wrap(err, "error during unmarshalling http response")
+	if err := c.create(ctx, endpointResource, tagId, params, &endpoint); err != nil {
+		return Endpoint{}, err
 	}
 	return endpoint, nil
 }
 
 /*
-DeleteEndpoint the Lab with the given id.
+DeleteEndpoint deletes the endpoint with the given id.
+*/
+func (c *ManagementClient) DeleteEndpoint(id int) error {
+	return c.DeleteEndpointContext(context.Background(), id)
+}
+
+/*
+DeleteEndpointContext is like DeleteEndpoint but carries the given context through to the http request.
+*/
+func (c *ManagementClient) DeleteEndpointContext(ctx context.Context, id int) error {
+	return c.delete(ctx, endpointResource, id)
+}
+
+/*
+UpdateEndpoint changes the attributes of the endpoint with the given id which are set in the update and returns the updated endpoint.
 */
-func (c *ManagementClient) DeleteEndpoint(id int) error {
-	if !c.isValid() {
-		return &NotValidError{}
-	}
+func (c *ManagementClient) UpdateEndpoint(id int, update EndpointUpdate) (Endpoint, error) {
+	return c.UpdateEndpointContext(context.Background(), id, update)
+}
 
-	response, err := c.request("DELETE", mgmtEndpointPath+"endpoints/"+strconv.Itoa(id), "", nil, nil)
-	if err != nil {
-		return errors.Wrap(err, "error during request")
-	}
-	if response.StatusCode() != 204 {
-		return getHttpError(response)
+/*
+UpdateEndpointContext is like UpdateEndpoint but carries the given context through to the http request.
+*/
+func (c *ManagementClient) UpdateEndpointContext(ctx context.Context, id int, update EndpointUpdate) (Endpoint, error) {
+	var endpoint Endpoint
+	if err := c.update(ctx, endpointResource, id, update, &endpoint); err != nil {
+		return Endpoint{}, err
 	}
-	return nil
+	return endpoint, nil
 }
 
 /*
 AddTagToEndpoint adds a tag to a endpoint.
 */
 func (c *ManagementClient) AddTagToEndpoint(endpointId, tagId int) error {
-	if !c.isValid() {
-		return &NotValidError{}
-	}
-	response, err := c.request("PUT", mgmtEndpointPath+"tags/"+strconv.Itoa(tagId)+"/endpoint/"+strconv.Itoa(endpointId), "", nil, nil)
-	if err != nil {
-		return errors.Wrap(err, "error during request")
-	}
+	return c.AddTagToEndpointContext(context.Background(), endpointId, tagId)
+}
 
-	if response.StatusCode() != 200 {
-		return getHttpError(response)
-	}
-	return nil
+/*
+AddTagToEndpointContext is like AddTagToEndpoint but carries the given context through to the http request.
+*/
+func (c *ManagementClient) AddTagToEndpointContext(ctx context.Context, endpointId, tagId int) error {
+	return c.tag(ctx, endpointResource, endpointId, tagId)
 }
 
 /*
 RemoveTagFromEndpoint removes a tag from a endpoint.
 */
 func (c *ManagementClient) RemoveTagFromEndpoint(endpointId, tagId int) error {
-	if !c.isValid() {
-		return &NotValidError{}
-	}
-	response, err := c.request("DELETE", mgmtEndpointPath+"tags/"+strconv.Itoa(tagId)+"/endpoint/"+strconv.Itoa(endpointId), "", nil, nil)
-	if err != nil {
-		return errors.Wrap(err, "error during request")
-	}
+	return c.RemoveTagFromEndpointContext(context.Background(), endpointId, tagId)
+}
 
-	if response.StatusCode() != 200 {
-		return getHttpError(response)
-	}
-	return nil
+/*
+RemoveTagFromEndpointContext is like RemoveTagFromEndpoint but carries the given context through to the http request.
+*/
+func (c *ManagementClient) RemoveTagFromEndpointContext(ctx context.Context, endpointId, tagId int) error {
+	return c.untag(ctx, endpointResource, endpointId, tagId)
 }
 
 /*
@@ -903,96 +883,154 @@ RECORD FILES
 GetRecordFiles returns a list of all record files.
 */
 func (c *ManagementClient) GetRecordFiles() (Recordings, error) {
-	if !c.isValid() {
-		return nil, &NotValidError{}
-	}
-
-	response, err := c.request("GET", mgmtEndpointPath+"recordings", "", nil, nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "error during get labs request")
-	}
-	if response.StatusCode() != 200 {
-		return nil, getHttpError(response)
-	}
+	return c.GetRecordFilesContext(context.Background())
+}
 
+/*
+GetRecordFilesContext is like GetRecordFiles but carries the given context through to the http request.
+*/
+func (c *ManagementClient) GetRecordFilesContext(ctx context.Context) (Recordings, error) {
 	var recordings Recordings
-	err = json.Unmarshal(response.Body(), &recordings)
+	err := c.do(ctx, apiCall{
+		method:         "GET",
+		path:           recordingsPath,
+		description:    "get record files",
+		expectedStatus: 200,
+		result:         &recordings,
+	})
 	if err != nil {
-		return nil, errors.Wrap(err, "error during unmarshalling http response")
+		return nil, err
 	}
 	return recordings, nil
 }
 
 /*
 UploadRecordFile uploads the given record file to the api and saves it at the given remote path inside of the data dir.
+The file can be of any RecordingFormat, the remote path has to be of the same format. Uncompressed snmprec files are validated before if the client was created with
+WithRecordFileValidation.
 */
 func (c *ManagementClient) UploadRecordFile(localPath, remotePath string) error {
+	return c.UploadRecordFileContext(context.Background(), localPath, remotePath)
+}
+
+/*
+UploadRecordFileContext is like UploadRecordFile but carries the given context through to the http request.
+*/
+func (c *ManagementClient) UploadRecordFileContext(ctx context.Context, localPath, remotePath string) error {
 	localPath = strings.TrimSpace(localPath)
-	if !strings.HasSuffix(localPath, ".snmprec") {
-		return errors.New("file is not an snmprec file")
+	format, compressed, err := RecordingFormatOf(localPath)
+	if err != nil {
+		return err
+	}
+	remoteFormat, remoteCompressed, err := RecordingFormatOf(remotePath)
+	if err != nil {
+		return err
 	}
-	b, err := ioutil.ReadFile(localPath)
+	if format != remoteFormat || compressed != remoteCompressed {
+		return errors.New("file '" + localPath + "' can not be uploaded to '" + strings.TrimSpace(remotePath) +
+			"', the recording formats differ")
+	}
+	if c.isValid() && c.validateRecordFiles && validatable(localPath) {
+		b, err := ioutil.ReadFile(localPath)
+		if err != nil {
+			return errors.Wrap(err, "error while reading file")
+		}
+		s := string(b)
+		return c.UploadRecordFileStringContext(ctx, &s, remotePath)
+	}
+
+	//without validation the file does not have to be read into memory
+	file, err := os.Open(localPath)
 	if err != nil {
 		return errors.Wrap(err, "error while reading file")
 	}
-	s := string(b)
-	return c.UploadRecordFileString(&s, remotePath)
+	defer file.Close()
+	return c.uploadRecording(ctx, remotePath, file, nil)
 }
 
 /*
-UploadRecordFileString uploads the given record data to the api and saves it as a .snmprec file at the given remote path inside of the data dir.
+UploadRecordFileString uploads the given record data to the api and saves it at the given remote path inside of the data dir.
+The record data is validated before if the client was created with WithRecordFileValidation, unless the remote path
+is a recording of another format than snmprec.
 */
 func (c *ManagementClient) UploadRecordFileString(recordContents *string, remotePath string) error {
-	headerMap := make(map[string]string)
-	headerMap["Content-Type"] = "text/plain"
-	response, err := c.request("POST", mgmtEndpointPath+"recordings/"+remotePath, *recordContents, headerMap, nil)
-	if err != nil {
-		return errors.Wrap(err, "error during request")
+	return c.UploadRecordFileStringContext(context.Background(), recordContents, remotePath)
+}
+
+/*
+UploadRecordFileStringContext is like UploadRecordFileString but carries the given context through to the http request.
+*/
+func (c *ManagementClient) UploadRecordFileStringContext(ctx context.Context, recordContents *string, remotePath string) error {
+	if recordContents == nil {
+		return errors.New("invalid record contents")
 	}
-	if response.StatusCode() != 204 {
-		return getHttpError(response)
+	if c.isValid() && c.validateRecordFiles && validatable(remotePath) {
+		if err := snmprec.Validate([]byte(*recordContents)); err != nil {
+			return errors.Wrap(err, "invalid record file")
+		}
 	}
-	return nil
+	return c.do(ctx, apiCall{
+		method:         "POST",
+		path:           recordingsPath + "/" + remotePath,
+		description:    "upload record file",
+		header:         textHeader(),
+		expectedStatus: 204,
+		body:           *recordContents,
+	})
 }
 
 /*
 DeleteRecordFile deletes the record file at the given path.
 */
 func (c *ManagementClient) DeleteRecordFile(remotePath string) error {
+	return c.DeleteRecordFileContext(context.Background(), remotePath)
+}
+
+/*
+DeleteRecordFileContext is like DeleteRecordFile but carries the given context through to the http request.
+*/
+func (c *ManagementClient) DeleteRecordFileContext(ctx context.Context, remotePath string) error {
 	remotePath = strings.TrimSpace(remotePath)
-	if !strings.HasSuffix(remotePath, ".snmprec") {
-		return errors.New("file is not an snmprec file")
+	if _, _, err := RecordingFormatOf(remotePath); err != nil {
+		return err
 	}
-	headerMap := make(map[string]string)
-	headerMap["Content-Type"] = "text/plain"
-	response, err := c.request("DELETE", mgmtEndpointPath+"recordings/"+remotePath, "", headerMap, nil)
-	if err != nil {
-		return errors.Wrap(err, "error during request")
-	}
-	if response.StatusCode() != 204 {
-		return getHttpError(response)
-	}
-	return nil
+	return c.do(ctx, apiCall{
+		method:         "DELETE",
+		path:           recordingsPath + "/" + remotePath,
+		description:    "delete record file",
+		header:         textHeader(),
+		expectedStatus: 204,
+	})
 }
 
 /*
 GetRecordFile returns the record file at the given path.
 */
 func (c *ManagementClient) GetRecordFile(remotePath string) (string, error) {
+	return c.GetRecordFileContext(context.Background(), remotePath)
+}
+
+/*
+GetRecordFileContext is like GetRecordFile but carries the given context through to the http request.
+*/
+func (c *ManagementClient) GetRecordFileContext(ctx context.Context, remotePath string) (string, error) {
 	remotePath = strings.TrimSpace(remotePath)
-	if !strings.HasSuffix(remotePath, ".snmprec") {
-		return "", errors.New("file is not an snmprec file")
+	if _, _, err := RecordingFormatOf(remotePath); err != nil {
+		return "", err
 	}
-	headerMap := make(map[string]string)
-	headerMap["Content-Type"] = "text/plain"
-	response, err := c.request("GET", mgmtEndpointPath+"recordings/"+remotePath, "", headerMap, nil)
+	var content string
+	err := c.do(ctx, apiCall{
+		method:         "GET",
+		path:           recordingsPath + "/" + remotePath,
+		description:    "get record file",
+		header:         textHeader(),
+		expectedStatus: 200,
+		result:         &content,
+	})
 	if err != nil {
-		return "", errors.Wrap(err, "error during request")
-	}
-	if response.StatusCode() != 200 {
-		return "", getHttpError(response)
+		return "", err
 	}
-	return string(response.Body()), nil
+	return content, nil
 }
 
 /*
@@ -1003,17 +1041,31 @@ USERS
 CreateUser creates a new user.
 */
 func (c *ManagementClient) CreateUser(user, name, authKey, authProto, privKey, privProto string) (User, error) {
-	return c.createUser(&user, &name, &authKey, &authProto, &privKey, &privProto, nil)
+	return c.CreateUserContext(context.Background(), user, name, authKey, authProto, privKey, privProto)
+}
+
+/*
+CreateUserContext is like CreateUser but carries the given context through to the http request.
+*/
+func (c *ManagementClient) CreateUserContext(ctx context.Context, user, name, authKey, authProto, privKey, privProto string) (User, error) {
+	return c.createUser(ctx, &user, &name, &authKey, &authProto, &privKey, &privProto, nil)
 }
 
 /*
 CreateUserWithTag creates a new user tagged with the given tag.
 */
 func (c *ManagementClient) CreateUserWithTag(user, name, authKey, authProto, privKey, privProto string, tagId int) (User, error) {
-	return c.createUser(&user, &name, &authKey, &authProto, &privKey, &privProto, &tagId)
+	return c.CreateUserWithTagContext(context.Background(), user, name, authKey, authProto, privKey, privProto, tagId)
+}
+
+/*
+CreateUserWithTagContext is like CreateUserWithTag but carries the given context through to the http request.
+*/
+func (c *ManagementClient) CreateUserWithTagContext(ctx context.Context, user, name, authKey, authProto, privKey, privProto string, tagId int) (User, error) {
+	return c.createUser(ctx, &user, &name, &authKey, &authProto, &privKey, &privProto, &tagId)
 }
 
-func (c *ManagementClient) createUser(user, name, authKey, authProto, privKey, privProto *string, tagId *int) (User, error) {
+func (c *ManagementClient) createUser(ctx context.Context, user, name, authKey, authProto, privKey, privProto *string, tagId *int) (User, error) {
 	if !c.isValid() {
 		return User{}, &NotValidError{}
 	}
@@ -1071,7 +1123,7 @@ func (c *ManagementClient) createUser(user, name, authKey, authProto, privKey, p
 		path = mgmtEndpointPath + "tags/" + strconv.Itoa(*tagId) + "/user"
 	}
 
-	response, err := c.request("POST", path, string(jsonString), nil, nil)
+	response, err := c.request(ctx, "POST", path, string(jsonString), nil, nil)
 	if err != nil {
 		return User{}, errors.Wrap(err, "error during request")
 	}
@@ -1091,22 +1143,34 @@ func (c *ManagementClient) createUser(user, name, authKey, authProto, privKey, p
 GetUsers returns a list of users, optionally filtered.
 */
 func (c *ManagementClient) GetUsers(filters map[string]string) (Users, error) {
-	if !c.isValid() {
-		return nil, &NotValidError{}
-	}
+	return c.GetUsersContext(context.Background(), filters)
+}
 
-	response, err := c.request("GET", mgmtEndpointPath+"users", "", nil, filters)
-	if err != nil {
-		return nil, errors.Wrap(err, "error during get users request")
-	}
-	if response.StatusCode() != 200 {
-		return nil, getHttpError(response)
+/*
+GetUsersContext is like GetUsers but carries the given context through to the http request.
+*/
+func (c *ManagementClient) GetUsersContext(ctx context.Context, filters map[string]string) (Users, error) {
+	var users Users
+	if err := c.list(ctx, userResource, filters, &users); err != nil {
+		return nil, err
 	}
+	return users, nil
+}
+
+/*
+SearchUsers returns all users matching the given filter.
+*/
+func (c *ManagementClient) SearchUsers(filter UserFilter) (Users, error) {
+	return c.SearchUsersContext(context.Background(), filter)
+}
 
+/*
+SearchUsersContext is like SearchUsers but carries the given context through to the http request.
+*/
+func (c *ManagementClient) SearchUsersContext(ctx context.Context, filter UserFilter) (Users, error) {
 	var users Users
-	err = json.Unmarshal(response.Body(), &users)
-	if err != nil {
-		return nil, errors.Wrap(err, "error during unmarshalling http response")
+	if err := c.search(ctx, userResource, filter, &users); err != nil {
+		return nil, err
 	}
 	return users, nil
 }
@@ -1115,22 +1179,16 @@ func (c *ManagementClient) GetUsers(filters map[string]string) (Users, error) {
 GetUser returns the user with the given id.
 */
 func (c *ManagementClient) GetUser(id int) (User, error) {
-	if !c.isValid() {
-		return User{}, &NotValidError{}
-	}
-
-	response, err := c.request("GET", mgmtEndpointPath+"users/"+strconv.Itoa(id), "", nil, nil)
-	if err != nil {
-		return User{}, errors.Wrap(err, "error during get labs request")
-	}
-	if response.StatusCode() != 200 {
-		return User{}, getHttpError(response)
-	}
+	return c.GetUserContext(context.Background(), id)
+}
 
+/*
+GetUserContext is like GetUser but carries the given context through to the http request.
+*/
+func (c *ManagementClient) GetUserContext(ctx context.Context, id int) (User, error) {
 	var user User
-	err = json.Unmarshal(response.Body(), &user)
-	if err != nil {
-		return User{}, errors.Wrap(err, "error during unmarshalling http response")
+	if err := c.get(ctx, userResource, id, &user); err != nil {
+		return User{}, err
 	}
 	return user, nil
 }
@@ -1139,91 +1197,189 @@ func (c *ManagementClient) GetUser(id int) (User, error) {
 DeleteUser deletes the user with the given id.
 */
 func (c *ManagementClient) DeleteUser(id int) error {
-	if !c.isValid() {
-		return &NotValidError{}
-	}
+	return c.DeleteUserContext(context.Background(), id)
+}
 
-	response, err := c.request("DELETE", mgmtEndpointPath+"users/"+strconv.Itoa(id), "", nil, nil)
-	if err != nil {
-		return errors.Wrap(err, "error during request")
-	}
-	if response.StatusCode() != 204 {
-		return getHttpError(response)
+/*
+DeleteUserContext is like DeleteUser but carries the given context through to the http request.
+*/
+func (c *ManagementClient) DeleteUserContext(ctx context.Context, id int) error {
+	return c.delete(ctx, userResource, id)
+}
+
+/*
+UpdateUser changes the attributes of the user with the given id which are set in the update and returns the updated user.
+*/
+func (c *ManagementClient) UpdateUser(id int, update UserUpdate) (User, error) {
+	return c.UpdateUserContext(context.Background(), id, update)
+}
+
+/*
+UpdateUserContext is like UpdateUser but carries the given context through to the http request.
+*/
+func (c *ManagementClient) UpdateUserContext(ctx context.Context, id int, update UserUpdate) (User, error) {
+	var user User
+	if err := c.update(ctx, userResource, id, update, &user); err != nil {
+		return User{}, err
 	}
-	return nil
+	return user, nil
 }
 
 /*
 AddTagToUser adds a tag to a user.
 */
 func (c *ManagementClient) AddTagToUser(userId, tagId int) error {
-	if !c.isValid() {
-		return &NotValidError{}
-	}
-	response, err := c.request("PUT", mgmtEndpointPath+"tags/"+strconv.Itoa(tagId)+"/user/"+strconv.Itoa(userId), "", nil, nil)
-	if err != nil {
-		return errors.Wrap(err, "error during request")
-	}
+	return c.AddTagToUserContext(context.Background(), userId, tagId)
+}
 
-	if response.StatusCode() != 200 {
-		return getHttpError(response)
-	}
-	return nil
+/*
+AddTagToUserContext is like AddTagToUser but carries the given context through to the http request.
+*/
+func (c *ManagementClient) AddTagToUserContext(ctx context.Context, userId, tagId int) error {
+	return c.tag(ctx, userResource, userId, tagId)
 }
 
 /*
 RemoveTagFromUser removes a tag from a user.
 */
 func (c *ManagementClient) RemoveTagFromUser(userId, tagId int) error {
-	if !c.isValid() {
-		return &NotValidError{}
-	}
-	response, err := c.request("DELETE", mgmtEndpointPath+"tags/"+strconv.Itoa(tagId)+"/user/"+strconv.Itoa(userId), "", nil, nil)
-	if err != nil {
-		return errors.Wrap(err, "error during request")
-	}
+	return c.RemoveTagFromUserContext(context.Background(), userId, tagId)
+}
 
-	if response.StatusCode() != 200 {
-		return getHttpError(response)
-	}
-	return nil
+/*
+RemoveTagFromUserContext is like RemoveTagFromUser but carries the given context through to the http request.
+*/
+func (c *ManagementClient) RemoveTagFromUserContext(ctx context.Context, userId, tagId int) error {
+	return c.untag(ctx, userResource, userId, tagId)
 }
 
 /*
 SELECTORS
 */
-//TODO: Not implemented yet in api, there is always a default selector for snmp v2c and v3. This has to be implemented when its possible to configure own selectors.
 
 /*
-CreateSelector creates a new selector.
+CreateSelector creates a new selector. The template is validated with ValidateSelectorTemplate before it is sent to the api.
 */
 func (c *ManagementClient) CreateSelector(comment, template string) (Selector, error) {
-	//TODO: Not implemented yet!!! This also isnt implemented in the api so we have to wait until its ready.
-	return Selector{}, errors.New("Not implemented yet")
+	return c.CreateSelectorContext(context.Background(), comment, template)
+}
+
+/*
+CreateSelectorContext is like CreateSelector but carries the given context through to the http request.
+*/
+func (c *ManagementClient) CreateSelectorContext(ctx context.Context, comment, template string) (Selector, error) {
+	return c.createSelector(ctx, &comment, &template, nil)
+}
+
+/*
+CreateSelectorWithTag creates a new selector tagged with the given tag.
+*/
+func (c *ManagementClient) CreateSelectorWithTag(comment, template string, tagId int) (Selector, error) {
+	return c.CreateSelectorWithTagContext(context.Background(), comment, template, tagId)
+}
+
+/*
+CreateSelectorWithTagContext is like CreateSelectorWithTag but carries the given context through to the http request.
+*/
+func (c *ManagementClient) CreateSelectorWithTagContext(ctx context.Context, comment, template string, tagId int) (Selector, error) {
+	return c.createSelector(ctx, &comment, &template, &tagId)
+}
+
+func (c *ManagementClient) createSelector(ctx context.Context, comment, template *string, tagId *int) (Selector, error) {
+	err := ValidateSelectorTemplate(*template)
+	if err != nil {
+		return Selector{}, err
+	}
+
+	type requestParams struct {
+		Comment  string `json:"comment"`
+		Template string `json:"template"`
+	}
+
+	params := requestParams{*comment, *template}
+	var selector Selector
+	if err := c.create(ctx, selectorResource, tagId, params, &selector); err != nil {
+		return Selector{}, err
+	}
+	return selector, nil
 }
 
 /*
 GetSelectors returns a list of all selectors.
 */
 func (c *ManagementClient) GetSelectors() (Selectors, error) {
-	//TODO: Not implemented yet!!! This also isnt implemented in the api so we have to wait until its ready.
-	return nil, errors.New("Not implemented yet")
+	return c.GetSelectorsContext(context.Background())
+}
+
+/*
+GetSelectorsContext is like GetSelectors but carries the given context through to the http request.
+*/
+func (c *ManagementClient) GetSelectorsContext(ctx context.Context) (Selectors, error) {
+	var selectors Selectors
+	if err := c.list(ctx, selectorResource, nil, &selectors); err != nil {
+		return nil, err
+	}
+	return selectors, nil
 }
 
 /*
 GetSelector returns the selector with the given id.
 */
 func (c *ManagementClient) GetSelector(id int) (Selector, error) {
-	//TODO: Not implemented yet!!! This also isnt implemented in the api so we have to wait until its ready.
-	return Selector{}, errors.New("Not implemented yet")
+	return c.GetSelectorContext(context.Background(), id)
+}
+
+/*
+GetSelectorContext is like GetSelector but carries the given context through to the http request.
+*/
+func (c *ManagementClient) GetSelectorContext(ctx context.Context, id int) (Selector, error) {
+	var selector Selector
+	if err := c.get(ctx, selectorResource, id, &selector); err != nil {
+		return Selector{}, err
+	}
+	return selector, nil
 }
 
 /*
 DeleteSelector deletes the selector with the given id.
 */
 func (c *ManagementClient) DeleteSelector(id int) error {
-	//TODO: Not implemented yet!!! This also isnt implemented in the api so we have to wait until its ready.
-	return errors.New("Not implemented yet")
+	return c.DeleteSelectorContext(context.Background(), id)
+}
+
+/*
+DeleteSelectorContext is like DeleteSelector but carries the given context through to the http request.
+*/
+func (c *ManagementClient) DeleteSelectorContext(ctx context.Context, id int) error {
+	return c.delete(ctx, selectorResource, id)
+}
+
+/*
+AddTagToSelector adds a tag to a selector.
+*/
+func (c *ManagementClient) AddTagToSelector(selectorId, tagId int) error {
+	return c.AddTagToSelectorContext(context.Background(), selectorId, tagId)
+}
+
+/*
+AddTagToSelectorContext is like AddTagToSelector but carries the given context through to the http request.
+*/
+func (c *ManagementClient) AddTagToSelectorContext(ctx context.Context, selectorId, tagId int) error {
+	return c.tag(ctx, selectorResource, selectorId, tagId)
+}
+
+/*
+RemoveTagFromSelector removes a tag from a selector.
+*/
+func (c *ManagementClient) RemoveTagFromSelector(selectorId, tagId int) error {
+	return c.RemoveTagFromSelectorContext(context.Background(), selectorId, tagId)
+}
+
+/*
+RemoveTagFromSelectorContext is like RemoveTagFromSelector but carries the given context through to the http request.
+*/
+func (c *ManagementClient) RemoveTagFromSelectorContext(ctx context.Context, selectorId, tagId int) error {
+	return c.untag(ctx, selectorResource, selectorId, tagId)
 }
 
 /*
@@ -1234,9 +1390,13 @@ func (c *ManagementClient) DeleteSelector(id int) error {
 CreateTag creates a new tag.
 */
 func (c *ManagementClient) CreateTag(name, description string) (Tag, error) {
-	if !c.isValid() {
-		return Tag{}, &NotValidError{}
-	}
+	return c.CreateTagContext(context.Background(), name, description)
+}
+
+/*
+CreateTagContext is like CreateTag but carries the given context through to the http request.
+*/
+func (c *ManagementClient) CreateTagContext(ctx context.Context, name, description string) (Tag, error) {
 	if name == "" {
 		return Tag{}, errors.New("invalid name")
 	}
@@ -1247,71 +1407,63 @@ func (c *ManagementClient) CreateTag(name, description string) (Tag, error) {
 	}
 
 	params := requestParams{name, description}
-	jsonString, err := json.Marshal(params)
-	if err != nil {
-		return Tag{}, errors.Wrap(err, "error during marshal")
-	}
-
-	response, err := c.request("POST", mgmtEndpointPath+"tags", string(jsonString), nil, nil)
-	if err != nil {
-		return Tag{}, errors.Wrap(err, "error during request")
-	}
-	if response.StatusCode() != 201 {
-		return Tag{}, getHttpError(response)
-	}
-
 	var tag Tag
-	err = json.Unmarshal(response.Body(), &tag)
-	if err != nil {
-		return Tag{}, errors.Wrap(err, "error during unmarshalling http response")
+	if err := c.create(ctx, tagResource, nil, params, &tag); err != nil {
+		return Tag{}, err
 	}
 	return tag, nil
 }
 
 /*
-GetTag returns the lab with the given id.
+GetTag returns the tag with the given id.
 */
 func (c *ManagementClient) GetTag(id int) (Tag, error) {
-	if !c.isValid() {
-		return Tag{}, &NotValidError{}
-	}
-
-	response, err := c.request("GET", mgmtEndpointPath+"tags/"+strconv.Itoa(id), "", nil, nil)
-	if err != nil {
-		return Tag{}, errors.Wrap(err, "error during get tags request")
-	}
-	if response.StatusCode() != 200 {
-		return Tag{}, getHttpError(response)
-	}
+	return c.GetTagContext(context.Background(), id)
+}
 
+/*
+GetTagContext is like GetTag but carries the given context through to the http request.
+*/
+func (c *ManagementClient) GetTagContext(ctx context.Context, id int) (Tag, error) {
 	var tag Tag
-	err = json.Unmarshal(response.Body(), &tag)
-	if err != nil {
-		return Tag{}, errors.Wrap(err, "error during unmarshalling http response")
+	if err := c.get(ctx, tagResource, id, &tag); err != nil {
+		return Tag{}, err
 	}
 	return tag, nil
 }
 
 /*
-GetTags returns a list of users, optionally filtered.
+GetTags returns a list of tags, optionally filtered.
 */
 func (c *ManagementClient) GetTags(filters map[string]string) (Tags, error) {
-	if !c.isValid() {
-		return nil, &NotValidError{}
-	}
+	return c.GetTagsContext(context.Background(), filters)
+}
 
-	response, err := c.request("GET", mgmtEndpointPath+"tags", "", nil, filters)
-	if err != nil {
-		return nil, errors.Wrap(err, "error during get users request")
-	}
-	if response.StatusCode() != 200 {
-		return nil, getHttpError(response)
+/*
+GetTagsContext is like GetTags but carries the given context through to the http request.
+*/
+func (c *ManagementClient) GetTagsContext(ctx context.Context, filters map[string]string) (Tags, error) {
+	var tags Tags
+	if err := c.list(ctx, tagResource, filters, &tags); err != nil {
+		return nil, err
 	}
+	return tags, nil
+}
+
+/*
+SearchTags returns all tags matching the given filter.
+*/
+func (c *ManagementClient) SearchTags(filter TagFilter) (Tags, error) {
+	return c.SearchTagsContext(context.Background(), filter)
+}
 
+/*
+SearchTagsContext is like SearchTags but carries the given context through to the http request.
+*/
+func (c *ManagementClient) SearchTagsContext(ctx context.Context, filter TagFilter) (Tags, error) {
 	var tags Tags
-	err = json.Unmarshal(response.Body(), &tags)
-	if err != nil {
-		return nil, errors.Wrap(err, "error during unmarshalling http response")
+	if err := c.search(ctx, tagResource, filter, &tags); err != nil {
+		return nil, err
 	}
 	return tags, nil
 }
@@ -1320,41 +1472,55 @@ func (c *ManagementClient) GetTags(filters map[string]string) (Tags, error) {
 DeleteTag deletes the tag with the given id.
 */
 func (c *ManagementClient) DeleteTag(id int) error {
-	if !c.isValid() {
-		return &NotValidError{}
-	}
+	return c.DeleteTagContext(context.Background(), id)
+}
 
-	response, err := c.request("DELETE", mgmtEndpointPath+"tags/"+strconv.Itoa(id), "", nil, nil)
-	if err != nil {
-		return errors.Wrap(err, "error during request")
-	}
-	if response.StatusCode() != 204 {
-		return getHttpError(response)
+/*
+DeleteTagContext is like DeleteTag but carries the given context through to the http request.
+*/
+func (c *ManagementClient) DeleteTagContext(ctx context.Context, id int) error {
+	return c.delete(ctx, tagResource, id)
+}
+
+/*
+UpdateTag changes the attributes of the tag with the given id which are set in the update and returns the updated tag.
+*/
+func (c *ManagementClient) UpdateTag(id int, update TagUpdate) (Tag, error) {
+	return c.UpdateTagContext(context.Background(), id, update)
+}
+
+/*
+UpdateTagContext is like UpdateTag but carries the given context through to the http request.
+*/
+func (c *ManagementClient) UpdateTagContext(ctx context.Context, id int, update TagUpdate) (Tag, error) {
+	var tag Tag
+	if err := c.update(ctx, tagResource, id, update, &tag); err != nil {
+		return Tag{}, err
 	}
-	return nil
+	return tag, nil
 }
 
 /*
 DeleteAllObjectsWithTag deletes all objects with the given tag.
 */
 func (c *ManagementClient) DeleteAllObjectsWithTag(tagId int) (Tag, error) {
-	if !c.isValid() {
-		return Tag{}, &NotValidError{}
-	}
-
-	response, err := c.request("DELETE", mgmtEndpointPath+"tags/"+strconv.Itoa(tagId)+"/objects", "", nil, nil)
-	if err != nil {
-		return Tag{}, errors.Wrap(err, "error during request")
-	}
-	if response.StatusCode() != 200 {
-		return Tag{}, getHttpError(response)
-	}
+	return c.DeleteAllObjectsWithTagContext(context.Background(), tagId)
+}
 
+/*
+DeleteAllObjectsWithTagContext is like DeleteAllObjectsWithTag but carries the given context through to the http request.
+*/
+func (c *ManagementClient) DeleteAllObjectsWithTagContext(ctx context.Context, tagId int) (Tag, error) {
 	var tag Tag
-	err = json.Unmarshal(response.Body(), &tag)
+	err := c.do(ctx, apiCall{
+		method:         "DELETE",
+		path:           tagResource.objectPath(tagId) + "/objects",
+		description:    "delete objects with tag",
+		expectedStatus: 200,
+		result:         &tag,
+	})
 	if err != nil {
-		return Tag{}, errors.Wrap(err, "error during unmarshalling http response")
+		return Tag{}, err
 	}
-
 	return tag, nil
 }
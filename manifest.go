@@ -0,0 +1,329 @@
+package snmpsimclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+/*
+ManifestVersion is the version of the manifest format written by WriteManifest and understood by LoadManifest.
+*/
+const ManifestVersion = 1
+
+/*
+ManifestFormat is the encoding of a manifest.
+*/
+type ManifestFormat string
+
+const (
+	//ManifestFormatYAML encodes a manifest as yaml
+	ManifestFormatYAML ManifestFormat = "yaml"
+	//ManifestFormatJSON encodes a manifest as json
+	ManifestFormatJSON ManifestFormat = "json"
+)
+
+/*
+Manifest describes labs together with the tags and record files they use. It can be exported from one control plane
+with ExportLab and applied to another one with ApplyManifest.
+*/
+type Manifest struct {
+	Version    int             `json:"version" yaml:"version"`
+	Tags       []TagSpec       `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Recordings []RecordingSpec `json:"recordings,omitempty" yaml:"recordings,omitempty"`
+	Labs       []LabSpec       `json:"labs,omitempty" yaml:"labs,omitempty"`
+}
+
+/*
+TagSpec describes a tag inside of a manifest.
+*/
+type TagSpec struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+/*
+RecordingEncodingBase64 is the encoding of the content of record files which are no valid UTF-8, e.g. compressed recordings.
+*/
+const RecordingEncodingBase64 = "base64"
+
+/*
+RecordingSpec describes a record file inside of a manifest. The path is relative to the data root of the api.
+*/
+type RecordingSpec struct {
+	Path    string `json:"path" yaml:"path"`
+	Content string `json:"content" yaml:"content"`
+	//Encoding is RecordingEncodingBase64 if the content is base64 encoded, it is empty if the content is plain text.
+	Encoding string `json:"encoding,omitempty" yaml:"encoding,omitempty"`
+}
+
+//decodedContent returns the original content of the record file
+func (r RecordingSpec) decodedContent() (string, error) {
+	switch r.Encoding {
+	case "":
+		return r.Content, nil
+	case RecordingEncodingBase64:
+		content, err := base64.StdEncoding.DecodeString(r.Content)
+		if err != nil {
+			return "", errors.Wrap(err, "error while decoding content")
+		}
+		return string(content), nil
+	default:
+		return "", errors.New("unsupported encoding '" + r.Encoding + "'")
+	}
+}
+
+/*
+LoadManifest reads a manifest in yaml or json format.
+*/
+func LoadManifest(r io.Reader) (Manifest, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return Manifest{}, errors.Wrap(err, "error while reading manifest")
+	}
+
+	var manifest Manifest
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		decoder := json.NewDecoder(bytes.NewReader(trimmed))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&manifest)
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.SetStrict(true)
+		err = decoder.Decode(&manifest)
+	}
+	if err != nil {
+		return Manifest{}, errors.Wrap(err, "error while decoding manifest")
+	}
+	if manifest.Version != ManifestVersion {
+		return Manifest{}, errors.New("unsupported manifest version " + strconv.Itoa(manifest.Version))
+	}
+	return manifest, nil
+}
+
+/*
+LoadManifestFile reads a manifest in yaml or json format from the given file.
+*/
+func LoadManifestFile(path string) (Manifest, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Manifest{}, errors.Wrap(err, "error while reading manifest file")
+	}
+	return LoadManifest(bytes.NewReader(data))
+}
+
+/*
+WriteManifest writes the manifest in the given format.
+*/
+func WriteManifest(w io.Writer, manifest Manifest, format ManifestFormat) error {
+	if manifest.Version == 0 {
+		manifest.Version = ManifestVersion
+	}
+
+	var data []byte
+	var err error
+	switch format {
+	case ManifestFormatYAML:
+		data, err = yaml.Marshal(manifest)
+	case ManifestFormatJSON:
+		data, err = json.MarshalIndent(manifest, "", "  ")
+	default:
+		return errors.New("invalid manifest format: " + string(format))
+	}
+	if err != nil {
+		return errors.Wrap(err, "error during marshal")
+	}
+	_, err = w.Write(data)
+	if err != nil {
+		return errors.Wrap(err, "error while writing manifest")
+	}
+	return nil
+}
+
+/*
+ExportLab exports the lab with the given id together with its tags and the record files in the data dirs of its agents into a manifest.
+The content of record files which are no valid UTF-8, like compressed recordings, is base64 encoded.
+*/
+func (c *ManagementClient) ExportLab(labId int) (Manifest, error) {
+	return c.ExportLabContext(context.Background(), labId)
+}
+
+/*
+ExportLabContext is like ExportLab but carries the given context through to the http requests.
+*/
+func (c *ManagementClient) ExportLabContext(ctx context.Context, labId int) (Manifest, error) {
+	if !c.isValid() {
+		return Manifest{}, &NotValidError{}
+	}
+
+	lab, err := c.GetLabContext(ctx, labId)
+	if err != nil {
+		return Manifest{}, errors.Wrap(err, "error while getting lab")
+	}
+
+	tags := make(map[string]TagSpec)
+	tagNames := func(objectTags Tags) []string {
+		var names []string
+		for _, tag := range objectTags {
+			tags[tag.Name] = TagSpec{Name: tag.Name, Description: tag.Description}
+			names = append(names, tag.Name)
+		}
+		return names
+	}
+
+	labSpec := LabSpec{Name: lab.Name, Power: lab.Power, Tags: tagNames(lab.Tags)}
+	var dataDirs []string
+	for _, labAgent := range lab.Agents {
+		agent, err := c.GetAgentContext(ctx, labAgent.Id)
+		if err != nil {
+			return Manifest{}, errors.Wrap(err, "error while getting agent '"+labAgent.Name+"'")
+		}
+		agentSpec := AgentSpec{Name: agent.Name, DataDir: agent.DataDir, Tags: tagNames(agent.Tags)}
+		dataDirs = append(dataDirs, agent.DataDir)
+
+		for _, agentEngine := range agent.Engines {
+			engine, err := c.GetEngineContext(ctx, agentEngine.Id)
+			if err != nil {
+				return Manifest{}, errors.Wrap(err, "error while getting engine '"+agentEngine.Name+"'")
+			}
+			engineSpec := EngineSpec{Name: engine.Name, EngineId: engine.EngineId, Tags: tagNames(engine.Tags)}
+			for _, endpoint := range engine.Endpoints {
+				engineSpec.Endpoints = append(engineSpec.Endpoints, EndpointSpec{
+					Name:     endpoint.Name,
+					Address:  endpoint.Address,
+					Protocol: endpoint.Protocol,
+					Tags:     tagNames(endpoint.Tags),
+				})
+			}
+			for _, user := range engine.Users {
+				engineSpec.Users = append(engineSpec.Users, UserSpec{
+					User:      user.User,
+					Name:      user.Name,
+					AuthKey:   user.AuthKey,
+					AuthProto: user.AuthProto,
+					PrivKey:   user.PrivKey,
+					PrivProto: user.PrivProto,
+					Tags:      tagNames(user.Tags),
+				})
+			}
+			agentSpec.Engines = append(agentSpec.Engines, engineSpec)
+		}
+		labSpec.Agents = append(labSpec.Agents, agentSpec)
+	}
+
+	manifest := Manifest{Version: ManifestVersion, Labs: []LabSpec{labSpec}}
+
+	recordings, err := c.GetRecordFilesContext(ctx)
+	if err != nil {
+		return Manifest{}, errors.Wrap(err, "error while getting record files")
+	}
+	for _, recording := range recordings {
+		if !isInDataDirs(recording.Path, dataDirs) {
+			continue
+		}
+		content, err := c.GetRecordFileContext(ctx, recording.Path)
+		if err != nil {
+			return Manifest{}, errors.Wrap(err, "error while getting record file '"+recording.Path+"'")
+		}
+		spec := RecordingSpec{Path: recording.Path, Content: content}
+		//compressed recordings would be corrupted by the yaml and json encodings, so they are stored base64 encoded
+		if !utf8.ValidString(content) {
+			spec.Content, spec.Encoding = base64.StdEncoding.EncodeToString([]byte(content)), RecordingEncodingBase64
+		}
+		manifest.Recordings = append(manifest.Recordings, spec)
+	}
+
+	for _, tag := range tags {
+		manifest.Tags = append(manifest.Tags, tag)
+	}
+	sort.Slice(manifest.Tags, func(i, j int) bool {
+		return manifest.Tags[i].Name < manifest.Tags[j].Name
+	})
+	return manifest, nil
+}
+
+/*
+ApplyManifest creates the tags of the manifest, uploads its record files (existing files are replaced) and applies all of its labs with ApplyLab.
+*/
+func (c *ManagementClient) ApplyManifest(manifest Manifest) (Labs, error) {
+	return c.ApplyManifestContext(context.Background(), manifest)
+}
+
+/*
+ApplyManifestContext is like ApplyManifest but carries the given context through to the http requests.
+*/
+func (c *ManagementClient) ApplyManifestContext(ctx context.Context, manifest Manifest) (Labs, error) {
+	if !c.isValid() {
+		return nil, &NotValidError{}
+	}
+	if manifest.Version != ManifestVersion {
+		return nil, errors.New("unsupported manifest version " + strconv.Itoa(manifest.Version))
+	}
+
+	//record files are decoded up front, so an invalid manifest does not change anything
+	contents := make([]string, len(manifest.Recordings))
+	for i, recording := range manifest.Recordings {
+		content, err := recording.decodedContent()
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid record file '"+recording.Path+"'")
+		}
+		contents[i] = content
+	}
+
+	tags, err := c.GetTagsContext(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "error while getting tags")
+	}
+	for _, spec := range manifest.Tags {
+		exists := false
+		for _, tag := range tags {
+			exists = exists || tag.Name == spec.Name
+		}
+		if exists {
+			continue
+		}
+		if _, err = c.CreateTagContext(ctx, spec.Name, spec.Description); err != nil {
+			return nil, errors.Wrap(err, "error while creating tag '"+spec.Name+"'")
+		}
+	}
+
+	for i, recording := range manifest.Recordings {
+		err = c.DeleteRecordFileContext(ctx, recording.Path)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, errors.Wrap(err, "error while replacing record file '"+recording.Path+"'")
+		}
+		if err = c.UploadRecordFileStringContext(ctx, &contents[i], recording.Path); err != nil {
+			return nil, errors.Wrap(err, "error while uploading record file '"+recording.Path+"'")
+		}
+	}
+
+	var labs Labs
+	for _, spec := range manifest.Labs {
+		lab, err := c.ApplyLabContext(ctx, spec)
+		if err != nil {
+			return nil, errors.Wrap(err, "error while applying lab '"+spec.Name+"'")
+		}
+		labs = append(labs, lab)
+	}
+	return labs, nil
+}
+
+//isInDataDirs checks if the given record file path is located inside of one of the data dirs
+func isInDataDirs(path string, dataDirs []string) bool {
+	for _, dataDir := range dataDirs {
+		dataDir = strings.Trim(dataDir, "/")
+		if dataDir == "" || dataDir == "." || strings.HasPrefix(strings.TrimLeft(path, "/"), dataDir+"/") {
+			return true
+		}
+	}
+	return false
+}
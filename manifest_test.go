@@ -0,0 +1,116 @@
+package snmpsimclient
+
+import (
+	"bytes"
+	"github.com/inexio/snmpsim-restapi-go-client/snmpsimtest"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestManifest_WriteAndLoad(t *testing.T) {
+	manifest := Manifest{
+		Version: ManifestVersion,
+		Tags:    []TagSpec{{Name: "manifest-test", Description: "tag for the manifest test"}},
+		Recordings: []RecordingSpec{
+			{Path: "manifest-test/agent/public.snmprec", Content: "1.3.6.1.2.1.1.1.0|4|manifest test\n"},
+		},
+		Labs: []LabSpec{
+			{
+				Name:  "manifest-test-lab",
+				Power: "on",
+				Tags:  []string{"manifest-test"},
+				Agents: []AgentSpec{
+					{
+						Name:    "manifest-test-agent",
+						DataDir: "manifest-test/agent",
+						Engines: []EngineSpec{
+							{
+								Name:      "manifest-test-engine",
+								EngineId:  "0102030405070809",
+								Endpoints: []EndpointSpec{{Name: "manifest-test-endpoint", Address: "127.0.0.1:1161", Protocol: "udpv4"}},
+								Users:     []UserSpec{{User: "manifest-test-user", Name: "manifest-test-user", AuthProto: "none", PrivProto: "none"}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, format := range []ManifestFormat{ManifestFormatYAML, ManifestFormatJSON} {
+		var buf bytes.Buffer
+		err := WriteManifest(&buf, manifest, format)
+		if !assert.NoError(t, err, "error while writing manifest as "+string(format)) {
+			continue
+		}
+		loaded, err := LoadManifest(&buf)
+		if assert.NoError(t, err, "error while loading manifest from "+string(format)) {
+			assert.Equal(t, manifest, loaded, "loaded manifest differs from written manifest")
+		}
+	}
+
+	err := WriteManifest(&bytes.Buffer{}, manifest, "xml")
+	assert.Error(t, err, "no error returned for an invalid manifest format")
+
+	_, err = LoadManifest(strings.NewReader("version: 2\n"))
+	assert.Error(t, err, "no error returned for an unsupported manifest version")
+
+	_, err = LoadManifest(strings.NewReader("version: 1\nlabz: []\n"))
+	assert.Error(t, err, "no error returned for an unknown manifest key")
+}
+
+func TestManagementClient_ExportLab_CompressedRecording(t *testing.T) {
+	server := snmpsimtest.NewServer()
+	defer server.Close()
+	text := []byte("1.3.6.1.2.1.1.1.0|4|manifest test\n")
+	compressed := []byte{0x42, 0x5a, 0x68, 0x39, 0xff, 0xfe, 0x00, 0x80, 0x81}
+	server.AddRecording("manifest-test/agent/public.snmprec", text)
+	server.AddRecording("manifest-test/agent/private.snmprec.bz2", compressed)
+
+	client, err := NewManagementClient(server.URL)
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+	lab, err := client.ApplyLab(LabSpec{Name: "manifest-test-lab", Agents: []AgentSpec{{Name: "manifest-test-agent", DataDir: "manifest-test/agent"}}})
+	if !assert.NoError(t, err, "error while applying lab spec") {
+		return
+	}
+	manifest, err := client.ExportLab(lab.Id)
+	if !assert.NoError(t, err, "error while exporting lab") || !assert.Len(t, manifest.Recordings, 2) {
+		return
+	}
+	for _, recording := range manifest.Recordings {
+		if strings.HasSuffix(recording.Path, ".bz2") {
+			assert.Equal(t, RecordingEncodingBase64, recording.Encoding, "compressed recording was not base64 encoded")
+		} else {
+			assert.Empty(t, recording.Encoding, "text recording was encoded")
+		}
+	}
+
+	for _, format := range []ManifestFormat{ManifestFormatYAML, ManifestFormatJSON} {
+		server.AddRecording("manifest-test/agent/public.snmprec", []byte("changed"))
+		server.AddRecording("manifest-test/agent/private.snmprec.bz2", []byte("changed"))
+
+		var buf bytes.Buffer
+		if !assert.NoError(t, WriteManifest(&buf, manifest, format), "error while writing manifest as "+string(format)) {
+			continue
+		}
+		loaded, err := LoadManifest(&buf)
+		if !assert.NoError(t, err, "error while loading manifest from "+string(format)) {
+			continue
+		}
+		_, err = client.ApplyManifest(loaded)
+		if !assert.NoError(t, err, "error while applying manifest loaded from "+string(format)) {
+			continue
+		}
+		content, _ := server.Recording("manifest-test/agent/public.snmprec")
+		assert.Equal(t, text, content, "text recording was changed by the "+string(format)+" round trip")
+		content, _ = server.Recording("manifest-test/agent/private.snmprec.bz2")
+		assert.Equal(t, compressed, content, "compressed recording was changed by the "+string(format)+" round trip")
+	}
+
+	manifest.Recordings[0].Encoding = "rot13"
+	_, err = client.ApplyManifest(manifest)
+	assert.Error(t, err, "manifest with an unsupported encoding was applied")
+}
@@ -15,6 +15,7 @@ func TestMetricsClient_BuildUpSetupAndTestMetrics(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping TestMetricsClient_BuildUpSetupAndTestMetrics in short mode")
 	}
+	skipWithFakeServer(t)
 	community := "public"
 	//	Agent 1
 	//Agent
@@ -54,7 +55,7 @@ func TestMetricsClient_BuildUpSetupAndTestMetrics(t *testing.T) {
 	//TODO: remove this when its possible to overwrite files
 	err = managementClient.DeleteRecordFile(remoteRecordFilePath1)
 	if err != nil {
-		if err, ok := err.(HttpError); assert.True(t, ok, "unknown error returned while deleting record file") {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "unknown error returned while deleting record file") {
 			if !assert.True(t, err.StatusCode == 404, "http error code for deleting record file is not 404! error: "+err.Error()) {
 				return
 			}
@@ -374,7 +375,7 @@ func TestMetricsClient_Processes(t *testing.T) {
 	//TODO: remove this when its possible to overwrite files
 	err = managementClient.DeleteRecordFile(remoteRecordFilePath1)
 	if err != nil {
-		if err, ok := err.(HttpError); assert.True(t, ok, "unknown error returned while deleting record file") {
+		if err, ok := err.(*HttpError); assert.True(t, ok, "unknown error returned while deleting record file") {
 			if !assert.True(t, err.StatusCode == 404, "http error code for deleting record file is not 404! error: "+err.Error()) {
 				return
 			}
@@ -536,7 +537,9 @@ func TestMetricsClient_Processes(t *testing.T) {
 	}()
 
 	//waiting for asynchronous metrics importer
-	time.Sleep(20 * time.Second)
+	if !configManagementTest.UseFakeServer {
+		time.Sleep(20 * time.Second)
+	}
 
 	//Test GetProcesses
 	processes, err := metricsClient.GetProcesses(nil)
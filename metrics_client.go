@@ -1,10 +1,18 @@
 package snmpsimclient
 
 import (
-	"encoding/json"
-	"github.com/go-resty/resty/v2"
+	"context"
 	"github.com/pkg/errors"
+	"sort"
 	"strconv"
+	"strings"
+)
+
+const (
+	//packetsPath is the path of the packet activity of the metrics api
+	packetsPath = metricsEndpointPath + "activity/packets"
+	//messagesPath is the path of the message activity of the metrics api
+	messagesPath = metricsEndpointPath + "activity/messages"
 )
 
 /*
@@ -12,39 +20,48 @@ MetricsClient is a client for communicating with the metrics api.
 */
 type MetricsClient struct {
 	client
+	catalog *FilterCatalog
 }
 
 /*
-NewMetricsClient creates a new NewMetricsClient.
+NewMetricsClient creates a new MetricsClient.
+Options can be given to configure the http transport, e.g. timeouts, tls or a proxy.
 */
-func NewMetricsClient(baseUrl string) (*MetricsClient, error) {
-	if baseUrl == "" {
-		return nil, errors.New("invalid base url")
+func NewMetricsClient(baseUrl string, opts ...ClientOption) (*MetricsClient, error) {
+	clientData, err := newClientData(baseUrl, opts)
+	if err != nil {
+		return nil, err
 	}
-	//if baseUrl does not end with an "/" it has to be added to the string
-	if lastChar := baseUrl[len(baseUrl)-1:]; lastChar != "/" {
-		baseUrl += "/"
+	metricsClient := &MetricsClient{client: client{clientData}}
+	metricsClient.catalog, err = NewFilterCatalog(metricsClient, DefaultFilterCatalogTTL)
+	if err != nil {
+		return nil, err
 	}
-	clientData := clientData{baseUrl: baseUrl, resty: resty.New(), useAuth: false}
-	newClient := client{&clientData}
-	return &MetricsClient{newClient}, nil
+	return metricsClient, nil
+}
+
+/*
+FilterCatalog returns the filter catalog of the client. It caches the filters of the api for DefaultFilterCatalogTTL
+and is used by SearchPackets and SearchMessages.
+*/
+func (c *MetricsClient) FilterCatalog() *FilterCatalog {
+	return c.catalog
 }
 
 /*
 GetProcesses returns process metrics.
 */
 func (c *MetricsClient) GetProcesses(filters map[string]string) (ProcessesMetrics, error) {
-	response, err := c.request("GET", metricsEndpointPath+"processes", "", nil, filters)
-	if err != nil {
-		return nil, errors.Wrap(err, "error during request")
-	}
-	if response.StatusCode() != 200 {
-		return nil, getHttpError(response)
-	}
+	return c.GetProcessesContext(context.Background(), filters)
+}
+
+/*
+GetProcessesContext is like GetProcesses but carries the given context through to the http request.
+*/
+func (c *MetricsClient) GetProcessesContext(ctx context.Context, filters map[string]string) (ProcessesMetrics, error) {
 	var processes ProcessesMetrics
-	err = json.Unmarshal(response.Body(), &processes)
-	if err != nil {
-		return nil, errors.Wrap(err, "error during unmarshalling http response")
+	if err := c.list(ctx, processResource, filters, &processes); err != nil {
+		return nil, err
 	}
 	return processes, nil
 }
@@ -53,17 +70,16 @@ func (c *MetricsClient) GetProcesses(filters map[string]string) (ProcessesMetric
 GetProcess returns the process with the given id.
 */
 func (c *MetricsClient) GetProcess(id int) (ProcessMetrics, error) {
-	response, err := c.request("GET", metricsEndpointPath+"processes/"+strconv.Itoa(id), "", nil, nil)
-	if err != nil {
-		return ProcessMetrics{}, errors.Wrap(err, "error during request")
-	}
-	if response.StatusCode() != 200 {
-		return ProcessMetrics{}, getHttpError(response)
-	}
+	return c.GetProcessContext(context.Background(), id)
+}
+
+/*
+GetProcessContext is like GetProcess but carries the given context through to the http request.
+*/
+func (c *MetricsClient) GetProcessContext(ctx context.Context, id int) (ProcessMetrics, error) {
 	var process ProcessMetrics
-	err = json.Unmarshal(response.Body(), &process)
-	if err != nil {
-		return ProcessMetrics{}, errors.Wrap(err, "error during unmarshalling http response")
+	if err := c.get(ctx, processResource, id, &process); err != nil {
+		return ProcessMetrics{}, err
 	}
 	return process, nil
 }
@@ -72,17 +88,23 @@ func (c *MetricsClient) GetProcess(id int) (ProcessMetrics, error) {
 GetProcessEndpoints returns an array of endpoints for the given process-id.
 */
 func (c *MetricsClient) GetProcessEndpoints(id int) (ProcessEndpoints, error) {
-	response, err := c.request("GET", metricsEndpointPath+"processes/"+strconv.Itoa(id)+"/endpoints", "", nil, nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "error during request")
-	}
-	if response.StatusCode() != 200 {
-		return nil, getHttpError(response)
-	}
+	return c.GetProcessEndpointsContext(context.Background(), id)
+}
+
+/*
+GetProcessEndpointsContext is like GetProcessEndpoints but carries the given context through to the http request.
+*/
+func (c *MetricsClient) GetProcessEndpointsContext(ctx context.Context, id int) (ProcessEndpoints, error) {
 	var endpoints ProcessEndpoints
-	err = json.Unmarshal(response.Body(), &endpoints)
+	err := c.do(ctx, apiCall{
+		method:         "GET",
+		path:           processResource.objectPath(id) + "/endpoints",
+		description:    "get process endpoints",
+		expectedStatus: 200,
+		result:         &endpoints,
+	})
 	if err != nil {
-		return nil, errors.Wrap(err, "error during unmarshalling http response")
+		return nil, err
 	}
 	return endpoints, nil
 }
@@ -91,17 +113,23 @@ func (c *MetricsClient) GetProcessEndpoints(id int) (ProcessEndpoints, error) {
 GetProcessEndpoint returns the endpoint for the given process- and endpoint-id.
 */
 func (c *MetricsClient) GetProcessEndpoint(processId int, endpointId int) (ProcessEndpoint, error) {
-	response, err := c.request("GET", metricsEndpointPath+"processes/"+strconv.Itoa(processId)+"/endpoints/"+strconv.Itoa(endpointId), "", nil, nil)
-	if err != nil {
-		return ProcessEndpoint{}, errors.Wrap(err, "error during request")
-	}
-	if response.StatusCode() != 200 {
-		return ProcessEndpoint{}, getHttpError(response)
-	}
+	return c.GetProcessEndpointContext(context.Background(), processId, endpointId)
+}
+
+/*
+GetProcessEndpointContext is like GetProcessEndpoint but carries the given context through to the http request.
+*/
+func (c *MetricsClient) GetProcessEndpointContext(ctx context.Context, processId int, endpointId int) (ProcessEndpoint, error) {
 	var endpoint ProcessEndpoint
-	err = json.Unmarshal(response.Body(), &endpoint)
+	err := c.do(ctx, apiCall{
+		method:         "GET",
+		path:           processResource.objectPath(processId) + "/endpoints/" + strconv.Itoa(endpointId),
+		description:    "get process endpoint",
+		expectedStatus: 200,
+		result:         &endpoint,
+	})
 	if err != nil {
-		return ProcessEndpoint{}, errors.Wrap(err, "error during unmarshalling http response")
+		return ProcessEndpoint{}, err
 	}
 	return endpoint, nil
 }
@@ -110,167 +138,271 @@ func (c *MetricsClient) GetProcessEndpoint(processId int, endpointId int) (Proce
 GetProcessConsolePages returns an array of console-pages for the given process-id.
 */
 func (c *MetricsClient) GetProcessConsolePages(processId int) (Consoles, error) {
-	response, err := c.request("GET", metricsEndpointPath+"processes/"+strconv.Itoa(processId)+"/console", "", nil, nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "error during request")
-	}
-	if response.StatusCode() != 200 {
-		return nil, getHttpError(response)
-	}
-	var consolePages Consoles
-	err = json.Unmarshal(response.Body(), &consolePages)
+	return c.GetProcessConsolePagesContext(context.Background(), processId)
+}
+
+/*
+GetProcessConsolePagesContext is like GetProcessConsolePages but carries the given context through to the http request.
+*/
+func (c *MetricsClient) GetProcessConsolePagesContext(ctx context.Context, processId int) (Consoles, error) {
+	var consoles Consoles
+	err := c.do(ctx, apiCall{
+		method:         "GET",
+		path:           processResource.objectPath(processId) + "/console",
+		description:    "get process console pages",
+		expectedStatus: 200,
+		result:         &consoles,
+	})
 	if err != nil {
-		return nil, errors.Wrap(err, "error during unmarshalling http response")
+		return nil, err
 	}
-	return consolePages, nil
+	return consoles, nil
 }
 
 /*
 GetProcessConsolePage returns the console-pages for the given process- and console-page-id.
 */
 func (c *MetricsClient) GetProcessConsolePage(processId int, pageId int) (Console, error) {
-	response, err := c.request("GET", metricsEndpointPath+"processes/"+strconv.Itoa(processId)+"/console/"+strconv.Itoa(pageId), "", nil, nil)
-	if err != nil {
-		return Console{}, errors.Wrap(err, "error during request")
-	}
-	if response.StatusCode() != 200 {
-		return Console{}, getHttpError(response)
-	}
-	var consolePages Console
-	err = json.Unmarshal(response.Body(), &consolePages)
+	return c.GetProcessConsolePageContext(context.Background(), processId, pageId)
+}
+
+/*
+GetProcessConsolePageContext is like GetProcessConsolePage but carries the given context through to the http request.
+*/
+func (c *MetricsClient) GetProcessConsolePageContext(ctx context.Context, processId int, pageId int) (Console, error) {
+	var console Console
+	err := c.do(ctx, apiCall{
+		method:         "GET",
+		path:           processResource.objectPath(processId) + "/console/" + strconv.Itoa(pageId),
+		description:    "get process console page",
+		expectedStatus: 200,
+		result:         &console,
+	})
 	if err != nil {
-		return Console{}, errors.Wrap(err, "error during unmarshalling http response")
+		return Console{}, err
 	}
-	return consolePages, nil
+	return console, nil
 }
 
 /*
 GetPackets returns packet metrics.
 */
 func (c *MetricsClient) GetPackets(filters map[string]string) (PacketMetrics, error) {
-	response, err := c.request("GET", metricsEndpointPath+"activity/packets", "", nil, filters)
-	if err != nil {
-		return PacketMetrics{}, errors.Wrap(err, "error during request")
-	}
-	if response.StatusCode() != 200 {
-		return PacketMetrics{}, getHttpError(response)
-	}
+	return c.GetPacketsContext(context.Background(), filters)
+}
+
+/*
+GetPacketsContext is like GetPackets but carries the given context through to the http request.
+*/
+func (c *MetricsClient) GetPacketsContext(ctx context.Context, filters map[string]string) (PacketMetrics, error) {
 	var packetMetrics PacketMetrics
-	err = json.Unmarshal(response.Body(), &packetMetrics)
+	err := c.do(ctx, apiCall{
+		method:         "GET",
+		path:           packetsPath,
+		description:    "get packets",
+		query:          filters,
+		expectedStatus: 200,
+		result:         &packetMetrics,
+	})
 	if err != nil {
-		return PacketMetrics{}, errors.Wrap(err, "error during unmarshalling http response")
+		return PacketMetrics{}, err
 	}
 	return packetMetrics, nil
 }
 
 /*
-GetPacketFilters returns all packet filters.
+SearchPackets returns packet metrics matching the given filter.
+The filter and its values are checked against the packet filters of the FilterCatalog of the client before the metrics
+are requested, a *FilterError is returned for unknown filters or values.
 */
-func (c *MetricsClient) GetPacketFilters() (PacketFilters, error) {
-	response, err := c.request("GET", metricsEndpointPath+"activity/packets/filters", "", nil, nil)
+func (c *MetricsClient) SearchPackets(filter PacketFilter) (PacketMetrics, error) {
+	return c.SearchPacketsContext(context.Background(), filter)
+}
+
+/*
+SearchPacketsContext is like SearchPackets but carries the given context through to the http requests.
+*/
+func (c *MetricsClient) SearchPacketsContext(ctx context.Context, filter PacketFilter) (PacketMetrics, error) {
+	if !c.isValid() {
+		return PacketMetrics{}, &NotValidError{}
+	}
+	params, err := filter.queryParams()
 	if err != nil {
-		return nil, errors.Wrap(err, "error during request")
+		return PacketMetrics{}, errors.Wrap(err, "invalid filter")
 	}
-	if response.StatusCode() != 200 {
-		return nil, getHttpError(response)
+	if err = c.catalog.validate(ctx, packetsPath, params); err != nil {
+		return PacketMetrics{}, err
 	}
+	return c.GetPacketsContext(ctx, params)
+}
 
-	var filters map[string]interface{}
-	err = json.Unmarshal(response.Body(), &filters)
-	if err != nil {
-		return nil, errors.Wrap(err, "error during unmarshalling http response")
-	}
+/*
+GetPacketFilters returns the names of all packet filters sorted by name.
+Use the FilterCatalog of the client to get the filters together with their possible values.
+*/
+func (c *MetricsClient) GetPacketFilters() (PacketFilters, error) {
+	return c.GetPacketFiltersContext(context.Background())
+}
 
-	var packetFilters PacketFilters
-	for key := range filters {
-		packetFilters = append(packetFilters, key)
+/*
+GetPacketFiltersContext is like GetPacketFilters but carries the given context through to the http request.
+*/
+func (c *MetricsClient) GetPacketFiltersContext(ctx context.Context) (PacketFilters, error) {
+	filters, err := c.getFilters(ctx, packetsPath)
+	if err != nil {
+		return nil, err
 	}
-
-	return packetFilters, nil
+	return PacketFilters(filters), nil
 }
 
 /*
 GetPossibleValuesForPacketFilter returns a list of all values that can be used for the given filter.
 */
 func (c *MetricsClient) GetPossibleValuesForPacketFilter(filter string) ([]string, error) {
-	response, err := c.request("GET", metricsEndpointPath+"activity/packets/filters/"+filter, "", nil, nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "error during request")
-	}
-	if response.StatusCode() != 200 {
-		return nil, getHttpError(response)
-	}
+	return c.GetPossibleValuesForPacketFilterContext(context.Background(), filter)
+}
 
-	var messageFilters []string
-	err = json.Unmarshal(response.Body(), &messageFilters)
+/*
+GetPossibleValuesForPacketFilterContext is like GetPossibleValuesForPacketFilter but carries the given context through to the http request.
+*/
+func (c *MetricsClient) GetPossibleValuesForPacketFilterContext(ctx context.Context, filter string) ([]string, error) {
+	var values []string
+	err := c.do(ctx, apiCall{
+		method:         "GET",
+		path:           packetsPath + "/filters/" + filter,
+		description:    "get packet filter values",
+		expectedStatus: 200,
+		result:         &values,
+	})
 	if err != nil {
-		return nil, errors.Wrap(err, "error during unmarshalling http response")
+		return nil, err
 	}
-	return messageFilters, nil
+	return values, nil
 }
 
 /*
 GetMessages returns message metrics.
 */
 func (c *MetricsClient) GetMessages(filters map[string]string) (MessageMetrics, error) {
-	response, err := c.request("GET", metricsEndpointPath+"activity/messages", "", nil, filters)
-	if err != nil {
-		return MessageMetrics{}, errors.Wrap(err, "error during request")
-	}
-	if response.StatusCode() != 200 {
-		return MessageMetrics{}, getHttpError(response)
-	}
+	return c.GetMessagesContext(context.Background(), filters)
+}
+
+/*
+GetMessagesContext is like GetMessages but carries the given context through to the http request.
+*/
+func (c *MetricsClient) GetMessagesContext(ctx context.Context, filters map[string]string) (MessageMetrics, error) {
 	var messageMetrics MessageMetrics
-	err = json.Unmarshal(response.Body(), &messageMetrics)
+	err := c.do(ctx, apiCall{
+		method:         "GET",
+		path:           messagesPath,
+		description:    "get messages",
+		query:          filters,
+		expectedStatus: 200,
+		result:         &messageMetrics,
+	})
 	if err != nil {
-		return MessageMetrics{}, errors.Wrap(err, "error during unmarshalling http response")
+		return MessageMetrics{}, err
 	}
 	return messageMetrics, nil
 }
 
 /*
-GetMessageFilters returns all message filters.
+SearchMessages returns message metrics matching the given filter.
+The filter and its values are checked against the message filters of the FilterCatalog of the client before the metrics
+are requested, a *FilterError is returned for unknown filters or values.
 */
-func (c *MetricsClient) GetMessageFilters() (MessageFilters, error) {
-	response, err := c.request("GET", metricsEndpointPath+"activity/messages/filters", "", nil, nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "error during request")
-	}
-	if response.StatusCode() != 200 {
-		return nil, getHttpError(response)
-	}
+func (c *MetricsClient) SearchMessages(filter MessageFilter) (MessageMetrics, error) {
+	return c.SearchMessagesContext(context.Background(), filter)
+}
 
-	var filters map[string]interface{}
-	err = json.Unmarshal(response.Body(), &filters)
+/*
+SearchMessagesContext is like SearchMessages but carries the given context through to the http requests.
+*/
+func (c *MetricsClient) SearchMessagesContext(ctx context.Context, filter MessageFilter) (MessageMetrics, error) {
+	if !c.isValid() {
+		return MessageMetrics{}, &NotValidError{}
+	}
+	params, err := filter.queryParams()
 	if err != nil {
-		return nil, errors.Wrap(err, "error during unmarshalling http response")
+		return MessageMetrics{}, errors.Wrap(err, "invalid filter")
+	}
+	if err = c.catalog.validate(ctx, messagesPath, params); err != nil {
+		return MessageMetrics{}, err
 	}
+	return c.GetMessagesContext(ctx, params)
+}
 
-	var messageFilters MessageFilters
+/*
+GetMessageFilters returns the names of all message filters sorted by name.
+Use the FilterCatalog of the client to get the filters together with their possible values.
+*/
+func (c *MetricsClient) GetMessageFilters() (MessageFilters, error) {
+	return c.GetMessageFiltersContext(context.Background())
+}
 
-	for key := range filters {
-		messageFilters = append(messageFilters, key)
+/*
+GetMessageFiltersContext is like GetMessageFilters but carries the given context through to the http request.
+*/
+func (c *MetricsClient) GetMessageFiltersContext(ctx context.Context) (MessageFilters, error) {
+	filters, err := c.getFilters(ctx, messagesPath)
+	if err != nil {
+		return nil, err
 	}
-
-	return messageFilters, nil
+	return MessageFilters(filters), nil
 }
 
 /*
 GetPossibleValuesForMessageFilter returns a list of all values that can be used for the given filter.
 */
 func (c *MetricsClient) GetPossibleValuesForMessageFilter(filter string) ([]string, error) {
-	response, err := c.request("GET", metricsEndpointPath+"activity/messages/filters/"+filter, "", nil, nil)
+	return c.GetPossibleValuesForMessageFilterContext(context.Background(), filter)
+}
+
+/*
+GetPossibleValuesForMessageFilterContext is like GetPossibleValuesForMessageFilter but carries the given context through to the http request.
+*/
+func (c *MetricsClient) GetPossibleValuesForMessageFilterContext(ctx context.Context, filter string) ([]string, error) {
+	var values []string
+	err := c.do(ctx, apiCall{
+		method:         "GET",
+		path:           messagesPath + "/filters/" + filter,
+		description:    "get message filter values",
+		expectedStatus: 200,
+		result:         &values,
+	})
 	if err != nil {
-		return nil, errors.Wrap(err, "error during request")
+		return nil, err
 	}
-	if response.StatusCode() != 200 {
-		return nil, getHttpError(response)
+	return values, nil
+}
+
+//getFilters returns the sorted names of the filters of the given activity
+func (c *MetricsClient) getFilters(ctx context.Context, activityPath string) ([]string, error) {
+	filters, err := c.filterMap(ctx, activityPath)
+	if err != nil {
+		return nil, err
 	}
 
-	var messageFilters []string
-	err = json.Unmarshal(response.Body(), &messageFilters)
+	names := make([]string, 0, len(filters))
+	for key := range filters {
+		names = append(names, key)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+//filterMap returns the filter map of the given activity as it is sent by the api
+func (c *MetricsClient) filterMap(ctx context.Context, activityPath string) (map[string]interface{}, error) {
+	var filters map[string]interface{}
+	err := c.do(ctx, apiCall{
+		method:         "GET",
+		path:           activityPath + "/filters",
+		description:    "get " + strings.TrimSuffix(activityName(activityPath), "s") + " filters",
+		expectedStatus: 200,
+		result:         &filters,
+	})
 	if err != nil {
-		return nil, errors.Wrap(err, "error during unmarshalling http response")
+		return nil, err
 	}
-	return messageFilters, nil
+	return filters, nil
 }
@@ -0,0 +1,233 @@
+package snmpsimclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"github.com/go-resty/resty/v2"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+/*
+ClientOption configures the transport of a ManagementClient or MetricsClient. Options are passed to NewManagementClient and NewMetricsClient.
+*/
+type ClientOption func(*clientOptions) error
+
+//clientOptions collects the settings of all given ClientOptions before the resty client is built
+type clientOptions struct {
+	httpClient *http.Client
+	transport  http.RoundTripper
+	timeout    time.Duration
+	tlsConfig  *tls.Config
+	proxyUrl   *url.URL
+
+	retryPolicy *RetryPolicy
+	cassette    *cassette
+
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+
+	validateRecordFiles bool
+}
+
+/*
+WithHTTPClient makes the client use the given http.Client instead of a newly created one.
+*/
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(o *clientOptions) error {
+		if httpClient == nil {
+			return errors.New("invalid http client")
+		}
+		o.httpClient = httpClient
+		return nil
+	}
+}
+
+/*
+WithTransport makes the client send its requests through the given http.RoundTripper.
+*/
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(o *clientOptions) error {
+		if transport == nil {
+			return errors.New("invalid transport")
+		}
+		o.transport = transport
+		return nil
+	}
+}
+
+/*
+WithTimeout sets the overall timeout of a single http request.
+*/
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(o *clientOptions) error {
+		if timeout < 0 {
+			return errors.New("invalid timeout")
+		}
+		o.timeout = timeout
+		return nil
+	}
+}
+
+/*
+WithTLSConfig sets the tls config used for https connections. It replaces any tls settings made by previous options.
+*/
+func WithTLSConfig(config *tls.Config) ClientOption {
+	return func(o *clientOptions) error {
+		if config == nil {
+			return errors.New("invalid tls config")
+		}
+		o.tlsConfig = config.Clone()
+		return nil
+	}
+}
+
+/*
+WithRootCAs makes the client trust the certificate authorities in the given pool.
+*/
+func WithRootCAs(pool *x509.CertPool) ClientOption {
+	return func(o *clientOptions) error {
+		if pool == nil {
+			return errors.New("invalid cert pool")
+		}
+		o.tls().RootCAs = pool
+		return nil
+	}
+}
+
+/*
+WithRootCAFile makes the client trust the PEM encoded certificate authorities in the given file.
+*/
+func WithRootCAFile(pemFilePath string) ClientOption {
+	return func(o *clientOptions) error {
+		pem, err := ioutil.ReadFile(pemFilePath)
+		if err != nil {
+			return errors.Wrap(err, "error while reading root ca file")
+		}
+		config := o.tls()
+		if config.RootCAs == nil {
+			config.RootCAs = x509.NewCertPool()
+		}
+		if !config.RootCAs.AppendCertsFromPEM(pem) {
+			return errors.New("no certificates found in root ca file")
+		}
+		return nil
+	}
+}
+
+/*
+WithClientCertificates sets the certificates the client presents to the server.
+*/
+func WithClientCertificates(certs ...tls.Certificate) ClientOption {
+	return func(o *clientOptions) error {
+		if len(certs) == 0 {
+			return errors.New("no certificates given")
+		}
+		config := o.tls()
+		config.Certificates = append(config.Certificates, certs...)
+		return nil
+	}
+}
+
+/*
+WithClientCertificateFile loads a PEM encoded certificate and key and presents it to the server.
+*/
+func WithClientCertificateFile(certFile, keyFile string) ClientOption {
+	return func(o *clientOptions) error {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return errors.Wrap(err, "error while loading client certificate")
+		}
+		config := o.tls()
+		config.Certificates = append(config.Certificates, cert)
+		return nil
+	}
+}
+
+/*
+WithInsecureSkipVerify disables the verification of the server certificate. This should only be used in lab environments.
+*/
+func WithInsecureSkipVerify() ClientOption {
+	return func(o *clientOptions) error {
+		o.tls().InsecureSkipVerify = true
+		return nil
+	}
+}
+
+/*
+WithProxy sends all requests through the proxy with the given url.
+*/
+func WithProxy(proxyUrl string) ClientOption {
+	return func(o *clientOptions) error {
+		parsed, err := url.Parse(proxyUrl)
+		if err != nil {
+			return errors.Wrap(err, "invalid proxy url")
+		}
+		if parsed.Scheme == "" || parsed.Host == "" {
+			return errors.New("invalid proxy url")
+		}
+		o.proxyUrl = parsed
+		return nil
+	}
+}
+
+/*
+WithRecordFileValidation makes UploadRecordFile and UploadRecordFileString parse and lint the record file with the
+snmprec package before it is uploaded. Invalid record files are not uploaded, the returned error contains all issues.
+*/
+func WithRecordFileValidation() ClientOption {
+	return func(o *clientOptions) error {
+		o.validateRecordFiles = true
+		return nil
+	}
+}
+
+//tls returns the tls config of the options and creates it if necessary
+func (o *clientOptions) tls() *tls.Config {
+	if o.tlsConfig == nil {
+		o.tlsConfig = &tls.Config{}
+	}
+	return o.tlsConfig
+}
+
+//newResty builds the resty client according to the options
+func (o *clientOptions) newResty() (*resty.Client, error) {
+	var restyClient *resty.Client
+	if o.httpClient != nil {
+		//resty modifies the http client, so the callers client is copied
+		httpClient := *o.httpClient
+		restyClient = resty.NewWithClient(&httpClient)
+	} else {
+		restyClient = resty.New()
+	}
+
+	if o.transport != nil {
+		restyClient.SetTransport(o.transport)
+	}
+
+	if o.tlsConfig != nil || o.proxyUrl != nil {
+		transport, ok := restyClient.GetClient().Transport.(*http.Transport)
+		if !ok {
+			return nil, errors.New("tls and proxy options can only be used with an *http.Transport")
+		}
+		//the transport might be shared with the caller, so a copy is modified
+		transport = transport.Clone()
+		if o.tlsConfig != nil {
+			transport.TLSClientConfig = o.tlsConfig
+		}
+		if o.proxyUrl != nil {
+			transport.Proxy = http.ProxyURL(o.proxyUrl)
+		}
+		restyClient.SetTransport(transport)
+	}
+
+	if o.timeout != 0 {
+		restyClient.SetTimeout(o.timeout)
+	}
+	return restyClient, nil
+}
@@ -0,0 +1,304 @@
+package snmpsimclient
+
+import (
+	"context"
+	"reflect"
+)
+
+//pager fetches the objects of a resource page by page, it is embedded into the typed pagers
+type pager struct {
+	ctx      context.Context
+	client   client
+	resource resource
+	opts     ListOptions
+	done     bool
+	err      error
+	//previous contains the ids of the objects of the previous page
+	previous []interface{}
+}
+
+func newPager(ctx context.Context, c client, r resource, opts ListOptions) pager {
+	if opts.Page == 0 {
+		opts.Page = 1
+	}
+	if opts.PerPage == 0 {
+		opts.PerPage = DefaultPageSize
+	}
+	return pager{ctx: ctx, client: c, resource: r, opts: opts}
+}
+
+//next fetches the next page into the result, which has to be a pointer to a slice
+func (p *pager) next(result interface{}) bool {
+	if p.done {
+		return false
+	}
+	query, err := p.opts.queryParams()
+	if err == nil {
+		err = p.client.list(p.ctx, p.resource, query, result)
+	}
+	if err != nil {
+		p.err = err
+		p.done = true
+		return false
+	}
+
+	page := reflect.ValueOf(result).Elem()
+	count := page.Len()
+	ids := pageIds(page)
+	//an api which ignores the paging parameters returns the same list again, which was already returned
+	if count != 0 && reflect.DeepEqual(ids, p.previous) {
+		page.Set(reflect.Zero(page.Type()))
+		p.done = true
+		return false
+	}
+	p.previous = ids
+	//a short page is the last one, a page larger than requested means the api returned the whole list
+	if count != p.opts.PerPage {
+		p.done = true
+	}
+	p.opts.Page++
+	if p.opts.Filter != nil {
+		applyTagFilter(p.opts.Filter, result)
+	}
+	return count != 0
+}
+
+//pageIds returns the ids of the objects of a page, objects without id are compared as a whole
+func pageIds(page reflect.Value) []interface{} {
+	ids := make([]interface{}, 0, page.Len())
+	for i := 0; i < page.Len(); i++ {
+		object := reflect.Indirect(page.Index(i))
+		if object.Kind() == reflect.Struct {
+			if id := object.FieldByName("Id"); id.IsValid() {
+				ids = append(ids, id.Interface())
+				continue
+			}
+		}
+		ids = append(ids, object.Interface())
+	}
+	return ids
+}
+
+/*
+Err returns the error which stopped the pager, it is nil if all pages were fetched successfully.
+*/
+func (p *pager) Err() error {
+	return p.err
+}
+
+/*
+LabPager fetches labs page by page. Next fetches the next page, which is returned by Labs.
+*/
+type LabPager struct {
+	pager
+	labs Labs
+}
+
+/*
+ListLabs returns a pager which lazily fetches the labs matching the options page by page.
+The given context is carried through to the http requests of the pager.
+*/
+func (c *ManagementClient) ListLabs(ctx context.Context, opts ListOptions) *LabPager {
+	return &LabPager{pager: newPager(ctx, c.client, labResource, opts)}
+}
+
+/*
+Next fetches the next page. It returns false if there are no more labs or an error occurred, which is returned by Err.
+*/
+func (p *LabPager) Next() bool {
+	p.labs = nil
+	return p.next(&p.labs)
+}
+
+/*
+Labs returns the labs of the page fetched by the last call of Next.
+*/
+func (p *LabPager) Labs() Labs {
+	return p.labs
+}
+
+/*
+EnginePager fetches engines page by page. Next fetches the next page, which is returned by Engines.
+*/
+type EnginePager struct {
+	pager
+	engines Engines
+}
+
+/*
+ListEngines returns a pager which lazily fetches the engines matching the options page by page.
+The given context is carried through to the http requests of the pager.
+*/
+func (c *ManagementClient) ListEngines(ctx context.Context, opts ListOptions) *EnginePager {
+	return &EnginePager{pager: newPager(ctx, c.client, engineResource, opts)}
+}
+
+/*
+Next fetches the next page. It returns false if there are no more engines or an error occurred, which is returned by Err.
+*/
+func (p *EnginePager) Next() bool {
+	p.engines = nil
+	return p.next(&p.engines)
+}
+
+/*
+Engines returns the engines of the page fetched by the last call of Next.
+*/
+func (p *EnginePager) Engines() Engines {
+	return p.engines
+}
+
+/*
+AgentPager fetches agents page by page. Next fetches the next page, which is returned by Agents.
+*/
+type AgentPager struct {
+	pager
+	agents Agents
+}
+
+/*
+ListAgents returns a pager which lazily fetches the agents matching the options page by page.
+The given context is carried through to the http requests of the pager.
+*/
+func (c *ManagementClient) ListAgents(ctx context.Context, opts ListOptions) *AgentPager {
+	return &AgentPager{pager: newPager(ctx, c.client, agentResource, opts)}
+}
+
+/*
+Next fetches the next page. It returns false if there are no more agents or an error occurred, which is returned by Err.
+*/
+func (p *AgentPager) Next() bool {
+	p.agents = nil
+	return p.next(&p.agents)
+}
+
+/*
+Agents returns the agents of the page fetched by the last call of Next.
+*/
+func (p *AgentPager) Agents() Agents {
+	return p.agents
+}
+
+/*
+EndpointPager fetches endpoints page by page. Next fetches the next page, which is returned by Endpoints.
+*/
+type EndpointPager struct {
+	pager
+	endpoints Endpoints
+}
+
+/*
+ListEndpoints returns a pager which lazily fetches the endpoints matching the options page by page.
+The given context is carried through to the http requests of the pager.
+*/
+func (c *ManagementClient) ListEndpoints(ctx context.Context, opts ListOptions) *EndpointPager {
+	return &EndpointPager{pager: newPager(ctx, c.client, endpointResource, opts)}
+}
+
+/*
+Next fetches the next page. It returns false if there are no more endpoints or an error occurred, which is returned by Err.
+*/
+func (p *EndpointPager) Next() bool {
+	p.endpoints = nil
+	return p.next(&p.endpoints)
+}
+
+/*
+Endpoints returns the endpoints of the page fetched by the last call of Next.
+*/
+func (p *EndpointPager) Endpoints() Endpoints {
+	return p.endpoints
+}
+
+/*
+UserPager fetches users page by page. Next fetches the next page, which is returned by Users.
+*/
+type UserPager struct {
+	pager
+	users Users
+}
+
+/*
+ListUsers returns a pager which lazily fetches the users matching the options page by page.
+The given context is carried through to the http requests of the pager.
+*/
+func (c *ManagementClient) ListUsers(ctx context.Context, opts ListOptions) *UserPager {
+	return &UserPager{pager: newPager(ctx, c.client, userResource, opts)}
+}
+
+/*
+Next fetches the next page. It returns false if there are no more users or an error occurred, which is returned by Err.
+*/
+func (p *UserPager) Next() bool {
+	p.users = nil
+	return p.next(&p.users)
+}
+
+/*
+Users returns the users of the page fetched by the last call of Next.
+*/
+func (p *UserPager) Users() Users {
+	return p.users
+}
+
+/*
+TagPager fetches tags page by page. Next fetches the next page, which is returned by Tags.
+*/
+type TagPager struct {
+	pager
+	tags Tags
+}
+
+/*
+ListTags returns a pager which lazily fetches the tags matching the options page by page.
+The given context is carried through to the http requests of the pager.
+*/
+func (c *ManagementClient) ListTags(ctx context.Context, opts ListOptions) *TagPager {
+	return &TagPager{pager: newPager(ctx, c.client, tagResource, opts)}
+}
+
+/*
+Next fetches the next page. It returns false if there are no more tags or an error occurred, which is returned by Err.
+*/
+func (p *TagPager) Next() bool {
+	p.tags = nil
+	return p.next(&p.tags)
+}
+
+/*
+Tags returns the tags of the page fetched by the last call of Next.
+*/
+func (p *TagPager) Tags() Tags {
+	return p.tags
+}
+
+/*
+ProcessPager fetches processes page by page. Next fetches the next page, which is returned by Processes.
+*/
+type ProcessPager struct {
+	pager
+	processes ProcessesMetrics
+}
+
+/*
+ListProcesses returns a pager which lazily fetches the processes matching the options page by page.
+The given context is carried through to the http requests of the pager.
+*/
+func (c *MetricsClient) ListProcesses(ctx context.Context, opts ListOptions) *ProcessPager {
+	return &ProcessPager{pager: newPager(ctx, c.client, processResource, opts)}
+}
+
+/*
+Next fetches the next page. It returns false if there are no more processes or an error occurred, which is returned by Err.
+*/
+func (p *ProcessPager) Next() bool {
+	p.processes = nil
+	return p.next(&p.processes)
+}
+
+/*
+Processes returns the processes of the page fetched by the last call of Next.
+*/
+func (p *ProcessPager) Processes() ProcessesMetrics {
+	return p.processes
+}
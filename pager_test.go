@@ -0,0 +1,122 @@
+package snmpsimclient
+
+import (
+	"context"
+	"github.com/inexio/snmpsim-restapi-go-client/snmpsimtest"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestListOptions_queryParams(t *testing.T) {
+	params, err := ListOptions{Filters: map[string]string{"name": "a"}, Page: 2, PerPage: 10, SortBy: "name", Descending: true}.queryParams()
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[string]string{"name": "a", "page": "2", "per_page": "10", "sort_by": "name", "order": "desc"}, params)
+	}
+	params, err = ListOptions{}.queryParams()
+	if assert.NoError(t, err) {
+		assert.Empty(t, params)
+	}
+
+	for _, opts := range []ListOptions{
+		{Page: -1},
+		{PerPage: -1},
+		{Descending: true},
+		{Filters: map[string]string{"page": "2"}},
+	} {
+		_, err = opts.queryParams()
+		assert.Error(t, err, "invalid options %+v were accepted", opts)
+	}
+}
+
+func TestManagementClient_ListAgents(t *testing.T) {
+	server := snmpsimtest.NewServer()
+	defer server.Close()
+
+	client, err := NewManagementClient(server.URL)
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+	for i := 1; i <= 5; i++ {
+		_, err = client.CreateAgent("agent "+strconv.Itoa(i), "agent"+strconv.Itoa(i))
+		if !assert.NoError(t, err, "error while creating agent") {
+			return
+		}
+	}
+
+	pager := client.ListAgents(context.Background(), ListOptions{PerPage: 2, SortBy: "name", Descending: true})
+	var pages [][]string
+	for pager.Next() {
+		var names []string
+		for _, agent := range pager.Agents() {
+			names = append(names, agent.Name)
+		}
+		pages = append(pages, names)
+	}
+	assert.NoError(t, pager.Err())
+	assert.Equal(t, [][]string{{"agent 5", "agent 4"}, {"agent 3", "agent 2"}, {"agent 1"}}, pages)
+
+	//a full last page is followed by an empty one
+	pager = client.ListAgents(context.Background(), ListOptions{PerPage: 5, Filters: map[string]string{"name": "agent 3"}})
+	if assert.True(t, pager.Next()) {
+		assert.Len(t, pager.Agents(), 1)
+	}
+	assert.False(t, pager.Next())
+	assert.NoError(t, pager.Err())
+
+	pager = client.ListAgents(context.Background(), ListOptions{SortBy: "unknown"})
+	assert.False(t, pager.Next())
+	assert.Error(t, pager.Err(), "sorting by an unknown attribute does not fail")
+}
+
+func TestMetricsClient_ListProcesses_NoPagingSupport(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(`[{"id": 1}, {"id": 2}, {"id": 3}]`))
+	}))
+	defer server.Close()
+
+	client, err := NewMetricsClient(server.URL)
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+
+	//an api ignoring the paging parameters returns the whole list at once
+	pager := client.ListProcesses(context.Background(), ListOptions{PerPage: 2})
+	if assert.True(t, pager.Next()) {
+		assert.Len(t, pager.Processes(), 3)
+	}
+	assert.False(t, pager.Next())
+	assert.NoError(t, pager.Err())
+	assert.Equal(t, 1, requests)
+}
+
+func TestManagementClient_ListLabs_PagingIgnored(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(`[{"id": 1, "name": "first"}, {"id": 2, "name": "second"}]`))
+	}))
+	defer server.Close()
+
+	client, err := NewManagementClient(server.URL)
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+
+	//the whole list has exactly the size of a page, so the repeated list has to end the pager
+	pager := client.ListLabs(context.Background(), ListOptions{PerPage: 2})
+	var pages int
+	for pager.Next() && pages < 10 {
+		pages++
+		assert.Len(t, pager.Labs(), 2)
+	}
+	assert.Equal(t, 1, pages, "pages of an api ignoring the paging parameters are repeated")
+	assert.Empty(t, pager.Labs())
+	assert.False(t, pager.Next())
+	assert.NoError(t, pager.Err())
+	assert.Equal(t, 2, requests)
+}
@@ -0,0 +1,242 @@
+package snmpsimclient
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"sync"
+	"time"
+)
+
+/*
+DefaultPollInterval is the interval of a MetricsPoller if PollOptions.Interval is not set.
+*/
+const DefaultPollInterval = 10 * time.Second
+
+/*
+PollOptions controls which metrics a MetricsPoller samples and how the samples are delivered.
+*/
+type PollOptions struct {
+	//Interval is the time between two samples. DefaultPollInterval is used if it is 0.
+	Interval time.Duration
+	//PacketFilters restricts the packet metrics like the filters of GetPackets.
+	PacketFilters map[string]string
+	//MessageFilters restricts the message metrics like the filters of GetMessages.
+	MessageFilters map[string]string
+	//Callback is called with every sample. If it is nil, the samples are delivered on the channel returned by Samples.
+	Callback func(MetricsSample)
+}
+
+/*
+Counter is the change of a cumulative counter between two samples.
+*/
+type Counter struct {
+	//Value is the cumulative value of the counter at the time of the sample.
+	Value int64
+	//Delta is the increase of the counter since the previous sample.
+	Delta int64
+	//Rate is the increase per second since the previous sample.
+	Rate float64
+	//Reset is set if the counter decreased, e.g. because the simulator was restarted. Delta is the value counted since the reset then.
+	Reset bool
+}
+
+/*
+PacketSample contains the packet counters of a sample.
+*/
+type PacketSample struct {
+	Total           Counter
+	ParseFailures   Counter
+	AuthFailures    Counter
+	ContextFailures Counter
+}
+
+/*
+MessageSample contains the message counters of a sample.
+*/
+type MessageSample struct {
+	Pdus     Counter
+	VarBinds Counter
+	Failures Counter
+}
+
+/*
+MetricsSample contains the changes of the packet and message counters since the previous sample.
+If the metrics could not be requested, Err is set and the counters are empty. The next sample is compared with the last successful one then.
+*/
+type MetricsSample struct {
+	Time     time.Time
+	Elapsed  time.Duration
+	Packets  PacketSample
+	Messages MessageSample
+	Err      error
+}
+
+/*
+MetricsPoller samples the packet and message metrics of a MetricsClient at a fixed interval. It is created with PollMetrics.
+*/
+type MetricsPoller struct {
+	client  *MetricsClient
+	opts    PollOptions
+	samples chan MetricsSample
+	cancel  context.CancelFunc
+	done    chan struct{}
+	once    sync.Once
+
+	//previous contains the counters of the last successful sample
+	previous     counterSnapshot
+	previousTime time.Time
+}
+
+//counterSnapshot contains the cumulative values of all polled counters
+type counterSnapshot struct {
+	total, parseFailures, authFailures, contextFailures int64
+	pdus, varBinds, failures                            int64
+}
+
+/*
+PollMetrics starts a poller which samples the metrics matching the filters of the options at a fixed interval.
+The first metrics are requested before PollMetrics returns and are only used as base for the first sample, an error is returned if they can not be requested.
+The poller runs until the context is done or Stop is called.
+*/
+func (c *MetricsClient) PollMetrics(ctx context.Context, opts PollOptions) (*MetricsPoller, error) {
+	if opts.Interval < 0 {
+		return nil, errors.New("invalid poll interval")
+	}
+	if opts.Interval == 0 {
+		opts.Interval = DefaultPollInterval
+	}
+
+	p := &MetricsPoller{client: c, opts: opts, done: make(chan struct{})}
+	snapshot, err := p.snapshot(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "error while requesting initial metrics")
+	}
+	p.previous, p.previousTime = snapshot, time.Now()
+	if opts.Callback == nil {
+		p.samples = make(chan MetricsSample)
+	}
+
+	ctx, p.cancel = context.WithCancel(ctx)
+	go p.run(ctx)
+	return p, nil
+}
+
+/*
+Samples returns the channel the samples are delivered on. It is closed after the poller has stopped.
+It returns nil if the samples are delivered to a callback.
+*/
+func (p *MetricsPoller) Samples() <-chan MetricsSample {
+	return p.samples
+}
+
+/*
+Stop stops the poller and waits until it has finished. A sample which is not received until then is dropped.
+Stop must not be called from the callback of the poller.
+*/
+func (p *MetricsPoller) Stop() {
+	p.once.Do(p.cancel)
+	<-p.done
+}
+
+//run samples the metrics at every tick until the context is done
+func (p *MetricsPoller) run(ctx context.Context) {
+	defer close(p.done)
+	if p.samples != nil {
+		defer close(p.samples)
+	}
+	ticker := time.NewTicker(p.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		sample := p.sample(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if p.opts.Callback != nil {
+			p.opts.Callback(sample)
+			continue
+		}
+		select {
+		case p.samples <- sample:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+//sample requests the current metrics and compares them with the previous ones
+func (p *MetricsPoller) sample(ctx context.Context) MetricsSample {
+	snapshot, err := p.snapshot(ctx)
+	now := time.Now()
+	if err != nil {
+		return MetricsSample{Time: now, Elapsed: now.Sub(p.previousTime), Err: err}
+	}
+	sample := diffSnapshots(p.previous, snapshot, now.Sub(p.previousTime))
+	sample.Time = now
+	p.previous, p.previousTime = snapshot, now
+	return sample
+}
+
+//snapshot requests the current values of all counters
+func (p *MetricsPoller) snapshot(ctx context.Context) (counterSnapshot, error) {
+	packets, err := p.client.GetPacketsContext(ctx, p.opts.PacketFilters)
+	if err != nil {
+		return counterSnapshot{}, err
+	}
+	messages, err := p.client.GetMessagesContext(ctx, p.opts.MessageFilters)
+	if err != nil {
+		return counterSnapshot{}, err
+	}
+	return counterSnapshot{
+		total:           int64Value(packets.Total),
+		parseFailures:   int64Value(packets.ParseFailures),
+		authFailures:    int64Value(packets.AuthFailures),
+		contextFailures: int64Value(packets.ContextFailures),
+		pdus:            int64Value(messages.Pdus),
+		varBinds:        int64Value(messages.VarBinds),
+		failures:        int64Value(messages.Failures),
+	}, nil
+}
+
+//diffSnapshots creates a sample from two snapshots which were taken the given time apart
+func diffSnapshots(previous, current counterSnapshot, elapsed time.Duration) MetricsSample {
+	return MetricsSample{
+		Elapsed: elapsed,
+		Packets: PacketSample{
+			Total:           diffCounter(previous.total, current.total, elapsed),
+			ParseFailures:   diffCounter(previous.parseFailures, current.parseFailures, elapsed),
+			AuthFailures:    diffCounter(previous.authFailures, current.authFailures, elapsed),
+			ContextFailures: diffCounter(previous.contextFailures, current.contextFailures, elapsed),
+		},
+		Messages: MessageSample{
+			Pdus:     diffCounter(previous.pdus, current.pdus, elapsed),
+			VarBinds: diffCounter(previous.varBinds, current.varBinds, elapsed),
+			Failures: diffCounter(previous.failures, current.failures, elapsed),
+		},
+	}
+}
+
+//diffCounter compares two values of a cumulative counter, a decreased counter has been reset and counts from 0 again
+func diffCounter(previous, current int64, elapsed time.Duration) Counter {
+	counter := Counter{Value: current, Delta: current - previous}
+	if current < previous {
+		counter.Delta = current
+		counter.Reset = true
+	}
+	if elapsed > 0 {
+		counter.Rate = float64(counter.Delta) / elapsed.Seconds()
+	}
+	return counter
+}
+
+func int64Value(value *int64) int64 {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
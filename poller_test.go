@@ -0,0 +1,103 @@
+package snmpsimclient
+
+import (
+	"context"
+	"github.com/inexio/snmpsim-restapi-go-client/snmpsimtest"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestDiffCounter(t *testing.T) {
+	assert.Equal(t, Counter{Value: 30, Delta: 20, Rate: 10}, diffCounter(10, 30, 2*time.Second))
+	assert.Equal(t, Counter{Value: 5, Delta: 5, Rate: 2.5, Reset: true}, diffCounter(10, 5, 2*time.Second), "counter reset was not detected")
+	assert.Equal(t, Counter{Value: 10}, diffCounter(10, 10, time.Second))
+	assert.Equal(t, Counter{Value: 10, Delta: 10}, diffCounter(0, 10, 0))
+}
+
+func TestMetricsClient_PollMetrics(t *testing.T) {
+	server := snmpsimtest.NewServer()
+	defer server.Close()
+	labels := map[string]string{"local_address": "127.0.0.1:1161"}
+	server.AddPacketActivity(snmpsimtest.PacketActivity{Labels: labels, Total: 100, AuthFailures: 1})
+	server.AddPacketActivity(snmpsimtest.PacketActivity{Labels: map[string]string{"local_address": "127.0.0.1:1162"}, Total: 1000})
+	server.AddMessageActivity(snmpsimtest.MessageActivity{Labels: labels, Pdus: 50, VarBinds: 70})
+
+	client, err := NewMetricsClient(server.URL)
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+
+	_, err = client.PollMetrics(context.Background(), PollOptions{Interval: -time.Second})
+	assert.Error(t, err, "negative interval was accepted")
+
+	poller, err := client.PollMetrics(context.Background(), PollOptions{
+		Interval:       50 * time.Millisecond,
+		PacketFilters:  labels,
+		MessageFilters: labels,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer poller.Stop()
+
+	//the counters are changed right after a sample was taken, so they are never sampled in between
+	server.AddPacketActivity(snmpsimtest.PacketActivity{Labels: labels, Total: 30})
+	server.AddMessageActivity(snmpsimtest.MessageActivity{Labels: labels, Pdus: 10, VarBinds: 20})
+	sample := <-poller.Samples()
+	if assert.NoError(t, sample.Err) {
+		assert.Equal(t, int64(130), sample.Packets.Total.Value)
+		assert.Equal(t, int64(30), sample.Packets.Total.Delta)
+		assert.InDelta(t, 30/sample.Elapsed.Seconds(), sample.Packets.Total.Rate, 0.001)
+		assert.Equal(t, int64(0), sample.Packets.AuthFailures.Delta)
+		assert.Equal(t, int64(10), sample.Messages.Pdus.Delta)
+		assert.Equal(t, int64(20), sample.Messages.VarBinds.Delta)
+		assert.False(t, sample.Packets.Total.Reset)
+	}
+
+	//a restarted simulator starts counting from 0 again
+	server.ResetActivity()
+	server.AddPacketActivity(snmpsimtest.PacketActivity{Labels: labels, Total: 5})
+	sample = <-poller.Samples()
+	if assert.NoError(t, sample.Err) {
+		assert.True(t, sample.Packets.Total.Reset)
+		assert.Equal(t, int64(5), sample.Packets.Total.Delta)
+		assert.True(t, sample.Messages.Pdus.Reset)
+		assert.Equal(t, int64(0), sample.Messages.Pdus.Delta)
+	}
+
+	poller.Stop()
+	_, ok := <-poller.Samples()
+	assert.False(t, ok, "samples channel was not closed")
+}
+
+func TestMetricsClient_PollMetricsCallback(t *testing.T) {
+	server := snmpsimtest.NewServer()
+	defer server.Close()
+
+	client, err := NewMetricsClient(server.URL)
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	samples := make(chan MetricsSample, 10)
+	poller, err := client.PollMetrics(ctx, PollOptions{
+		Interval: 10 * time.Millisecond,
+		Callback: func(sample MetricsSample) { samples <- sample },
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Nil(t, poller.Samples())
+
+	//a failed request results in a sample with an error, the poller keeps running
+	server.Close()
+	sample := <-samples
+	assert.Error(t, sample.Err)
+
+	cancel()
+	poller.Stop()
+	assert.NotPanics(t, poller.Stop, "second stop")
+}
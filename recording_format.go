@@ -0,0 +1,95 @@
+package snmpsimclient
+
+import (
+	"github.com/pkg/errors"
+	"strings"
+)
+
+/*
+RecordingFormat is the format of a simulation data file. snmpsim picks the format by the extension of the file.
+*/
+type RecordingFormat string
+
+const (
+	//RecordingFormatSnmprec is the native format of snmpsim, see the snmprec package
+	RecordingFormatSnmprec RecordingFormat = "snmprec"
+	//RecordingFormatSnmpwalk is the output of net-snmp's snmpwalk -On
+	RecordingFormatSnmpwalk RecordingFormat = "snmpwalk"
+	//RecordingFormatSapwalk is the walk format of the SimpleAgentPro simulator
+	RecordingFormatSapwalk RecordingFormat = "sapwalk"
+	//RecordingFormatDump is the walk format of the Net-SNMP perl module
+	RecordingFormatDump RecordingFormat = "dump"
+	//RecordingFormatMvc is the walk format of the MIMIC simulator
+	RecordingFormatMvc RecordingFormat = "mvc"
+)
+
+//compressedExtension is the extension of recordings which are compressed with bzip2
+const compressedExtension = ".bz2"
+
+/*
+RecordingFormats returns all formats served by snmpsim.
+*/
+func RecordingFormats() []RecordingFormat {
+	return []RecordingFormat{RecordingFormatSnmprec, RecordingFormatSnmpwalk, RecordingFormatSapwalk, RecordingFormatDump,
+		RecordingFormatMvc}
+}
+
+/*
+Valid checks whether the format is served by snmpsim.
+*/
+func (f RecordingFormat) Valid() bool {
+	for _, format := range RecordingFormats() {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+Extension returns the file extension of the format including the dot, e.g. ".snmprec".
+*/
+func (f RecordingFormat) Extension() string {
+	return "." + string(f)
+}
+
+/*
+RecordingFormatOf returns the format of the recording at the given path. compressed is true if the recording is
+compressed with bzip2, e.g. for "public.snmpwalk.bz2". An error is returned if snmpsim does not serve the file.
+*/
+func RecordingFormatOf(path string) (format RecordingFormat, compressed bool, err error) {
+	path = strings.TrimSpace(path)
+	if strings.HasSuffix(path, compressedExtension) {
+		path, compressed = strings.TrimSuffix(path, compressedExtension), true
+	}
+	if i := strings.LastIndexByte(path, '.'); i >= 0 && !strings.ContainsRune(path[i:], '/') {
+		format = RecordingFormat(path[i+1:])
+	}
+	if !format.Valid() {
+		return "", false, errors.New("file '" + path + "' is not a recording, supported formats are " +
+			joinFormats(RecordingFormats()))
+	}
+	return format, compressed, nil
+}
+
+/*
+Format returns the format of the recording and whether it is compressed with bzip2.
+*/
+func (r Recording) Format() (RecordingFormat, bool, error) {
+	return RecordingFormatOf(r.Path)
+}
+
+//validatable checks whether the record file at the given path can be validated with the snmprec package. Paths of
+//an unknown format are treated like snmprec files.
+func validatable(path string) bool {
+	format, compressed, err := RecordingFormatOf(path)
+	return err != nil || (format == RecordingFormatSnmprec && !compressed)
+}
+
+func joinFormats(formats []RecordingFormat) string {
+	extensions := make([]string, 0, len(formats))
+	for _, format := range formats {
+		extensions = append(extensions, format.Extension())
+	}
+	return strings.Join(extensions, ", ")
+}
@@ -0,0 +1,76 @@
+package snmpsimclient
+
+import (
+	"github.com/inexio/snmpsim-restapi-go-client/snmpsimtest"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecordingFormatOf(t *testing.T) {
+	for path, expected := range map[string]struct {
+		format     RecordingFormat
+		compressed bool
+	}{
+		"public.snmprec":              {RecordingFormatSnmprec, false},
+		"lab/public.snmpwalk":         {RecordingFormatSnmpwalk, false},
+		" lab/public.sapwalk ":        {RecordingFormatSapwalk, false},
+		"public.dump.bz2":             {RecordingFormatDump, true},
+		"lab.v2/public.mvc":           {RecordingFormatMvc, false},
+		"lab/device.1/public.snmprec": {RecordingFormatSnmprec, false},
+	} {
+		format, compressed, err := RecordingFormatOf(path)
+		if assert.NoError(t, err, path) {
+			assert.Equal(t, expected.format, format, path)
+			assert.Equal(t, expected.compressed, compressed, path)
+		}
+	}
+	for _, path := range []string{"", "public", "public.txt", "public.bz2", "lab.snmprec/public", ".snmprec.gz"} {
+		_, _, err := RecordingFormatOf(path)
+		assert.Error(t, err, path)
+	}
+
+	format, compressed, err := Recording{Path: "lab/public.snmpwalk.bz2"}.Format()
+	assert.NoError(t, err)
+	assert.Equal(t, RecordingFormatSnmpwalk, format)
+	assert.True(t, compressed)
+	assert.False(t, RecordingFormat("txt").Valid())
+	assert.Equal(t, ".sapwalk", RecordingFormatSapwalk.Extension())
+}
+
+func TestManagementClient_RecordingFormats(t *testing.T) {
+	server := snmpsimtest.NewServer()
+	defer server.Close()
+
+	client, err := NewManagementClient(server.URL, WithRecordFileValidation())
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+
+	//walks are not validated as snmprec files
+	walk := ".1.3.6.1.2.1.1.5.0 = STRING: router\n"
+	localPath := filepath.Join(t.TempDir(), "public.snmpwalk")
+	if !assert.NoError(t, ioutil.WriteFile(localPath, []byte(walk), 0600)) {
+		return
+	}
+	if assert.NoError(t, client.UploadRecordFile(localPath, "lab/public.snmpwalk")) {
+		content, err := client.GetRecordFile("lab/public.snmpwalk")
+		assert.NoError(t, err)
+		assert.Equal(t, walk, content)
+	}
+	assert.NoError(t, client.UploadRecordFileString(&walk, "lab/other.snmpwalk"))
+	assert.NoError(t, client.DeleteRecordFile("lab/other.snmpwalk"))
+
+	//the remote path has to be of the same format as the local file
+	assert.Error(t, client.UploadRecordFile(localPath, "lab/public.snmprec"), "walk was uploaded as snmprec file")
+	assert.Error(t, client.UploadRecordFile(localPath, "lab/public.snmpwalk.bz2"), "walk was uploaded as compressed walk")
+	assert.Error(t, client.UploadRecordFile(localPath, "lab/public.txt"), "walk was uploaded as unknown file")
+	_, found := server.Recording("lab/public.snmprec")
+	assert.False(t, found)
+
+	assert.Error(t, client.UploadRecordFile(filepath.Join(t.TempDir(), "public.txt"), "lab/public.txt"))
+	_, err = client.GetRecordFile("lab/public.txt")
+	assert.Error(t, err)
+	assert.Error(t, client.DeleteRecordFile("lab/public"))
+}
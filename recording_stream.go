@@ -0,0 +1,199 @@
+package snmpsimclient
+
+import (
+	"compress/gzip"
+	"context"
+	"github.com/go-resty/resty/v2"
+	"github.com/pkg/errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+//maxErrorBodySize limits the size of an error response which is read from a streamed response
+const maxErrorBodySize = 1 << 20
+
+/*
+TransferOption configures a streamed transfer of a recording with UploadRecording or DownloadRecording.
+*/
+type TransferOption func(*transferOptions)
+
+//transferOptions collects the settings of all given TransferOptions
+type transferOptions struct {
+	progress func(transferred, total int64)
+	gzip     bool
+}
+
+/*
+WithProgress makes the transfer call the given function whenever data was transferred. transferred is the number of
+uncompressed bytes transferred so far, total is the size of the recording or -1 if it is not known.
+*/
+func WithProgress(progress func(transferred, total int64)) TransferOption {
+	return func(o *transferOptions) {
+		o.progress = progress
+	}
+}
+
+/*
+WithGzip compresses uploads on the fly and asks the api to compress downloads. Uploads are sent with the Content-Encoding
+gzip, so the api has to accept compressed requests. Downloads are only compressed if the api supports it.
+*/
+func WithGzip() TransferOption {
+	return func(o *transferOptions) {
+		o.gzip = true
+	}
+}
+
+/*
+UploadRecording streams the recording read from the reader to the given remote path inside of the data dir, the recording
+is not buffered in memory. Streamed uploads are sent exactly once and are not validated, even if the client was created
+with WithRecordFileValidation.
+*/
+func (c *ManagementClient) UploadRecording(ctx context.Context, remotePath string, r io.Reader, opts ...TransferOption) error {
+	return c.uploadRecording(ctx, remotePath, r, opts)
+}
+
+/*
+DownloadRecording streams the recording at the given remote path into the writer, the recording is not buffered in memory.
+Streamed downloads are sent exactly once.
+*/
+func (c *ManagementClient) DownloadRecording(ctx context.Context, remotePath string, w io.Writer, opts ...TransferOption) error {
+	if !c.isValid() {
+		return &NotValidError{}
+	}
+	if w == nil {
+		return errors.New("invalid writer")
+	}
+	remotePath = strings.TrimSpace(remotePath)
+	if _, _, err := RecordingFormatOf(remotePath); err != nil {
+		return err
+	}
+	o := newTransferOptions(opts)
+
+	header := textHeader()
+	if o.gzip {
+		//setting the header disables the transparent decompression of the transport, the body is decompressed below
+		header["Accept-Encoding"] = "gzip"
+	}
+	response, err := c.send(ctx, "GET", recordingsPath+"/"+remotePath, header, nil, func(request *resty.Request) {
+		request.SetDoNotParseResponse(true)
+	}, false)
+	if err != nil {
+		return errors.Wrap(err, "error during download recording request")
+	}
+	body := response.RawBody()
+	defer body.Close()
+	if response.StatusCode() != 200 {
+		errorBody, _ := ioutil.ReadAll(io.LimitReader(body, maxErrorBodySize))
+		return getHttpErrorWithBody(response, errorBody)
+	}
+
+	var reader io.Reader = body
+	total := response.RawResponse.ContentLength
+	if strings.EqualFold(response.Header().Get("Content-Encoding"), "gzip") {
+		gzipReader, err := gzip.NewReader(body)
+		if err != nil {
+			return errors.Wrap(err, "error while decompressing recording")
+		}
+		defer gzipReader.Close()
+		reader, total = gzipReader, -1
+	}
+	if _, err = io.Copy(w, &progressReader{r: reader, total: total, progress: o.progress}); err != nil {
+		return errors.Wrap(err, "error while downloading recording")
+	}
+	return nil
+}
+
+//uploadRecording streams the recording to the api, it is used by all uploads of record files
+func (c *ManagementClient) uploadRecording(ctx context.Context, remotePath string, r io.Reader, opts []TransferOption) error {
+	if !c.isValid() {
+		return &NotValidError{}
+	}
+	if r == nil {
+		return errors.New("invalid reader")
+	}
+	remotePath = strings.TrimSpace(remotePath)
+	if _, _, err := RecordingFormatOf(remotePath); err != nil {
+		return err
+	}
+	o := newTransferOptions(opts)
+
+	header := textHeader()
+	var body io.Reader = &progressReader{r: r, total: readerSize(r), progress: o.progress}
+	if o.gzip {
+		header["Content-Encoding"] = "gzip"
+		pipeReader, pipeWriter := io.Pipe()
+		//closing the reader stops the compression if the request ends before the whole recording was sent
+		defer pipeReader.Close()
+		go func(uncompressed io.Reader) {
+			gzipWriter := gzip.NewWriter(pipeWriter)
+			_, err := io.Copy(gzipWriter, uncompressed)
+			if err == nil {
+				err = gzipWriter.Close()
+			}
+			_ = pipeWriter.CloseWithError(err)
+		}(body)
+		body = pipeReader
+	}
+
+	response, err := c.send(ctx, "POST", recordingsPath+"/"+remotePath, header, nil, func(request *resty.Request) {
+		request.SetBody(body)
+	}, false)
+	if err != nil {
+		return errors.Wrap(err, "error during upload recording request")
+	}
+	if response.StatusCode() != 204 {
+		return getHttpError(response)
+	}
+	return nil
+}
+
+//newTransferOptions applies the given options, nil options are ignored
+func newTransferOptions(opts []TransferOption) transferOptions {
+	var o transferOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return o
+}
+
+//progressReader reports the number of bytes read to a progress function
+type progressReader struct {
+	r           io.Reader
+	transferred int64
+	total       int64
+	progress    func(transferred, total int64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.transferred += int64(n)
+		if p.progress != nil {
+			p.progress(p.transferred, p.total)
+		}
+	}
+	return n, err
+}
+
+//readerSize returns the number of bytes left in the reader, or -1 if it is not known
+func readerSize(r io.Reader) int64 {
+	switch reader := r.(type) {
+	case interface{ Len() int }:
+		return int64(reader.Len())
+	case *os.File:
+		info, err := reader.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return -1
+		}
+		offset, err := reader.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		return info.Size() - offset
+	}
+	return -1
+}
@@ -0,0 +1,89 @@
+package snmpsimclient
+
+import (
+	"bytes"
+	"context"
+	"github.com/inexio/snmpsim-restapi-go-client/snmpsimtest"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+//recordingContent creates a large snmprec file
+func recordingContent(records int) []byte {
+	var buf bytes.Buffer
+	for i := 1; i <= records; i++ {
+		buf.WriteString("1.3.6.1.4.1.1." + strconv.Itoa(i) + ".0|4|" + strings.Repeat("x", 64) + "\n")
+	}
+	return buf.Bytes()
+}
+
+func TestManagementClient_StreamRecordings(t *testing.T) {
+	server := snmpsimtest.NewServer()
+	defer server.Close()
+
+	var mtx sync.Mutex
+	var requests []*http.Request
+	transport := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		mtx.Lock()
+		requests = append(requests, r)
+		mtx.Unlock()
+		return http.DefaultTransport.RoundTrip(r)
+	})
+	client, err := NewManagementClient(server.URL, WithTransport(transport))
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+	ctx := context.Background()
+	content := recordingContent(20000)
+
+	//the size of a reader without Len is unknown, it is sent without content length
+	var transferred, total int64
+	progress := WithProgress(func(t, s int64) { transferred, total = t, s })
+	err = client.UploadRecording(ctx, "streamed/public.snmprec", io.MultiReader(bytes.NewReader(content)), progress)
+	if assert.NoError(t, err) {
+		stored, ok := server.Recording("streamed/public.snmprec")
+		assert.True(t, ok)
+		assert.Equal(t, content, stored)
+		assert.Equal(t, int64(len(content)), transferred)
+		assert.Equal(t, int64(-1), total)
+		//a client request with a body and a content length of 0 is sent chunked
+		assert.Equal(t, int64(0), requests[len(requests)-1].ContentLength)
+	}
+
+	err = client.UploadRecording(ctx, "compressed/public.snmprec", bytes.NewReader(content), progress, WithGzip())
+	if assert.NoError(t, err) {
+		stored, _ := server.Recording("compressed/public.snmprec")
+		assert.Equal(t, content, stored)
+		assert.Equal(t, int64(len(content)), total)
+		assert.Equal(t, "gzip", requests[len(requests)-1].Header.Get("Content-Encoding"))
+	}
+
+	var downloaded bytes.Buffer
+	transferred, total = 0, 0
+	if assert.NoError(t, client.DownloadRecording(ctx, "streamed/public.snmprec", &downloaded, progress)) {
+		assert.Equal(t, content, downloaded.Bytes())
+		assert.Equal(t, int64(len(content)), transferred)
+	}
+
+	downloaded.Reset()
+	if assert.NoError(t, client.DownloadRecording(ctx, "compressed/public.snmprec", &downloaded, progress, WithGzip())) {
+		assert.Equal(t, content, downloaded.Bytes())
+		assert.Equal(t, "gzip", requests[len(requests)-1].Header.Get("Accept-Encoding"))
+		assert.Equal(t, int64(-1), total, "the size of a compressed download is unknown")
+	}
+
+	err = client.DownloadRecording(ctx, "missing.snmprec", &downloaded)
+	assert.True(t, errors.Is(err, ErrNotFound), "unexpected error %v", err)
+	err = client.UploadRecording(ctx, "streamed/public.snmprec", bytes.NewReader(content))
+	assert.True(t, errors.Is(err, ErrConflict), "unexpected error %v", err)
+	assert.Error(t, client.UploadRecording(ctx, "", bytes.NewReader(content)))
+	assert.Error(t, client.DownloadRecording(ctx, "streamed/public.snmprec", nil))
+	assert.Error(t, client.DownloadRecording(ctx, "streamed/public.txt", &downloaded), "file which is no recording was downloaded")
+	assert.Error(t, client.UploadRecording(ctx, "streamed/public.txt", bytes.NewReader(content)), "file which is no recording was uploaded")
+}
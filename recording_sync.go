@@ -0,0 +1,311 @@
+package snmpsimclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"github.com/inexio/snmpsim-restapi-go-client/snmprec"
+	"github.com/pkg/errors"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+/*
+DefaultSyncParallelism is the number of parallel transfers of SyncRecordings if SyncOptions.Parallelism is not set.
+*/
+const DefaultSyncParallelism = 4
+
+/*
+SyncOptions controls how SyncRecordings changes the remote recordings.
+*/
+type SyncOptions struct {
+	//DryRun only reports the changes which would be made, no recording is uploaded or deleted.
+	DryRun bool
+	//Delete deletes remote recordings below the remote prefix which do not exist in the local dir.
+	Delete bool
+	//Parallelism is the maximum number of parallel transfers. DefaultSyncParallelism is used if it is 0.
+	Parallelism int
+}
+
+/*
+SyncAction is the kind of change SyncRecordings makes to a remote recording.
+*/
+type SyncAction string
+
+const (
+	//SyncActionUpload uploads a recording which does not exist remotely
+	SyncActionUpload SyncAction = "upload"
+	//SyncActionReplace replaces a remote recording whose content differs from the local file. The api can not replace
+	//recordings, so the remote recording is deleted before the local file is uploaded. If the upload fails, the previous
+	//recording is uploaded again. Only if that fails as well the recording is left deleted, which is stated by the error.
+	SyncActionReplace SyncAction = "replace"
+	//SyncActionDelete deletes a remote recording which does not exist locally
+	SyncActionDelete SyncAction = "delete"
+)
+
+/*
+SyncChange is a change of a remote recording made by SyncRecordings.
+*/
+type SyncChange struct {
+	Action SyncAction
+	//RemotePath is the path of the recording relative to the data root.
+	RemotePath string
+	//LocalPath is the path of the local file, it is empty for deletions.
+	LocalPath string
+	//Err is the error which made the change fail, it is always nil in a dry run. See SyncActionReplace for the state of
+	//a recording after a failed replace.
+	Err error
+}
+
+/*
+SyncReport lists the changes made by SyncRecordings, or the changes which would be made in a dry run.
+*/
+type SyncReport struct {
+	DryRun bool
+	//Changes are sorted by their remote path.
+	Changes []SyncChange
+	//Unchanged are the remote paths of recordings which already match the local files.
+	Unchanged []string
+}
+
+/*
+SyncError is returned by SyncRecordings if changes failed. The report returned together with the error contains all changes.
+*/
+type SyncError struct {
+	Failed []SyncChange
+}
+
+func (e *SyncError) Error() string {
+	messages := make([]string, 0, len(e.Failed))
+	for _, change := range e.Failed {
+		messages = append(messages, string(change.Action)+" '"+change.RemotePath+"': "+change.Err.Error())
+	}
+	return strconv.Itoa(len(e.Failed)) + " changes failed: " + strings.Join(messages, "; ")
+}
+
+/*
+SyncRecordings makes the recordings below the remote prefix match the recordings in the local dir, e.g. the local file
+"<localDir>/lab/public.snmprec" is synced to "<remotePrefix>/lab/public.snmprec". Local files of a format which is not
+served by snmpsim and hidden directories are ignored. Recordings which exist on both sides are compared by the sha256
+hash of their content and only replaced if they differ. Remote recordings which do not exist locally are only deleted
+if SyncOptions.Delete is set. An empty remote prefix syncs the whole data root.
+*/
+func (c *ManagementClient) SyncRecordings(localDir, remotePrefix string, opts SyncOptions) (SyncReport, error) {
+	return c.SyncRecordingsContext(context.Background(), localDir, remotePrefix, opts)
+}
+
+/*
+SyncRecordingsContext is like SyncRecordings but carries the given context through to the http requests.
+*/
+func (c *ManagementClient) SyncRecordingsContext(ctx context.Context, localDir, remotePrefix string, opts SyncOptions) (SyncReport, error) {
+	if !c.isValid() {
+		return SyncReport{}, &NotValidError{}
+	}
+	if opts.Parallelism < 0 {
+		return SyncReport{}, errors.New("invalid parallelism")
+	}
+	if opts.Parallelism == 0 {
+		opts.Parallelism = DefaultSyncParallelism
+	}
+	remotePrefix = strings.Trim(strings.TrimSpace(remotePrefix), "/")
+
+	local, err := localRecordings(localDir, remotePrefix)
+	if err != nil {
+		return SyncReport{}, err
+	}
+	recordings, err := c.GetRecordFilesContext(ctx)
+	if err != nil {
+		return SyncReport{}, errors.Wrap(err, "error while getting record files")
+	}
+	remote := make(map[string]bool)
+	for _, recording := range recordings {
+		remotePath := strings.TrimLeft(recording.Path, "/")
+		if _, _, err := RecordingFormatOf(remotePath); err == nil && isInDataDirs(remotePath, []string{remotePrefix}) {
+			remote[remotePath] = true
+		}
+	}
+
+	report := SyncReport{DryRun: opts.DryRun}
+	var compared []string
+	for remotePath, localPath := range local {
+		if !remote[remotePath] {
+			report.Changes = append(report.Changes, SyncChange{Action: SyncActionUpload, RemotePath: remotePath, LocalPath: localPath})
+		} else {
+			compared = append(compared, remotePath)
+		}
+	}
+	if opts.Delete {
+		for remotePath := range remote {
+			if _, ok := local[remotePath]; !ok {
+				report.Changes = append(report.Changes, SyncChange{Action: SyncActionDelete, RemotePath: remotePath})
+			}
+		}
+	}
+
+	//recordings on both sides are downloaded to compare their hashes, which are not provided by the api
+	equal := make([]bool, len(compared))
+	compareErrs := make([]error, len(compared))
+	forEach(len(compared), opts.Parallelism, func(i int) {
+		equal[i], compareErrs[i] = c.recordingEqual(ctx, local[compared[i]], compared[i])
+	})
+	for i, remotePath := range compared {
+		if compareErrs[i] != nil {
+			return SyncReport{}, compareErrs[i]
+		}
+		if equal[i] {
+			report.Unchanged = append(report.Unchanged, remotePath)
+		} else {
+			report.Changes = append(report.Changes, SyncChange{Action: SyncActionReplace, RemotePath: remotePath, LocalPath: local[remotePath]})
+		}
+	}
+	sort.Strings(report.Unchanged)
+	sort.Slice(report.Changes, func(i, j int) bool {
+		return report.Changes[i].RemotePath < report.Changes[j].RemotePath
+	})
+	if opts.DryRun {
+		return report, nil
+	}
+
+	forEach(len(report.Changes), opts.Parallelism, func(i int) {
+		report.Changes[i].Err = c.applySyncChange(ctx, report.Changes[i])
+	})
+	var failed []SyncChange
+	for _, change := range report.Changes {
+		if change.Err != nil {
+			failed = append(failed, change)
+		}
+	}
+	if len(failed) != 0 {
+		return report, &SyncError{Failed: failed}
+	}
+	return report, nil
+}
+
+//localRecordings returns the local paths of all recordings in the local dir by their remote paths
+func localRecordings(localDir, remotePrefix string) (map[string]string, error) {
+	info, err := os.Stat(localDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "error while reading local dir")
+	}
+	if !info.IsDir() {
+		return nil, errors.New("'" + localDir + "' is not a directory")
+	}
+
+	local := make(map[string]string)
+	err = filepath.WalkDir(localDir, func(localPath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if localPath != localDir && strings.HasPrefix(entry.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if _, _, err := RecordingFormatOf(entry.Name()); err != nil || !entry.Type().IsRegular() {
+			return nil
+		}
+		relativePath, err := filepath.Rel(localDir, localPath)
+		if err != nil {
+			return err
+		}
+		local[path.Join(remotePrefix, filepath.ToSlash(relativePath))] = localPath
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error while reading local dir")
+	}
+	return local, nil
+}
+
+//recordingEqual checks whether the local file and the remote recording have the same content
+func (c *ManagementClient) recordingEqual(ctx context.Context, localPath, remotePath string) (bool, error) {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return false, errors.Wrap(err, "error while reading file")
+	}
+	defer file.Close()
+	localHash := sha256.New()
+	if _, err = io.Copy(localHash, file); err != nil {
+		return false, errors.Wrap(err, "error while reading file")
+	}
+
+	remoteHash := sha256.New()
+	if err = c.DownloadRecording(ctx, remotePath, remoteHash); err != nil {
+		return false, errors.Wrap(err, "error while getting record file '"+remotePath+"'")
+	}
+	return bytes.Equal(localHash.Sum(nil), remoteHash.Sum(nil)), nil
+}
+
+//applySyncChange uploads, replaces or deletes a remote recording
+func (c *ManagementClient) applySyncChange(ctx context.Context, change SyncChange) error {
+	switch change.Action {
+	case SyncActionDelete:
+		return c.DeleteRecordFileContext(ctx, change.RemotePath)
+	case SyncActionReplace:
+		return c.replaceRecording(ctx, change)
+	}
+	return c.UploadRecordFileContext(ctx, change.LocalPath, change.RemotePath)
+}
+
+//replaceRecording deletes the remote recording and uploads the local file, the previous recording is restored if the
+//upload fails
+func (c *ManagementClient) replaceRecording(ctx context.Context, change SyncChange) error {
+	//the recording is validated before it is deleted, so an invalid file does not remove the remote recording
+	if c.validateRecordFiles && validatable(change.LocalPath) {
+		b, err := ioutil.ReadFile(change.LocalPath)
+		if err != nil {
+			return errors.Wrap(err, "error while reading file")
+		}
+		if err = snmprec.Validate(b); err != nil {
+			return errors.Wrap(err, "invalid record file")
+		}
+	}
+	var previous bytes.Buffer
+	existed := true
+	if err := c.DownloadRecording(ctx, change.RemotePath, &previous); errors.Is(err, ErrNotFound) {
+		existed = false
+	} else if err != nil {
+		return errors.Wrap(err, "error while getting record file '"+change.RemotePath+"'")
+	}
+	err := c.DeleteRecordFileContext(ctx, change.RemotePath)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	err = c.UploadRecordFileContext(ctx, change.LocalPath, change.RemotePath)
+	if err == nil || !existed {
+		return err
+	}
+	//the previous recording is restored even if the context was cancelled during the upload
+	if restoreErr := c.UploadRecording(context.Background(), change.RemotePath, &previous); restoreErr != nil {
+		return errors.Wrap(err, "recording was deleted and could not be restored ("+restoreErr.Error()+")")
+	}
+	return errors.Wrap(err, "previous recording was restored")
+}
+
+//forEach calls the function for all indexes from 0 to n-1, at most parallelism calls run at the same time
+func forEach(n, parallelism int, f func(i int)) {
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			f(i)
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,173 @@
+package snmpsimclient
+
+import (
+	"bytes"
+	"github.com/inexio/snmpsim-restapi-go-client/snmpsimtest"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+//writeLocalRecordings creates the given files in a temporary dir
+func writeLocalRecordings(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		localPath := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(localPath), 0700))
+		assert.NoError(t, ioutil.WriteFile(localPath, []byte(content), 0600))
+	}
+	return dir
+}
+
+func TestManagementClient_SyncRecordings(t *testing.T) {
+	server := snmpsimtest.NewServer()
+	defer server.Close()
+	server.AddRecording("tests/first/public.snmprec", []byte("1.3.6.1.2.1.1.1.0|4|first\n"))
+	server.AddRecording("tests/second/public.snmprec", []byte("1.3.6.1.2.1.1.1.0|4|old\n"))
+	server.AddRecording("tests/removed/public.snmprec", []byte("1.3.6.1.2.1.1.1.0|4|removed\n"))
+	server.AddRecording("other/public.snmprec", []byte("1.3.6.1.2.1.1.1.0|4|other\n"))
+
+	var inFlight, maxInFlight int32
+	transport := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		return http.DefaultTransport.RoundTrip(r)
+	})
+	client, err := NewManagementClient(server.URL, WithTransport(transport))
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+
+	dir := writeLocalRecordings(t, map[string]string{
+		"first/public.snmprec":     "1.3.6.1.2.1.1.1.0|4|first\n",
+		"second/public.snmprec":    "1.3.6.1.2.1.1.1.0|4|new\n",
+		"third/public.snmpwalk":    ".1.3.6.1.2.1.1.1.0 = STRING: third\n",
+		"third/a/b/public.snmprec": "1.3.6.1.2.1.1.1.0|4|nested\n",
+		"README.md":                "not a recording",
+		".git/public.snmprec":      "1.3.6.1.2.1.1.1.0|4|hidden\n",
+	})
+
+	report, err := client.SyncRecordings(dir, "/tests/", SyncOptions{DryRun: true, Delete: true})
+	if assert.NoError(t, err) && assert.Len(t, report.Changes, 4) {
+		assert.True(t, report.DryRun)
+		assert.Equal(t, SyncChange{Action: SyncActionReplace, RemotePath: "tests/second/public.snmprec",
+			LocalPath: filepath.Join(dir, "second", "public.snmprec")}, report.Changes[1])
+		assert.Equal(t, []string{"tests/first/public.snmprec"}, report.Unchanged)
+		content, _ := server.Recording("tests/second/public.snmprec")
+		assert.Equal(t, "1.3.6.1.2.1.1.1.0|4|old\n", string(content), "dry run changed a recording")
+	}
+	var actions []string
+	for _, change := range report.Changes {
+		actions = append(actions, string(change.Action)+" "+change.RemotePath)
+	}
+	assert.Equal(t, []string{
+		"delete tests/removed/public.snmprec",
+		"replace tests/second/public.snmprec",
+		"upload tests/third/a/b/public.snmprec",
+		"upload tests/third/public.snmpwalk",
+	}, actions)
+
+	//without delete, remote recordings which do not exist locally are kept
+	report, err = client.SyncRecordings(dir, "tests", SyncOptions{Parallelism: 2})
+	if assert.NoError(t, err) {
+		assert.Len(t, report.Changes, 3)
+		assert.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(2))
+		content, _ := server.Recording("tests/second/public.snmprec")
+		assert.Equal(t, "1.3.6.1.2.1.1.1.0|4|new\n", string(content))
+		content, _ = server.Recording("tests/third/a/b/public.snmprec")
+		assert.Equal(t, "1.3.6.1.2.1.1.1.0|4|nested\n", string(content))
+		_, ok := server.Recording("tests/removed/public.snmprec")
+		assert.True(t, ok)
+		_, ok = server.Recording("tests/.git/public.snmprec")
+		assert.False(t, ok, "hidden dir was synced")
+	}
+
+	report, err = client.SyncRecordings(dir, "tests", SyncOptions{Delete: true})
+	if assert.NoError(t, err) && assert.Len(t, report.Changes, 1) {
+		assert.Equal(t, SyncActionDelete, report.Changes[0].Action)
+		assert.Len(t, report.Unchanged, 4)
+		_, ok := server.Recording("tests/removed/public.snmprec")
+		assert.False(t, ok)
+		_, ok = server.Recording("other/public.snmprec")
+		assert.True(t, ok, "recording outside of the prefix was deleted")
+	}
+
+	//an invalid record file is reported and does not remove the remote recording
+	client, err = NewManagementClient(server.URL, WithRecordFileValidation())
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "first", "public.snmprec"), []byte("1.3.6.1.2.1.1.1.0|2|invalid\n"), 0600))
+	report, err = client.SyncRecordings(dir, "tests", SyncOptions{})
+	var syncErr *SyncError
+	if assert.True(t, errors.As(err, &syncErr), "unexpected error %v", err) && assert.Len(t, syncErr.Failed, 1) {
+		assert.Equal(t, "tests/first/public.snmprec", syncErr.Failed[0].RemotePath)
+		assert.Equal(t, report.Changes, syncErr.Failed)
+	}
+	content, _ := server.Recording("tests/first/public.snmprec")
+	assert.Equal(t, "1.3.6.1.2.1.1.1.0|4|first\n", string(content))
+
+	_, err = client.SyncRecordings(filepath.Join(dir, "missing"), "tests", SyncOptions{})
+	assert.Error(t, err)
+	_, err = client.SyncRecordings(dir, "tests", SyncOptions{Parallelism: -1})
+	assert.Error(t, err)
+}
+
+func TestManagementClient_SyncRecordings_FailedReplace(t *testing.T) {
+	fake := snmpsimtest.NewUnstartedServer()
+	fake.AddRecording("tests/public.snmprec", []byte("1.3.6.1.2.1.1.1.0|4|old\n"))
+	var failRestore bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		//the server rejects the new recording, and the restored recording as well if failRestore is set
+		if r.Method == "POST" {
+			body, _ := ioutil.ReadAll(r.Body)
+			if strings.Contains(string(body), "new") || failRestore {
+				w.WriteHeader(500)
+				return
+			}
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+		fake.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	client, err := NewManagementClient(server.URL)
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+	dir := writeLocalRecordings(t, map[string]string{"public.snmprec": "1.3.6.1.2.1.1.1.0|4|new\n"})
+
+	report, err := client.SyncRecordings(dir, "tests", SyncOptions{})
+	var syncErr *SyncError
+	if assert.True(t, errors.As(err, &syncErr), "unexpected error %v", err) && assert.Len(t, report.Changes, 1) {
+		assert.Equal(t, SyncActionReplace, report.Changes[0].Action)
+		assert.True(t, errors.Is(report.Changes[0].Err, ErrServer))
+		assert.Contains(t, report.Changes[0].Err.Error(), "previous recording was restored")
+	}
+	content, ok := fake.Recording("tests/public.snmprec")
+	if assert.True(t, ok, "recording was deleted by a failed replace") {
+		assert.Equal(t, "1.3.6.1.2.1.1.1.0|4|old\n", string(content))
+	}
+
+	//if the previous recording can not be restored either, the error says that it was deleted
+	failRestore = true
+	report, err = client.SyncRecordings(dir, "tests", SyncOptions{})
+	if assert.Error(t, err) && assert.Len(t, report.Changes, 1) {
+		assert.Contains(t, report.Changes[0].Err.Error(), "recording was deleted and could not be restored")
+	}
+	_, ok = fake.Recording("tests/public.snmprec")
+	assert.False(t, ok)
+}
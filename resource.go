@@ -0,0 +1,215 @@
+package snmpsimclient
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"strconv"
+)
+
+//resource describes a collection of objects of the api. All paths, expected status codes and error messages of the
+//collection are derived from it, so every resource behaves the same way.
+type resource struct {
+	//name is the singular name of an object, it is used in link paths and error messages
+	name string
+	//plural is the name of the collection, it is used in the path of the collection and in error messages
+	plural string
+	//api is the path of the api the collection belongs to
+	api string
+}
+
+//resources of the management api
+var (
+	labResource      = resource{name: "lab", plural: "labs", api: mgmtEndpointPath}
+	engineResource   = resource{name: "engine", plural: "engines", api: mgmtEndpointPath}
+	agentResource    = resource{name: "agent", plural: "agents", api: mgmtEndpointPath}
+	endpointResource = resource{name: "endpoint", plural: "endpoints", api: mgmtEndpointPath}
+	userResource     = resource{name: "user", plural: "users", api: mgmtEndpointPath}
+	selectorResource = resource{name: "selector", plural: "selectors", api: mgmtEndpointPath}
+	tagResource      = resource{name: "tag", plural: "tags", api: mgmtEndpointPath}
+)
+
+//resources of the metrics api
+var (
+	processResource = resource{name: "process", plural: "processes", api: metricsEndpointPath}
+)
+
+//recordingsPath is the path of the record files of the management api
+const recordingsPath = mgmtEndpointPath + "recordings"
+
+//textHeader returns the header of requests which send or receive record files
+func textHeader() map[string]string {
+	return map[string]string{"Content-Type": "text/plain"}
+}
+
+//collectionPath returns the path of the collection
+func (r resource) collectionPath() string {
+	return r.api + r.plural
+}
+
+//objectPath returns the path of the object with the given id
+func (r resource) objectPath(id int) string {
+	return r.collectionPath() + "/" + strconv.Itoa(id)
+}
+
+//linkPath returns the path of the link between the object with the given id and an object of the child resource
+func (r resource) linkPath(id int, child resource, childId int) string {
+	return r.objectPath(id) + "/" + child.name + "/" + strconv.Itoa(childId)
+}
+
+//apiCall describes a single request to the api and the status code which is expected on success
+type apiCall struct {
+	method string
+	path   string
+	//description is used in the error message if the request fails, e.g. "get lab"
+	description    string
+	header         map[string]string
+	query          map[string]string
+	expectedStatus int
+	//body is marshalled to json, a string is sent as it is
+	body interface{}
+	//result is decoded from the json response body, a *string receives the raw body
+	result interface{}
+}
+
+//do sends the call and decodes the response, every request of the clients goes through it
+func (c *client) do(ctx context.Context, call apiCall) error {
+	if !c.isValid() {
+		return &NotValidError{}
+	}
+
+	var body string
+	switch b := call.body.(type) {
+	case nil:
+	case string:
+		body = b
+	default:
+		jsonBody, err := json.Marshal(b)
+		if err != nil {
+			return errors.Wrap(err, "error during marshal")
+		}
+		body = string(jsonBody)
+	}
+
+	response, err := c.request(ctx, call.method, call.path, body, call.header, call.query)
+	if err != nil {
+		return errors.Wrap(err, "error during "+call.description+" request")
+	}
+	if response.StatusCode() != call.expectedStatus {
+		return getHttpError(response)
+	}
+
+	switch result := call.result.(type) {
+	case nil:
+	case *string:
+		*result = string(response.Body())
+	default:
+		err = json.Unmarshal(response.Body(), result)
+		if err != nil {
+			return errors.Wrap(err, "error during unmarshalling http response")
+		}
+	}
+	return nil
+}
+
+//list gets all objects of the resource matching the filters
+func (c *client) list(ctx context.Context, r resource, filters map[string]string, result interface{}) error {
+	return c.do(ctx, apiCall{
+		method:         "GET",
+		path:           r.collectionPath(),
+		description:    "get " + r.plural,
+		query:          filters,
+		expectedStatus: 200,
+		result:         result,
+	})
+}
+
+//get gets the object of the resource with the given id
+func (c *client) get(ctx context.Context, r resource, id int, result interface{}) error {
+	return c.do(ctx, apiCall{
+		method:         "GET",
+		path:           r.objectPath(id),
+		description:    "get " + r.name,
+		expectedStatus: 200,
+		result:         result,
+	})
+}
+
+//create creates a new object of the resource, if a tag id is given the object is created with the tag
+func (c *client) create(ctx context.Context, r resource, tagId *int, body, result interface{}) error {
+	path := r.collectionPath()
+	if tagId != nil {
+		path = tagResource.objectPath(*tagId) + "/" + r.name
+	}
+	return c.do(ctx, apiCall{
+		method:         "POST",
+		path:           path,
+		description:    "create " + r.name,
+		expectedStatus: 201,
+		body:           body,
+		result:         result,
+	})
+}
+
+//update changes the object of the resource with the given id
+func (c *client) update(ctx context.Context, r resource, id int, body, result interface{}) error {
+	return c.do(ctx, apiCall{
+		method:         "PUT",
+		path:           r.objectPath(id),
+		description:    "update " + r.name,
+		expectedStatus: 200,
+		body:           body,
+		result:         result,
+	})
+}
+
+//delete deletes the object of the resource with the given id
+func (c *client) delete(ctx context.Context, r resource, id int) error {
+	return c.do(ctx, apiCall{
+		method:         "DELETE",
+		path:           r.objectPath(id),
+		description:    "delete " + r.name,
+		expectedStatus: 204,
+	})
+}
+
+//link adds an object of the child resource to the object of the resource with the given id
+func (c *client) link(ctx context.Context, r resource, id int, child resource, childId int, result interface{}) error {
+	return c.do(ctx, apiCall{
+		method:         "PUT",
+		path:           r.linkPath(id, child, childId),
+		description:    "add " + child.name + " to " + r.name,
+		expectedStatus: 200,
+		result:         result,
+	})
+}
+
+//unlink removes an object of the child resource from the object of the resource with the given id
+func (c *client) unlink(ctx context.Context, r resource, id int, child resource, childId int) error {
+	return c.do(ctx, apiCall{
+		method:         "DELETE",
+		path:           r.linkPath(id, child, childId),
+		description:    "remove " + child.name + " from " + r.name,
+		expectedStatus: 204,
+	})
+}
+
+//tag adds the tag with the given tag id to the object of the resource with the given id
+func (c *client) tag(ctx context.Context, r resource, id, tagId int) error {
+	return c.do(ctx, apiCall{
+		method:         "PUT",
+		path:           tagResource.linkPath(tagId, r, id),
+		description:    "add tag to " + r.name,
+		expectedStatus: 200,
+	})
+}
+
+//untag removes the tag with the given tag id from the object of the resource with the given id
+func (c *client) untag(ctx context.Context, r resource, id, tagId int) error {
+	return c.do(ctx, apiCall{
+		method:         "DELETE",
+		path:           tagResource.linkPath(tagId, r, id),
+		description:    "remove tag from " + r.name,
+		expectedStatus: 200,
+	})
+}
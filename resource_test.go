@@ -0,0 +1,79 @@
+package snmpsimclient
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_Resource(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.Method {
+		case "POST":
+			w.WriteHeader(201)
+			_, _ = w.Write([]byte(`{"id": 3, "name": "created"}`))
+		case "DELETE":
+			w.WriteHeader(204)
+		default:
+			_, _ = w.Write([]byte(`{"id": 3, "name": "object"}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewManagementClient(server.URL)
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+
+	ctx := context.Background()
+	var selector Selector
+	assert.NoError(t, client.create(ctx, selectorResource, nil, map[string]string{"template": "t"}, &selector))
+	assert.Equal(t, 3, selector.Id)
+	tagId := 2
+	assert.NoError(t, client.create(ctx, selectorResource, &tagId, map[string]string{"template": "t"}, nil))
+	assert.NoError(t, client.get(ctx, selectorResource, 3, &selector))
+	assert.NoError(t, client.update(ctx, selectorResource, 3, map[string]string{}, nil))
+	assert.NoError(t, client.delete(ctx, selectorResource, 3))
+	assert.NoError(t, client.link(ctx, agentResource, 1, selectorResource, 3, nil))
+	assert.NoError(t, client.unlink(ctx, agentResource, 1, selectorResource, 3))
+	assert.NoError(t, client.tag(ctx, selectorResource, 3, 2))
+
+	assert.Equal(t, []string{
+		"POST /snmpsim/mgmt/v1/selectors",
+		"POST /snmpsim/mgmt/v1/tags/2/selector",
+		"GET /snmpsim/mgmt/v1/selectors/3",
+		"PUT /snmpsim/mgmt/v1/selectors/3",
+		"DELETE /snmpsim/mgmt/v1/selectors/3",
+		"PUT /snmpsim/mgmt/v1/agents/1/selector/3",
+		"DELETE /snmpsim/mgmt/v1/agents/1/selector/3",
+		"PUT /snmpsim/mgmt/v1/tags/2/selector/3",
+	}, requests)
+
+	//removing a tag is answered with 200, any other status code is returned as http error
+	err = client.untag(ctx, selectorResource, 3, 2)
+	var httpErr *HttpError
+	if assert.True(t, errors.As(err, &httpErr), "unexpected status code is not a http error") {
+		assert.Equal(t, 204, httpErr.StatusCode)
+	}
+
+	//failed requests are wrapped with the description of the call
+	server.Close()
+	_, err = client.GetRecordFiles()
+	if assert.Error(t, err) {
+		assert.True(t, strings.HasPrefix(err.Error(), "error during get record files request"), err.Error())
+	}
+
+	//all methods check whether the client is valid
+	var invalid ManagementClient
+	var notValidErr *NotValidError
+	assert.True(t, errors.As(invalid.UploadRecordFileString(String("content"), "file.snmprec"), &notValidErr))
+	var invalidMetrics MetricsClient
+	_, err = invalidMetrics.GetPackets(nil)
+	assert.True(t, errors.As(err, &notValidErr))
+}
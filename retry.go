@@ -0,0 +1,141 @@
+package snmpsimclient
+
+import (
+	"context"
+	"github.com/go-resty/resty/v2"
+	"github.com/pkg/errors"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+/*
+RetryPolicy defines how often and when a failed request is sent again.
+A request is retried if the connection failed or the api responded with one of the RetryableStatusCodes,
+as long as its http method is one of the RetryableMethods and MaxAttempts is not reached yet.
+Between two attempts the client waits for an exponentially growing, randomized backoff.
+*/
+type RetryPolicy struct {
+	//MaxAttempts is the maximum number of attempts per request, including the first one. Values below 2 disable retries.
+	MaxAttempts int
+	//InitialBackoff is the upper bound of the wait time after the first attempt, it doubles with every further attempt.
+	InitialBackoff time.Duration
+	//MaxBackoff limits the wait time between two attempts.
+	MaxBackoff time.Duration
+	//RetryableStatusCodes contains the http status codes which are treated as transient failures.
+	RetryableStatusCodes []int
+	//RetryableMethods contains the http methods which may be retried.
+	RetryableMethods []string
+}
+
+/*
+DefaultRetryPolicy returns the retry policy used by new clients. It retries idempotent GET, PUT and DELETE requests up to 3 times
+on connection errors and on the status codes 502, 503 and 504.
+*/
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:          3,
+		InitialBackoff:       200 * time.Millisecond,
+		MaxBackoff:           5 * time.Second,
+		RetryableStatusCodes: []int{502, 503, 504},
+		RetryableMethods:     []string{"GET", "PUT", "DELETE"},
+	}
+}
+
+/*
+NoRetryPolicy returns a retry policy which sends every request exactly once.
+*/
+func NoRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 1}
+}
+
+/*
+WithRetryPolicy replaces the DefaultRetryPolicy of the client with the given policy.
+*/
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(o *clientOptions) error {
+		if policy.MaxAttempts < 0 || policy.InitialBackoff < 0 || policy.MaxBackoff < 0 {
+			return errors.New("invalid retry policy")
+		}
+		o.retryPolicy = &policy
+		return nil
+	}
+}
+
+/*
+RequestError is returned when a request could not be sent or no response was received, even after retrying it.
+*/
+type RequestError struct {
+	Method   string
+	Path     string
+	Attempts int
+	Err      error
+}
+
+func (r *RequestError) Error() string {
+	return "error during http request: " + r.Method + " " + r.Path + " failed after " + strconv.Itoa(r.Attempts) + " attempt(s): " + r.Err.Error()
+}
+
+/*
+Unwrap returns the underlying error.
+*/
+func (r *RequestError) Unwrap() error {
+	return r.Err
+}
+
+//retry checks whether a request with the given method should be sent again after the given attempt
+func (p *RetryPolicy) retry(method string, attempt int, response *resty.Response, err error) bool {
+	if attempt >= p.MaxAttempts {
+		return false
+	}
+	if !p.isRetryableMethod(method) {
+		return false
+	}
+	if err != nil {
+		//replaying the same request again can not succeed
+		var missErr *CassetteMissError
+		return !errors.As(err, &missErr)
+	}
+	for _, code := range p.RetryableStatusCodes {
+		if response.StatusCode() == code {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *RetryPolicy) isRetryableMethod(method string) bool {
+	for _, m := range p.RetryableMethods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+//backoff returns a random wait time between 0 and the exponential backoff for the given attempt (full jitter)
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	backoff := p.InitialBackoff
+	for i := 1; i < attempt && (p.MaxBackoff == 0 || backoff < p.MaxBackoff); i++ {
+		backoff *= 2
+	}
+	if p.MaxBackoff != 0 && backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+	if backoff <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(backoff) + 1))
+}
+
+//wait blocks for the backoff of the given attempt or until the context is done
+func (p *RetryPolicy) wait(ctx context.Context, attempt int) error {
+	timer := time.NewTimer(p.backoff(attempt))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,184 @@
+package snmpsimclient
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"path"
+	"strings"
+)
+
+const (
+	//SelectorVariableContextEngineId is replaced by the snmp context engine id of the request
+	SelectorVariableContextEngineId = "context-engine-id"
+	//SelectorVariableContextName is replaced by the snmp context name of the request
+	SelectorVariableContextName = "context-name"
+	//SelectorVariableEndpointId is replaced by the id of the endpoint which received the request
+	SelectorVariableEndpointId = "endpoint-id"
+	//SelectorVariableSourceAddress is replaced by the source address of the request
+	SelectorVariableSourceAddress = "source-address"
+)
+
+var selectorVariables = []string{
+	SelectorVariableContextEngineId,
+	SelectorVariableContextName,
+	SelectorVariableEndpointId,
+	SelectorVariableSourceAddress,
+}
+
+/*
+ValidateSelectorTemplate checks that the given selector template is not empty and only uses the documented
+variables ${context-engine-id}, ${context-name}, ${endpoint-id} and ${source-address}.
+*/
+func ValidateSelectorTemplate(template string) error {
+	_, err := parseSelectorTemplate(template)
+	return err
+}
+
+//selectorPart is a part of a selector template, either literal text or the name of a variable
+type selectorPart struct {
+	text     string
+	variable bool
+}
+
+//parseSelectorTemplate validates the template and splits it into literal text and variables in order of appearance
+func parseSelectorTemplate(template string) ([]selectorPart, error) {
+	if strings.TrimSpace(template) == "" {
+		return nil, errors.New("selector template is empty")
+	}
+	var parts []selectorPart
+	rest := template
+	for {
+		start := strings.Index(rest, "${")
+		if start == -1 {
+			if rest != "" {
+				parts = append(parts, selectorPart{text: rest})
+			}
+			return parts, nil
+		}
+		end := strings.Index(rest[start:], "}")
+		if end == -1 {
+			return nil, errors.New("unterminated variable in selector template '" + template + "'")
+		}
+		name := rest[start+2 : start+end]
+		if !isSelectorVariable(name) {
+			return nil, errors.New("unknown variable '${" + name + "}' in selector template '" + template + "'")
+		}
+		if start != 0 {
+			parts = append(parts, selectorPart{text: rest[:start]})
+		}
+		parts = append(parts, selectorPart{text: name, variable: true})
+		rest = rest[start+end+1:]
+	}
+}
+
+func isSelectorVariable(name string) bool {
+	for _, variable := range selectorVariables {
+		if variable == name {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+SelectorRequest describes a simulated snmp request whose values are used to expand a selector template.
+*/
+type SelectorRequest struct {
+	ContextEngineId string
+	ContextName     string
+	EndpointId      string
+	SourceAddress   string
+}
+
+/*
+SelectorRoute is the result of routing a simulated request through a selector template.
+*/
+type SelectorRoute struct {
+	//Path is the expanded template, relative to the data dir of the agent.
+	Path string
+	//RecordFile is the path of the matching record file relative to the data root, it is empty if no record file matches.
+	RecordFile string
+	//Found reports whether a matching record file exists.
+	Found bool
+}
+
+/*
+ExpandSelectorTemplate replaces the variables of the given selector template with the values of the simulated request
+and returns the resulting path relative to the data dir of the agent. It fails if the template is invalid or the
+expanded path leaves the data dir.
+*/
+func ExpandSelectorTemplate(template string, request SelectorRequest) (string, error) {
+	parts, err := parseSelectorTemplate(template)
+	if err != nil {
+		return "", err
+	}
+
+	values := map[string]string{
+		SelectorVariableContextEngineId: request.ContextEngineId,
+		SelectorVariableContextName:     request.ContextName,
+		SelectorVariableEndpointId:      request.EndpointId,
+		SelectorVariableSourceAddress:   request.SourceAddress,
+	}
+	//the template is expanded in a single pass, so values are inserted literally even if they contain variables
+	var b strings.Builder
+	for _, part := range parts {
+		if part.variable {
+			b.WriteString(values[part.text])
+		} else {
+			b.WriteString(part.text)
+		}
+	}
+	expanded := b.String()
+
+	expanded = path.Clean(expanded)
+	if path.IsAbs(expanded) || expanded == "." || expanded == ".." || strings.HasPrefix(expanded, "../") {
+		return "", errors.New("selector template '" + template + "' expands to '" + expanded + "' which is outside of the data dir")
+	}
+	return expanded, nil
+}
+
+/*
+ResolveSelector expands the selector template for the simulated request and looks up the resulting record file
+inside of the data dir of the given agent. A record file matches if its path equals the expanded path, with or
+without a file extension.
+*/
+func (c *ManagementClient) ResolveSelector(agent Agent, template string, request SelectorRequest) (SelectorRoute, error) {
+	return c.ResolveSelectorContext(context.Background(), agent, template, request)
+}
+
+/*
+ResolveSelectorContext is like ResolveSelector but carries the given context through to the http request.
+*/
+func (c *ManagementClient) ResolveSelectorContext(ctx context.Context, agent Agent, template string, request SelectorRequest) (SelectorRoute, error) {
+	if !c.isValid() {
+		return SelectorRoute{}, &NotValidError{}
+	}
+
+	expanded, err := ExpandSelectorTemplate(template, request)
+	if err != nil {
+		return SelectorRoute{}, err
+	}
+
+	recordings, err := c.GetRecordFilesContext(ctx)
+	if err != nil {
+		return SelectorRoute{}, errors.Wrap(err, "error while getting record files")
+	}
+	return resolveSelectorRoute(expanded, agent.DataDir, recordings), nil
+}
+
+//resolveSelectorRoute looks up the record file for an expanded selector path inside of the given data dir
+func resolveSelectorRoute(expanded, dataDir string, recordings Recordings) SelectorRoute {
+	route := SelectorRoute{Path: expanded}
+	target := path.Join(strings.Trim(dataDir, "/"), expanded)
+	for _, recording := range recordings {
+		recordPath := strings.TrimLeft(recording.Path, "/")
+		if recordPath == target {
+			return SelectorRoute{Path: expanded, RecordFile: recording.Path, Found: true}
+		}
+		if !route.Found && strings.TrimSuffix(recordPath, path.Ext(recordPath)) == target {
+			route.RecordFile = recording.Path
+			route.Found = true
+		}
+	}
+	return route
+}
@@ -0,0 +1,171 @@
+package snmpsimclient
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestValidateSelectorTemplate(t *testing.T) {
+	assert.NoError(t, ValidateSelectorTemplate("${context-engine-id}/${context-name}.snmprec"))
+	assert.NoError(t, ValidateSelectorTemplate("${endpoint-id}/${source-address}/public.snmprec"))
+	assert.NoError(t, ValidateSelectorTemplate("static/public.snmprec"))
+
+	assert.Error(t, ValidateSelectorTemplate(""), "no error returned for an empty template")
+	assert.Error(t, ValidateSelectorTemplate("${transport-id}/public.snmprec"), "no error returned for an unknown variable")
+	assert.Error(t, ValidateSelectorTemplate("${context-name/public.snmprec"), "no error returned for an unterminated variable")
+}
+
+func TestExpandSelectorTemplate(t *testing.T) {
+	request := SelectorRequest{
+		ContextEngineId: "0102030405070809",
+		ContextName:     "public",
+		EndpointId:      "udpv4:127.0.0.1:1161",
+		SourceAddress:   "10.0.0.1",
+	}
+
+	expanded, err := ExpandSelectorTemplate("${context-engine-id}/${context-name}.snmprec", request)
+	if assert.NoError(t, err, "error while expanding template") {
+		assert.Equal(t, "0102030405070809/public.snmprec", expanded)
+	}
+	expanded, err = ExpandSelectorTemplate("./${source-address}/${context-name}", request)
+	if assert.NoError(t, err, "error while expanding template") {
+		assert.Equal(t, "10.0.0.1/public", expanded)
+	}
+
+	_, err = ExpandSelectorTemplate("${community}.snmprec", request)
+	assert.Error(t, err, "no error returned for an invalid template")
+	_, err = ExpandSelectorTemplate("../${context-name}.snmprec", request)
+	assert.Error(t, err, "no error returned for a path outside of the data dir")
+
+	//values of the request are inserted literally, even if they look like variables
+	for i := 0; i < 20; i++ {
+		expanded, err = ExpandSelectorTemplate("${context-name}/${source-address}", SelectorRequest{
+			ContextName:   "${source-address}",
+			SourceAddress: "${context-name}",
+		})
+		if assert.NoError(t, err, "error while expanding template") {
+			assert.Equal(t, "${source-address}/${context-name}", expanded)
+		}
+	}
+
+	request.ContextName = "../../etc/passwd"
+	_, err = ExpandSelectorTemplate("${context-name}", request)
+	assert.Error(t, err, "no error returned for a request value leaving the data dir")
+}
+
+func TestManagementClient_ResolveSelector(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"id": 1, "path": "agent/public.snmprec"}, {"id": 2, "path": "agent/private/private.snmprec"}, {"id": 3, "path": "other/unused.snmprec"}]`))
+	}))
+	defer server.Close()
+
+	client, err := NewManagementClient(server.URL)
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+	agent := Agent{Id: 1, DataDir: "/agent/"}
+
+	route, err := client.ResolveSelector(agent, "${context-name}.snmprec", SelectorRequest{ContextName: "public"})
+	if assert.NoError(t, err, "error while resolving selector") {
+		assert.True(t, route.Found)
+		assert.Equal(t, "public.snmprec", route.Path)
+		assert.Equal(t, "agent/public.snmprec", route.RecordFile)
+	}
+
+	route, err = client.ResolveSelector(agent, "${context-name}/${context-name}", SelectorRequest{ContextName: "private"})
+	if assert.NoError(t, err, "error while resolving selector") {
+		assert.True(t, route.Found)
+		assert.Equal(t, "agent/private/private.snmprec", route.RecordFile)
+	}
+
+	route, err = client.ResolveSelector(agent, "${context-name}.snmprec", SelectorRequest{ContextName: "unused"})
+	if assert.NoError(t, err, "error while resolving selector") {
+		assert.False(t, route.Found, "record file outside of the data dir was matched")
+		assert.Empty(t, route.RecordFile)
+	}
+}
+
+func TestManagementClient_Selectors(t *testing.T) {
+	var mtx sync.Mutex
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/"+mgmtEndpointPath)
+		mtx.Lock()
+		requests = append(requests, r.Method+" "+path)
+		mtx.Unlock()
+		switch {
+		case r.Method == "POST":
+			var selector Selector
+			_ = json.NewDecoder(r.Body).Decode(&selector)
+			selector.Id = 3
+			w.WriteHeader(201)
+			_ = json.NewEncoder(w).Encode(selector)
+		case r.Method == "DELETE" && strings.HasPrefix(path, "tags/"):
+			_, _ = w.Write([]byte(`{}`))
+		case r.Method == "DELETE":
+			w.WriteHeader(204)
+		case path == "selectors":
+			_, _ = w.Write([]byte(`[{"id": 3, "template": "${context-name}.snmprec"}]`))
+		case path == "selectors/3":
+			_, _ = w.Write([]byte(`{"id": 3, "template": "${context-name}.snmprec"}`))
+		case strings.HasPrefix(path, "agents/1"):
+			_, _ = w.Write([]byte(`{"id": 1, "selectors": [{"id": 3}]}`))
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewManagementClient(server.URL)
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+
+	_, err = client.CreateSelector("invalid", "${community}.snmprec")
+	assert.Error(t, err, "no error returned for an invalid template")
+
+	selector, err := client.CreateSelector("by context", "${context-name}.snmprec")
+	if assert.NoError(t, err, "error while creating selector") {
+		assert.Equal(t, 3, selector.Id)
+		assert.Equal(t, "by context", selector.Comment)
+		assert.Equal(t, "${context-name}.snmprec", selector.Template)
+	}
+	_, err = client.CreateSelectorWithTag("by context", "${context-name}.snmprec", 2)
+	assert.NoError(t, err, "error while creating selector with tag")
+
+	selectors, err := client.GetSelectors()
+	if assert.NoError(t, err, "error while getting selectors") && assert.Len(t, selectors, 1) {
+		assert.Equal(t, 3, selectors[0].Id)
+	}
+	_, err = client.GetSelector(3)
+	assert.NoError(t, err, "error while getting selector")
+
+	agent, err := client.AddSelectorToAgent(1, 3)
+	if assert.NoError(t, err, "error while adding selector to agent") {
+		assert.Len(t, agent.Selectors, 1)
+	}
+	_, err = client.RemoveSelectorFromAgent(1, 3)
+	assert.NoError(t, err, "error while removing selector from agent")
+
+	assert.NoError(t, client.AddTagToSelector(3, 2), "error while adding tag to selector")
+	assert.NoError(t, client.RemoveTagFromSelector(3, 2), "error while removing tag from selector")
+	assert.NoError(t, client.DeleteSelector(3), "error while deleting selector")
+
+	assert.Equal(t, []string{
+		"POST selectors",
+		"POST tags/2/selector",
+		"GET selectors",
+		"GET selectors/3",
+		"PUT agents/1/selector/3",
+		"DELETE agents/1/selector/3",
+		"GET agents/1",
+		"PUT tags/2/selector/3",
+		"DELETE tags/2/selector/3",
+		"DELETE selectors/3",
+	}, requests)
+}
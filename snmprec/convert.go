@@ -0,0 +1,316 @@
+package snmprec
+
+import (
+	"bufio"
+	"encoding/hex"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+//maxLineSize limits the size of a single line of a converted walk
+const maxLineSize = 16 << 20
+
+//walkLine matches the first line of a value in the output of snmpwalk -On
+var walkLine = regexp.MustCompile(`^\.?([0-9]+(?:\.[0-9]+)+) = (.*)$`)
+
+//sapwalkTags maps the type names of sapwalk files to their tags
+var sapwalkTags = map[string]Tag{
+	"Integer":     Integer32,
+	"OctetString": OctetString,
+	"Null":        Null,
+	"ObjectID":    ObjectIdentifier,
+	"IpAddress":   IpAddress,
+	"Counter":     Counter32,
+	"Gauge":       Gauge32,
+	"TimeTicks":   TimeTicks,
+	"Counter64":   Counter64,
+}
+
+//walkEntry is a value of a walk which may span multiple lines
+type walkEntry struct {
+	line int
+	oid  string
+	text string
+}
+
+/*
+ConvertSnmpwalk converts the output of net-snmp's "snmpwalk -On" into a snmprec file. Values spanning multiple lines,
+enumerations like "up(1)", hex strings and the exceptions "No Such Object", "No Such Instance" and "No more variables"
+are supported. The records are sorted by OID and the Number of every line is the line of the value in the walk, so
+issues reported by Lint point into the walk. A *ParseError is returned for values which can not be converted.
+*/
+func ConvertSnmpwalk(r io.Reader) (*File, error) {
+	var entries []walkEntry
+	err := scanLines(r, func(number int, text string) error {
+		if n := len(entries); n != 0 && openString(entries[n-1].text) {
+			entries[n-1].text += "\n" + text
+			return nil
+		}
+		if m := walkLine.FindStringSubmatch(text); m != nil {
+			entries = append(entries, walkEntry{line: number, oid: m[1], text: m[2]})
+			return nil
+		}
+		switch trimmed := strings.TrimSpace(text); {
+		case trimmed == "" || trimmed == "End of MIB":
+			return nil
+		case len(entries) == 0:
+			return &ParseError{Line: number, Text: text, Msg: "expected OID = TYPE: VALUE"}
+		}
+		//unquoted strings and long hex strings are continued on the next lines
+		entries[len(entries)-1].text += "\n" + text
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]Line, 0, len(entries))
+	for _, entry := range entries {
+		record, msg := convertWalkValue(entry.text)
+		if msg == "" {
+			record.OID = entry.oid
+			msg = checkValue(record)
+		}
+		if msg != "" {
+			return nil, &ParseError{Line: entry.line, Text: entry.oid + " = " + entry.text, Msg: msg}
+		}
+		records = append(records, Line{Number: entry.line, Record: &record, Ending: "\n"})
+	}
+	return sortedFile(records), nil
+}
+
+/*
+ConvertSapwalk converts a sapwalk file with lines of the form "OID, TYPE, VALUE" into a snmprec file. Octet strings
+are read as text unless they start with "0x", which marks hex values. They are written as text if they are printable
+and hex encoded otherwise. The records are sorted by OID and the Number of
+every line is the line of the record in the sapwalk file. A *ParseError is returned for lines which can not be converted.
+*/
+func ConvertSapwalk(r io.Reader) (*File, error) {
+	var records []Line
+	err := scanLines(r, func(number int, text string) error {
+		if trimmed := strings.TrimSpace(text); trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			return nil
+		}
+		record, msg := convertSapwalkLine(text)
+		if msg == "" {
+			msg = checkValue(record)
+		}
+		if msg != "" {
+			return &ParseError{Line: number, Text: text, Msg: msg}
+		}
+		records = append(records, Line{Number: number, Record: &record, Ending: "\n"})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sortedFile(records), nil
+}
+
+//scanLines calls the function for every line of the reader without line ending, line numbers start at 1
+func scanLines(r io.Reader, f func(number int, text string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	for number := 1; scanner.Scan(); number++ {
+		if err := f(number, strings.TrimSuffix(scanner.Text(), "\r")); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+//openString checks whether the text of a walk value is a quoted string which is continued on the next line
+func openString(text string) bool {
+	value := strings.TrimPrefix(text, "STRING: ")
+	if value == text || !strings.HasPrefix(value, `"`) {
+		return false
+	}
+	_, closed := unquote(value)
+	return !closed
+}
+
+//unquote removes the quotes of a string printed by net-snmp, which escapes quotes and backslashes. closed is false if
+//the closing quote is missing, text after the closing quote is ignored.
+func unquote(value string) (s string, closed bool) {
+	var b strings.Builder
+	for i := 1; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			if i+1 < len(value) {
+				i++
+			}
+		case '"':
+			return b.String(), true
+		}
+		b.WriteByte(value[i])
+	}
+	return b.String(), false
+}
+
+//convertWalkValue converts the text of a walk value into a record without OID, the returned message describes the
+//problem if it can not be converted
+func convertWalkValue(text string) (Record, string) {
+	switch {
+	case text == `""`:
+		return Record{Tag: OctetString}, ""
+	case text == "NULL":
+		return Record{Tag: Null}, ""
+	case strings.HasPrefix(text, "No Such Object"):
+		return Record{Tag: NoSuchObject}, ""
+	case strings.HasPrefix(text, "No Such Instance"):
+		return Record{Tag: NoSuchInstance}, ""
+	case strings.HasPrefix(text, "No more variables left"):
+		return Record{Tag: EndOfMibView}, ""
+	}
+	//values which do not match their MIB definition are printed as "Wrong Type (should be INTEGER): Gauge32: 5"
+	if strings.HasPrefix(text, "Wrong Type") {
+		if i := strings.Index(text, "): "); i >= 0 {
+			text = text[i+3:]
+		}
+	}
+
+	i := strings.Index(text, ":")
+	if i < 0 {
+		return Record{}, "expected TYPE: VALUE"
+	}
+	typ, value := text[:i], strings.TrimPrefix(text[i+1:], " ")
+	switch typ {
+	case "STRING":
+		if strings.HasPrefix(value, `"`) {
+			s, closed := unquote(value)
+			if !closed {
+				return Record{}, "unterminated string"
+			}
+			value = s
+		}
+		return NewOctetString("", []byte(value)), ""
+	case "Hex-STRING", "BITS":
+		//BITS are followed by the names of the set bits, e.g. "BITS: 80 00 first(0)"
+		b, ok := decodeHex(strings.Fields(value), typ == "BITS")
+		if !ok {
+			return Record{}, "invalid " + typ + " value"
+		}
+		return Record{Tag: OctetString, Hex: true, Value: hex.EncodeToString(b)}, ""
+	case "INTEGER":
+		//enumerations are printed as "up(1)"
+		value = firstField(value)
+		if open := strings.LastIndexByte(value, '('); open >= 0 && strings.HasSuffix(value, ")") {
+			value = value[open+1 : len(value)-1]
+		}
+		return Record{Tag: Integer32, Value: value}, ""
+	case "Counter32":
+		return Record{Tag: Counter32, Value: firstField(value)}, ""
+	case "Gauge32", "Unsigned32", "UInteger32":
+		return Record{Tag: Gauge32, Value: firstField(value)}, ""
+	case "Counter64":
+		return Record{Tag: Counter64, Value: firstField(value)}, ""
+	case "Timeticks":
+		//time ticks are printed as "(12345) 0:02:03.45" unless snmpwalk was called with -Ot
+		if strings.HasPrefix(value, "(") {
+			if end := strings.IndexByte(value, ')'); end >= 0 {
+				return Record{Tag: TimeTicks, Value: value[1:end]}, ""
+			}
+		}
+		return Record{Tag: TimeTicks, Value: firstField(value)}, ""
+	case "OID":
+		value = strings.TrimPrefix(strings.TrimSpace(value), ".")
+		if _, ok := parseOID(value); !ok {
+			return Record{}, "OID value '" + value + "' is not numeric, the walk has to be created with snmpwalk -On"
+		}
+		return Record{Tag: ObjectIdentifier, Value: value}, ""
+	case "IpAddress":
+		return Record{Tag: IpAddress, Value: strings.TrimSpace(value)}, ""
+	case "Network Address":
+		b, ok := decodeHex(strings.Split(strings.TrimSpace(value), ":"), false)
+		if !ok || len(b) != 4 {
+			return Record{}, "invalid Network Address value"
+		}
+		return Record{Tag: IpAddress, Value: ipAddress(b)}, ""
+	case "Opaque":
+		b, ok := decodeHex(strings.Fields(value), false)
+		if !ok {
+			return Record{}, "unsupported Opaque value '" + value + "'"
+		}
+		return Record{Tag: Opaque, Hex: true, Value: hex.EncodeToString(b)}, ""
+	}
+	return Record{}, "unsupported type '" + typ + "'"
+}
+
+//convertSapwalkLine converts a line of a sapwalk file into a record, the returned message describes the problem if it
+//can not be converted
+func convertSapwalkLine(text string) (Record, string) {
+	fields := strings.SplitN(text, ",", 3)
+	if len(fields) != 3 {
+		return Record{}, "expected OID, TYPE, VALUE"
+	}
+	oid := strings.TrimPrefix(strings.TrimSpace(fields[0]), ".")
+	typ, value := strings.TrimSpace(fields[1]), strings.TrimSpace(fields[2])
+	if _, ok := parseOID(oid); !ok {
+		return Record{}, "invalid OID"
+	}
+	tag, ok := sapwalkTags[typ]
+	if !ok {
+		return Record{}, "unsupported type '" + typ + "'"
+	}
+
+	switch tag {
+	case OctetString:
+		//like snmpsim, octet strings are text unless they are hex encoded with a 0x prefix, e.g. "0x0A 0B" or "0x0A0B"
+		if !strings.HasPrefix(value, "0x") {
+			return NewOctetString(oid, []byte(value)), ""
+		}
+		b, err := hex.DecodeString(strings.Join(strings.Fields(value[2:]), ""))
+		if err != nil {
+			return Record{}, "invalid hex OctetString value"
+		}
+		return NewOctetString(oid, b), ""
+	case IpAddress:
+		//ip addresses are written as hex octets, e.g. "C0.A8.01.01"
+		b, ok := decodeHex(strings.Split(value, "."), false)
+		if !ok || len(b) != 4 {
+			return Record{}, "invalid IpAddress value"
+		}
+		value = ipAddress(b)
+	case ObjectIdentifier:
+		value = strings.TrimPrefix(value, ".")
+	case Null:
+		value = ""
+	}
+	return Record{OID: oid, Tag: tag, Value: value}, ""
+}
+
+//decodeHex decodes hex bytes like "0A". If stopAtText is set, decoding stops at the first field which is no hex byte.
+func decodeHex(fields []string, stopAtText bool) ([]byte, bool) {
+	b := make([]byte, 0, len(fields))
+	for _, field := range fields {
+		if field == "" {
+			continue
+		}
+		value, err := strconv.ParseUint(field, 16, 8)
+		if err != nil || len(field) > 2 {
+			if stopAtText {
+				break
+			}
+			return nil, false
+		}
+		b = append(b, byte(value))
+	}
+	return b, true
+}
+
+func ipAddress(b []byte) string {
+	parts := make([]string, 0, len(b))
+	for _, octet := range b {
+		parts = append(parts, strconv.Itoa(int(octet)))
+	}
+	return strings.Join(parts, ".")
+}
+
+func firstField(value string) string {
+	if fields := strings.Fields(value); len(fields) != 0 {
+		return fields[0]
+	}
+	return ""
+}
@@ -0,0 +1,123 @@
+package snmprec
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestConvertSnmpwalk(t *testing.T) {
+	walk := `.1.3.6.1.2.1.1.1.0 = STRING: "Linux router 5.4.0
+#1 SMP \"generic\""
+.1.3.6.1.2.1.1.2.0 = OID: .1.3.6.1.4.1.8072.3.2.10
+.1.3.6.1.2.1.1.3.0 = Timeticks: (8843700) 1 day, 0:33:57.00
+.1.3.6.1.2.1.1.5.0 = STRING: router
+.1.3.6.1.2.1.2.2.1.8.10 = INTEGER: down(2)
+.1.3.6.1.2.1.2.2.1.8.2 = INTEGER: up(1)
+.1.3.6.1.2.1.2.2.1.5.2 = Gauge32: 1000000000
+.1.3.6.1.2.1.2.2.1.6.2 = Hex-STRING: 00 50 56 AB
+CD EF
+.1.3.6.1.2.1.2.2.1.10.2 = Counter32: 1234
+.1.3.6.1.2.1.2.2.1.9.2 = Timeticks: 42
+.1.3.6.1.2.1.2.2.1.2.2 = ""
+.1.3.6.1.2.1.4.20.1.1.127.0.0.1 = IpAddress: 127.0.0.1
+.1.3.6.1.2.1.31.1.1.1.6.2 = Counter64: 18446744073709551615
+.1.3.6.1.2.1.25.1.7.0 = Wrong Type (should be INTEGER): Gauge32: 5
+.1.3.6.1.2.1.99.1.0 = No Such Object available on this agent at this OID
+.1.3.6.1.2.1.99.2.0 = No more variables left in this MIB View (It is past the end of the MIB tree)
+.1.3.6.1.2.1.99.3.0 = BITS: 80 01 first(0) last(15)
+End of MIB
+`
+	file, err := ConvertSnmpwalk(strings.NewReader(walk))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Empty(t, Lint(file))
+	assert.Equal(t, `1.3.6.1.2.1.1.1.0|4x|4c696e757820726f7574657220352e342e300a233120534d50202267656e6572696322
+1.3.6.1.2.1.1.2.0|6|1.3.6.1.4.1.8072.3.2.10
+1.3.6.1.2.1.1.3.0|67|8843700
+1.3.6.1.2.1.1.5.0|4|router
+1.3.6.1.2.1.2.2.1.2.2|4|
+1.3.6.1.2.1.2.2.1.5.2|66|1000000000
+1.3.6.1.2.1.2.2.1.6.2|4x|005056abcdef
+1.3.6.1.2.1.2.2.1.8.2|2|1
+1.3.6.1.2.1.2.2.1.8.10|2|2
+1.3.6.1.2.1.2.2.1.9.2|67|42
+1.3.6.1.2.1.2.2.1.10.2|65|1234
+1.3.6.1.2.1.4.20.1.1.127.0.0.1|64|127.0.0.1
+1.3.6.1.2.1.25.1.7.0|66|5
+1.3.6.1.2.1.31.1.1.1.6.2|70|18446744073709551615
+1.3.6.1.2.1.99.1.0|128|
+1.3.6.1.2.1.99.2.0|130|
+1.3.6.1.2.1.99.3.0|4x|8001
+`, string(file.Bytes()))
+	assert.Equal(t, 1, file.Lines[0].Number)
+	assert.Equal(t, 6, file.Lines[8].Number)
+
+	for walk, line := range map[string]int{
+		"garbage": 1,
+		".1.3.6.1.2.1.1.1.0 = INTEGER: 1\n.1.3.6.1.2.1.1.2.0 = OID: SNMPv2-SMI::enterprises.8072": 2,
+		".1.3.6.1.2.1.1.1.0 = INTEGER: many":                                                      1,
+		".1.3.6.1.2.1.1.1.0 = Counter32: -1":                                                      1,
+		".1.3.6.1.2.1.1.1.0 = STRING: \"unterminated":                                             1,
+		".1.3.6.1.2.1.1.1.0 = Float: 1.5":                                                         1,
+	} {
+		_, err := ConvertSnmpwalk(strings.NewReader(walk))
+		if parseErr, ok := err.(*ParseError); assert.True(t, ok, "unexpected error %v for %q", err, walk) {
+			assert.Equal(t, line, parseErr.Line)
+		}
+	}
+}
+
+func TestConvertSapwalk(t *testing.T) {
+	sapwalk := `# captured by the field
+1.3.6.1.2.1.1.5.0, OctetString, router
+1.3.6.1.2.1.1.4.0, OctetString, ab cd
+1.3.6.1.2.1.1.1.0, OctetString, 0x4C 69 6E 75 78 0A
+1.3.6.1.2.1.1.6.0, OctetString, 0x6C6162
+1.3.6.1.2.1.1.2.0, ObjectID, .1.3.6.1.4.1.8072.3.2.10
+1.3.6.1.2.1.1.3.0, TimeTicks, 8843700
+
+1.3.6.1.2.1.2.2.1.6.2, OctetString,
+1.3.6.1.2.1.2.2.1.7.2, Integer, -1
+1.3.6.1.2.1.2.2.1.10.2, Counter, 1234
+1.3.6.1.2.1.2.2.1.5.2, Gauge, 100
+1.3.6.1.2.1.4.20.1.1.127.0.0.1, IpAddress, 7F.00.00.01
+1.3.6.1.2.1.31.1.1.1.6.2, Counter64, 18446744073709551615
+1.3.6.1.2.1.99.1.0, Null,
+`
+	file, err := ConvertSapwalk(strings.NewReader(sapwalk))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Empty(t, Lint(file))
+	assert.Equal(t, `1.3.6.1.2.1.1.1.0|4x|4c696e75780a
+1.3.6.1.2.1.1.2.0|6|1.3.6.1.4.1.8072.3.2.10
+1.3.6.1.2.1.1.3.0|67|8843700
+1.3.6.1.2.1.1.4.0|4|ab cd
+1.3.6.1.2.1.1.5.0|4|router
+1.3.6.1.2.1.1.6.0|4|lab
+1.3.6.1.2.1.2.2.1.5.2|66|100
+1.3.6.1.2.1.2.2.1.6.2|4|
+1.3.6.1.2.1.2.2.1.7.2|2|-1
+1.3.6.1.2.1.2.2.1.10.2|65|1234
+1.3.6.1.2.1.4.20.1.1.127.0.0.1|64|127.0.0.1
+1.3.6.1.2.1.31.1.1.1.6.2|70|18446744073709551615
+1.3.6.1.2.1.99.1.0|5|
+`, string(file.Bytes()))
+
+	for _, line := range []string{
+		"1.3.6.1.2.1.1.5.0, OctetString",
+		"1.3.6.1.2.1.1.5.0, Float, 1.5",
+		"1.3.6.1.2.1.1.5.0, OctetString, 0x72 6F 7",
+		"1.3.6.1.2.1.1.5.0, OctetString, 0xrouter",
+		"1.3.6.1.2.1.1.5.0, IpAddress, 127.0.0.1.1",
+		"1.3.6.1.2.1.1.5.0, Counter, -5",
+		"iso.3.6, Integer, 1",
+	} {
+		_, err := ConvertSapwalk(strings.NewReader("\n" + line))
+		if parseErr, ok := err.(*ParseError); assert.True(t, ok, "unexpected error %v for %q", err, line) {
+			assert.Equal(t, 2, parseErr.Line)
+		}
+	}
+}
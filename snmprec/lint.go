@@ -0,0 +1,187 @@
+package snmprec
+
+import (
+	"encoding/hex"
+	"net"
+	"strconv"
+	"strings"
+)
+
+/*
+Issue is a problem of a record found by Lint.
+*/
+type Issue struct {
+	//Line is the number of the line of the record, it is 0 for records which were not parsed.
+	Line    int
+	OID     string
+	Message string
+}
+
+func (i Issue) String() string {
+	return "line " + strconv.Itoa(i.Line) + ": " + i.OID + ": " + i.Message
+}
+
+/*
+ValidationError is returned by Validate if a file has issues.
+*/
+type ValidationError struct {
+	Issues []Issue
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Issues))
+	for _, issue := range e.Issues {
+		messages = append(messages, issue.String())
+	}
+	return "invalid snmprec file: " + strings.Join(messages, "; ")
+}
+
+/*
+Validate parses the snmprec data and lints it. The returned error is a *ParseError if the data can not be parsed
+and a *ValidationError if Lint reports issues.
+*/
+func Validate(data []byte) error {
+	file, err := Parse(strings.NewReader(string(data)))
+	if err != nil {
+		return err
+	}
+	if issues := Lint(file); len(issues) != 0 {
+		return &ValidationError{Issues: issues}
+	}
+	return nil
+}
+
+/*
+Lint checks the records of the file. It reports invalid, unsorted and duplicate OIDs, unknown tags and values which
+do not match their tag. The values of records with a variation module are not checked, they are interpreted by the module.
+*/
+func Lint(f *File) []Issue {
+	var issues []Issue
+	var previous []uint64
+	var previousOID string
+	for _, line := range f.Lines {
+		r := line.Record
+		if r == nil {
+			continue
+		}
+		report := func(message string) {
+			issues = append(issues, Issue{Line: line.Number, OID: r.OID, Message: message})
+		}
+
+		arcs, ok := parseOID(r.OID)
+		if !ok {
+			report("invalid OID")
+		} else if previous != nil {
+			switch compareOIDs(previous, arcs) {
+			case 0:
+				report("duplicate OID")
+			case 1:
+				report("OID is not sorted, it has to be after " + previousOID)
+			}
+		}
+		if ok {
+			previous, previousOID = arcs, r.OID
+		}
+
+		if !r.Tag.Known() {
+			report("unknown tag " + strconv.Itoa(int(r.Tag)))
+			continue
+		}
+		if r.Variation == "" {
+			if message := checkValue(*r); message != "" {
+				report(message)
+			}
+		}
+	}
+	return issues
+}
+
+//checkValue checks whether the value of a record without variation module matches its tag
+func checkValue(r Record) string {
+	value := r.Value
+	if r.Hex {
+		decoded, err := hex.DecodeString(value)
+		if err != nil {
+			return "invalid hex value"
+		}
+		switch r.Tag {
+		case OctetString, Opaque:
+			return ""
+		case IpAddress:
+			if len(decoded) != 4 {
+				return "hex IpAddress has to be 4 bytes long"
+			}
+			return ""
+		default:
+			return "hex encoding is not supported for " + r.Tag.String()
+		}
+	}
+
+	switch r.Tag {
+	case Integer32:
+		if _, err := strconv.ParseInt(value, 10, 32); err != nil {
+			return "invalid " + r.Tag.String() + " value '" + value + "'"
+		}
+	case Counter32, Gauge32, TimeTicks:
+		if _, err := strconv.ParseUint(value, 10, 32); err != nil {
+			return "invalid " + r.Tag.String() + " value '" + value + "'"
+		}
+	case Counter64:
+		if _, err := strconv.ParseUint(value, 10, 64); err != nil {
+			return "invalid " + r.Tag.String() + " value '" + value + "'"
+		}
+	case ObjectIdentifier:
+		if _, ok := parseOID(value); !ok {
+			return "invalid " + r.Tag.String() + " value '" + value + "'"
+		}
+	case IpAddress:
+		if ip := net.ParseIP(value); ip == nil || ip.To4() == nil || strings.Contains(value, ":") {
+			return "invalid " + r.Tag.String() + " value '" + value + "'"
+		}
+	case Null, NoSuchObject, NoSuchInstance, EndOfMibView:
+		if value != "" {
+			return r.Tag.String() + " must not have a value"
+		}
+	}
+	return ""
+}
+
+//parseOID parses a numeric OID with at least two arcs, e.g. 1.3.6.1.2.1.1.1.0
+func parseOID(oid string) ([]uint64, bool) {
+	parts := strings.Split(oid, ".")
+	if len(parts) < 2 {
+		return nil, false
+	}
+	arcs := make([]uint64, 0, len(parts))
+	for _, part := range parts {
+		//leading zeros would make the OID ambiguous
+		if part == "" || (len(part) > 1 && part[0] == '0') {
+			return nil, false
+		}
+		arc, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return nil, false
+		}
+		arcs = append(arcs, arc)
+	}
+	return arcs, true
+}
+
+//compareOIDs compares two OIDs arc by arc like snmpsim does, a prefix is sorted before the longer OID
+func compareOIDs(a, b []uint64) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,311 @@
+/*
+Package snmprec reads, writes and validates snmpsim record files in the snmprec format.
+
+Every record of a snmprec file is a line of the form
+
+	OID|TAG|VALUE
+
+where TAG is the ASN.1 tag code of the value, e.g. 2 for an Integer32 or 4 for an OctetString. The tag may be followed by
+the encoding flag "x" for hex encoded values and by a colon and the name of a variation module, e.g. "67:numeric".
+Lines starting with "#" and empty lines are kept as they are, so a parsed file is written back byte by byte.
+
+	file, err := snmprec.Parse(bytes.NewReader(data))
+	for _, issue := range snmprec.Lint(file) {
+		fmt.Println(issue)
+	}
+*/
+package snmprec
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/*
+Tag is the ASN.1 tag code of a value.
+*/
+type Tag int
+
+//ASN.1 tag codes supported by snmpsim
+const (
+	Integer32        Tag = 2
+	OctetString      Tag = 4
+	Null             Tag = 5
+	ObjectIdentifier Tag = 6
+	IpAddress        Tag = 64
+	Counter32        Tag = 65
+	Gauge32          Tag = 66
+	TimeTicks        Tag = 67
+	Opaque           Tag = 68
+	Counter64        Tag = 70
+	NoSuchObject     Tag = 128
+	NoSuchInstance   Tag = 129
+	EndOfMibView     Tag = 130
+)
+
+var tagNames = map[Tag]string{
+	Integer32:        "Integer32",
+	OctetString:      "OctetString",
+	Null:             "Null",
+	ObjectIdentifier: "ObjectIdentifier",
+	IpAddress:        "IpAddress",
+	Counter32:        "Counter32",
+	Gauge32:          "Gauge32",
+	TimeTicks:        "TimeTicks",
+	Opaque:           "Opaque",
+	Counter64:        "Counter64",
+	NoSuchObject:     "NoSuchObject",
+	NoSuchInstance:   "NoSuchInstance",
+	EndOfMibView:     "EndOfMibView",
+}
+
+/*
+Known checks whether the tag is one of the tags supported by snmpsim.
+*/
+func (t Tag) Known() bool {
+	_, ok := tagNames[t]
+	return ok
+}
+
+/*
+String returns the name of the tag, unknown tags are returned as their code.
+*/
+func (t Tag) String() string {
+	if name, ok := tagNames[t]; ok {
+		return name
+	}
+	return strconv.Itoa(int(t))
+}
+
+/*
+Record is a single OID with its value.
+*/
+type Record struct {
+	OID string
+	Tag Tag
+	//Hex is set if the value is hex encoded, which is marked by an "x" after the tag.
+	Hex bool
+	//Variation is the name of the variation module which generates the value, it is empty for static values.
+	Variation string
+	Value     string
+}
+
+/*
+String returns the record as a line of a snmprec file without line ending.
+*/
+func (r Record) String() string {
+	var b strings.Builder
+	b.WriteString(r.OID)
+	b.WriteByte('|')
+	b.WriteString(strconv.Itoa(int(r.Tag)))
+	if r.Hex {
+		b.WriteByte('x')
+	}
+	if r.Variation != "" {
+		b.WriteByte(':')
+		b.WriteString(r.Variation)
+	}
+	b.WriteByte('|')
+	b.WriteString(r.Value)
+	return b.String()
+}
+
+/*
+NewOctetString creates an OctetString record of the value. The value is hex encoded if it is not printable.
+*/
+func NewOctetString(oid string, value []byte) Record {
+	for _, c := range value {
+		if c < 0x20 || c > 0x7e {
+			return Record{OID: oid, Tag: OctetString, Hex: true, Value: hex.EncodeToString(value)}
+		}
+	}
+	return Record{OID: oid, Tag: OctetString, Value: string(value)}
+}
+
+/*
+Line is a line of a snmprec file. Record is nil for comments and empty lines, whose content is kept in Text.
+*/
+type Line struct {
+	//Number is the number of the line in the parsed file starting at 1, it is 0 for added lines.
+	Number int
+	Record *Record
+	Text   string
+	//Ending is the line ending, "\n" or "\r\n". It is empty for the last line of a file without a final line ending.
+	Ending string
+}
+
+/*
+File is a parsed snmprec file.
+*/
+type File struct {
+	Lines []Line
+}
+
+/*
+NewFile creates a file of the records sorted by OID, like snmpsim expects them. Records with the same OID keep their order.
+*/
+func NewFile(records []Record) *File {
+	records = append([]Record(nil), records...)
+	lines := make([]Line, 0, len(records))
+	for i := range records {
+		lines = append(lines, Line{Record: &records[i], Ending: "\n"})
+	}
+	return sortedFile(lines)
+}
+
+//sortedFile creates a file of the record lines sorted by their OIDs
+func sortedFile(lines []Line) *File {
+	arcs := make(map[*Record][]uint64, len(lines))
+	for _, line := range lines {
+		arcs[line.Record], _ = parseOID(line.Record.OID)
+	}
+	sort.SliceStable(lines, func(i, j int) bool {
+		return compareOIDs(arcs[lines[i].Record], arcs[lines[j].Record]) < 0
+	})
+	return &File{Lines: lines}
+}
+
+/*
+ParseError is returned if a line is not a valid record.
+*/
+type ParseError struct {
+	Line int
+	Text string
+	Msg  string
+}
+
+func (e *ParseError) Error() string {
+	return "line " + strconv.Itoa(e.Line) + ": " + e.Msg + ": " + strconv.Quote(e.Text)
+}
+
+/*
+Parse reads a snmprec file. It returns a *ParseError if a line is no record, comment or empty line.
+*/
+func Parse(r io.Reader) (*File, error) {
+	reader := bufio.NewReader(r)
+	file := &File{}
+	for number := 1; ; number++ {
+		text, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if text == "" && err == io.EOF {
+			return file, nil
+		}
+
+		line := Line{Number: number}
+		switch {
+		case strings.HasSuffix(text, "\r\n"):
+			text, line.Ending = text[:len(text)-2], "\r\n"
+		case strings.HasSuffix(text, "\n"):
+			text, line.Ending = text[:len(text)-1], "\n"
+		}
+		if strings.TrimSpace(text) == "" || strings.HasPrefix(text, "#") {
+			line.Text = text
+		} else {
+			record, msg := parseRecord(text)
+			if msg != "" {
+				return nil, &ParseError{Line: number, Text: text, Msg: msg}
+			}
+			line.Record = &record
+		}
+		file.Lines = append(file.Lines, line)
+
+		if err == io.EOF {
+			return file, nil
+		}
+	}
+}
+
+/*
+ParseString is like Parse but reads the file from a string.
+*/
+func ParseString(s string) (*File, error) {
+	return Parse(strings.NewReader(s))
+}
+
+//parseRecord parses a record line, the returned message describes the syntax error if there is one
+func parseRecord(text string) (Record, string) {
+	fields := strings.SplitN(text, "|", 3)
+	if len(fields) != 3 {
+		return Record{}, "expected OID|TAG|VALUE"
+	}
+	record := Record{OID: fields[0], Value: fields[2]}
+	if record.OID == "" {
+		return Record{}, "missing OID"
+	}
+
+	tag := fields[1]
+	if i := strings.IndexByte(tag, ':'); i >= 0 {
+		tag, record.Variation = tag[:i], tag[i+1:]
+		if record.Variation == "" {
+			return Record{}, "missing variation module after ':'"
+		}
+	}
+	if strings.HasSuffix(tag, "x") {
+		tag, record.Hex = tag[:len(tag)-1], true
+	}
+	code, err := strconv.Atoi(tag)
+	//the tag has to be written the same way it is written back
+	if err != nil || code < 0 || strconv.Itoa(code) != tag {
+		return Record{}, "invalid tag '" + fields[1] + "'"
+	}
+	record.Tag = Tag(code)
+	return record, ""
+}
+
+/*
+Records returns all records of the file in the order of their lines.
+*/
+func (f *File) Records() []Record {
+	var records []Record
+	for _, line := range f.Lines {
+		if line.Record != nil {
+			records = append(records, *line.Record)
+		}
+	}
+	return records
+}
+
+/*
+Append adds a record at the end of the file. A missing line ending of the last line is added.
+*/
+func (f *File) Append(record Record) {
+	if n := len(f.Lines); n != 0 && f.Lines[n-1].Ending == "" {
+		f.Lines[n-1].Ending = "\n"
+	}
+	f.Lines = append(f.Lines, Line{Record: &record, Ending: "\n"})
+}
+
+/*
+WriteTo writes the file. A file which was parsed and not changed is written exactly as it was read.
+*/
+func (f *File) WriteTo(w io.Writer) (int64, error) {
+	var written int64
+	for _, line := range f.Lines {
+		text := line.Text
+		if line.Record != nil {
+			text = line.Record.String()
+		}
+		n, err := io.WriteString(w, text+line.Ending)
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+/*
+Bytes returns the content of the file.
+*/
+func (f *File) Bytes() []byte {
+	var buf bytes.Buffer
+	_, _ = f.WriteTo(&buf)
+	return buf.Bytes()
+}
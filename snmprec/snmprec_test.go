@@ -0,0 +1,105 @@
+package snmprec
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestParse_RoundTrip(t *testing.T) {
+	for _, data := range []string{
+		"",
+		"1.3.6.1.2.1.1.1.0|4|test\n",
+		"# comment\r\n\r\n1.3.6.1.2.1.1.1.0|4x|74657374\r\n1.3.6.1.2.1.1.3.0|67:numeric|rate=100,initial=5",
+		"1.3.6.1.2.1.1.1.0|4|value with | pipes|\n  \n",
+	} {
+		file, err := ParseString(data)
+		if assert.NoError(t, err, "error while parsing %q", data) {
+			assert.Equal(t, data, string(file.Bytes()))
+		}
+	}
+
+	files, err := filepath.Glob("../test-data/snmprecs/*/*.snmprec")
+	assert.NoError(t, err)
+	more, err := filepath.Glob("../test-data/snmprecs/*/*/*.snmprec")
+	assert.NoError(t, err)
+	files = append(files, more...)
+	assert.NotEmpty(t, files)
+	for _, path := range files {
+		data, err := ioutil.ReadFile(path)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		file, err := Parse(bytes.NewReader(data))
+		if assert.NoError(t, err, "error while parsing %s", path) {
+			assert.Equal(t, data, file.Bytes(), "%s was not written back byte by byte", path)
+			assert.Empty(t, Lint(file), "issues in %s", path)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	file, err := ParseString("# header\n1.3.6.1.2.1.1.1.0|4x:writecache|74657374\n1.3.6.1.2.1.1.3.0|67|100\n")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []Record{
+		{OID: "1.3.6.1.2.1.1.1.0", Tag: OctetString, Hex: true, Variation: "writecache", Value: "74657374"},
+		{OID: "1.3.6.1.2.1.1.3.0", Tag: TimeTicks, Value: "100"},
+	}, file.Records())
+	assert.Equal(t, 3, file.Lines[2].Number)
+
+	file.Append(Record{OID: "1.3.6.1.2.1.1.5.0", Tag: OctetString, Value: "name"})
+	assert.Equal(t, "1.3.6.1.2.1.1.5.0|4|name\n", string(file.Bytes()[len(file.Bytes())-25:]))
+
+	for _, invalid := range []string{
+		"1.3.6.1.2.1.1.1.0|4",
+		"|4|value",
+		"1.3.6.1.2.1.1.1.0|four|value",
+		"1.3.6.1.2.1.1.1.0|04|value",
+		"1.3.6.1.2.1.1.1.0|4:|value",
+	} {
+		_, err = ParseString("1.3.6.1.2.1.1.0.0|4|ok\n" + invalid + "\n")
+		if parseErr, ok := err.(*ParseError); assert.True(t, ok, "%q was parsed", invalid) {
+			assert.Equal(t, 2, parseErr.Line)
+		}
+	}
+}
+
+func TestLint(t *testing.T) {
+	file, err := ParseString(`1.3.6.1.2.1.1.1.0|4|ok
+1.3.6.1.2.1.1.2.0|6|1.3.6.1.4.1
+1.3.6.1.2.1.1.2.0|4|duplicate
+1.3.6.1.2.1.1.10.0|2|-5
+1.3.6.1.2.1.1.9.0|2|3
+1.3.6.1.2.1.1.11.0|99|unknown tag
+1.3.6.1.2.1.1.12.0|2|2147483648
+1.3.6.1.2.1.1.13.0|65|-1
+1.3.6.1.2.1.1.14.0|64|300.1.1.1
+1.3.6.1.2.1.1.15.0|64x|c0a80001
+1.3.6.1.2.1.1.16.0|4x|zz
+1.3.6.1.2.1.1.17.0|2x|01
+1.3.6.1.2.1.1.18.0|6|not an oid
+1.3.6.1.2.1.1.19.0|70|18446744073709551615
+1.3.6.1.2.1.1.20.0|67:numeric|anything
+1.3.6.1.2.1.1.21.0|5|
+1.3.6.1.2.1.1.22.0|130|value
+1.3.6.1.2.1.1..0|4|invalid oid
+`)
+	if !assert.NoError(t, err) {
+		return
+	}
+	var lines []int
+	for _, issue := range Lint(file) {
+		lines = append(lines, issue.Line)
+	}
+	assert.Equal(t, []int{3, 5, 6, 7, 8, 9, 11, 12, 13, 17, 18}, lines)
+
+	assert.NoError(t, Validate([]byte("1.3.6.1.2.1.1.1.0|4|ok\n")))
+	err = Validate([]byte("1.3.6.1.2.1.1.1.0|2|ok\n"))
+	if validationErr, ok := err.(*ValidationError); assert.True(t, ok) && assert.Len(t, validationErr.Issues, 1) {
+		assert.Equal(t, "line 1: 1.3.6.1.2.1.1.1.0: invalid Integer32 value 'ok'", validationErr.Issues[0].String())
+	}
+}
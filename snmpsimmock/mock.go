@@ -0,0 +1,167 @@
+/*
+Package snmpsimmock provides mock implementations of the snmpsimclient.ManagementAPI and snmpsimclient.MetricsAPI interfaces.
+
+The mocks record every call and answer it with canned results, so code depending on the interfaces can be unit tested
+without a network:
+
+	client := snmpsimmock.NewManagementClient()
+	client.Return("GetLabs", snmpsimclient.Labs{{Id: 1, Name: "lab"}}, nil)
+	client.ReturnOnce("CreateAgent", nil, snmpsimclient.ErrConflict)
+
+	err := service.Run(client)
+
+	calls := client.CallsTo("CreateAgent")
+
+Methods without configured results return the zero values of their result types and a nil error.
+The mocks are generated from the interfaces with go generate, see internal/mockgen.
+*/
+package snmpsimmock
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+/*
+Call is a recorded call of a mocked method. Ctx is the context given to the Context variant of the method,
+or context.Background() if the method without context was called.
+*/
+type Call struct {
+	Method string
+	Ctx    context.Context
+	Args   []interface{}
+}
+
+//mock contains the call recording and the canned results shared by all mocks, it is safe for concurrent use
+type mock struct {
+	mtx     sync.Mutex
+	results map[string]int
+
+	calls    []Call
+	defaults map[string][]interface{}
+	once     map[string][][]interface{}
+}
+
+/*
+Return configures the results of all calls of the given method. The results have to be given in the order of the method's
+result list, nil can be used for the zero value of any result.
+*/
+func (m *mock) Return(method string, results ...interface{}) {
+	m.checkResults(method, results)
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if m.defaults == nil {
+		m.defaults = make(map[string][]interface{})
+	}
+	m.defaults[method] = results
+}
+
+/*
+ReturnOnce configures the results of the next call of the given method. Results configured with ReturnOnce are used in
+the order they were configured before the results configured with Return.
+*/
+func (m *mock) ReturnOnce(method string, results ...interface{}) {
+	m.checkResults(method, results)
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if m.once == nil {
+		m.once = make(map[string][][]interface{})
+	}
+	m.once[method] = append(m.once[method], results)
+}
+
+/*
+Fail makes all calls of the given method return the given error and the zero values of all other results.
+*/
+func (m *mock) Fail(method string, err error) {
+	count, ok := m.results[method]
+	if !ok {
+		panic("snmpsimmock: unknown method " + method)
+	}
+	if count == 0 {
+		panic("snmpsimmock: method " + method + " has no results")
+	}
+	results := make([]interface{}, count)
+	results[count-1] = err
+	m.Return(method, results...)
+}
+
+/*
+Calls returns all recorded calls in the order they were made.
+*/
+func (m *mock) Calls() []Call {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+/*
+CallsTo returns all recorded calls of the given method in the order they were made.
+*/
+func (m *mock) CallsTo(method string) []Call {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	var calls []Call
+	for _, call := range m.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+/*
+Reset removes all recorded calls and configured results.
+*/
+func (m *mock) Reset() {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.calls = nil
+	m.defaults = nil
+	m.once = nil
+}
+
+//checkResults panics if the method does not exist or the number of results does not match
+func (m *mock) checkResults(method string, results []interface{}) {
+	count, ok := m.results[method]
+	if !ok {
+		panic("snmpsimmock: unknown method " + method)
+	}
+	if len(results) != count {
+		panic("snmpsimmock: method " + method + " has " + strconv.Itoa(count) + " results, got " + strconv.Itoa(len(results)))
+	}
+}
+
+//called records a call and returns the results configured for it
+func (m *mock) called(ctx context.Context, method string, args ...interface{}) results {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.calls = append(m.calls, Call{Method: method, Ctx: ctx, Args: args})
+	if queued := m.once[method]; len(queued) != 0 {
+		m.once[method] = queued[1:]
+		return results{method: method, values: queued[0]}
+	}
+	return results{method: method, values: m.defaults[method]}
+}
+
+//results are the configured results of a single call
+type results struct {
+	method string
+	values []interface{}
+}
+
+//assign sets the target to the i-th result, a missing or nil result leaves the zero value
+func (r results) assign(i int, target interface{}) {
+	if i >= len(r.values) || r.values[i] == nil {
+		return
+	}
+	value := reflect.ValueOf(r.values[i])
+	elem := reflect.ValueOf(target).Elem()
+	if !value.Type().AssignableTo(elem.Type()) {
+		panic(fmt.Sprintf("snmpsimmock: result %d of method %s has to be of type %s, got %s", i, r.method, elem.Type(), value.Type()))
+	}
+	elem.Set(value)
+}
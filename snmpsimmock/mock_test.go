@@ -0,0 +1,87 @@
+package snmpsimmock_test
+
+import (
+	"context"
+	"github.com/inexio/snmpsim-restapi-go-client"
+	"github.com/inexio/snmpsim-restapi-go-client/snmpsimmock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+//labNames is an example of code depending on the ManagementAPI
+func labNames(ctx context.Context, api snmpsimclient.ManagementAPI) ([]string, error) {
+	labs, err := api.GetLabsContext(ctx, map[string]string{"power": "on"})
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, lab := range labs {
+		names = append(names, lab.Name)
+	}
+	return names, nil
+}
+
+func TestManagementClient(t *testing.T) {
+	client := snmpsimmock.NewManagementClient()
+	client.Return("GetLabs", snmpsimclient.Labs{{Id: 1, Name: "lab 1"}, {Id: 2, Name: "lab 2"}}, nil)
+	client.ReturnOnce("GetLabs", nil, snmpsimclient.ErrServer)
+
+	ctx := context.WithValue(context.Background(), struct{}{}, "value")
+	_, err := labNames(ctx, client)
+	assert.True(t, errors.Is(err, snmpsimclient.ErrServer), "result configured with ReturnOnce was not used first")
+	names, err := labNames(ctx, client)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"lab 1", "lab 2"}, names)
+	}
+
+	calls := client.CallsTo("GetLabs")
+	if assert.Len(t, calls, 2) {
+		assert.Equal(t, ctx, calls[0].Ctx)
+		assert.Equal(t, []interface{}{map[string]string{"power": "on"}}, calls[0].Args)
+	}
+
+	//unconfigured methods return zero values
+	agent, err := client.CreateAgent("agent", "data")
+	assert.NoError(t, err)
+	assert.Equal(t, snmpsimclient.Agent{}, agent)
+	calls = client.CallsTo("CreateAgent")
+	if assert.Len(t, calls, 1) {
+		assert.Equal(t, context.Background(), calls[0].Ctx)
+		assert.Equal(t, []interface{}{"agent", "data"}, calls[0].Args)
+	}
+
+	client.Fail("DeleteLab", snmpsimclient.ErrNotFound)
+	assert.True(t, errors.Is(client.DeleteLab(1), snmpsimclient.ErrNotFound))
+	assert.Len(t, client.Calls(), 4)
+
+	assert.Panics(t, func() { client.Return("GetLabs", nil) }, "wrong number of results was accepted")
+	assert.Panics(t, func() { client.Return("NoSuchMethod") }, "unknown method was accepted")
+	client.Return("GetLab", snmpsimclient.Agent{}, nil)
+	assert.Panics(t, func() { _, _ = client.GetLab(1) }, "result of wrong type was accepted")
+
+	client.Reset()
+	assert.Empty(t, client.Calls())
+	_, err = client.GetLab(1)
+	assert.NoError(t, err)
+}
+
+func TestMetricsClient(t *testing.T) {
+	client := snmpsimmock.NewMetricsClient()
+	var total int64 = 42
+	client.Return("GetPackets", snmpsimclient.PacketMetrics{Total: &total}, nil)
+	client.Return("GetProcessConsolePages", snmpsimclient.Consoles{{Id: 1}}, nil)
+
+	var api snmpsimclient.MetricsAPI = client
+	packets, err := api.GetPackets(nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(42), *packets.Total)
+	}
+	pages, err := api.GetProcessConsolePages(1)
+	if assert.NoError(t, err) {
+		assert.Len(t, pages, 1)
+	}
+	if calls := client.CallsTo("GetProcessConsolePages"); assert.Len(t, calls, 1) {
+		assert.Equal(t, []interface{}{1}, calls[0].Args)
+	}
+}
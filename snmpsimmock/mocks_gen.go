@@ -0,0 +1,1435 @@
+// Code generated by internal/mockgen. DO NOT EDIT.
+
+package snmpsimmock
+
+import (
+	"context"
+	"github.com/inexio/snmpsim-restapi-go-client"
+	"io"
+)
+
+/*
+ManagementClient is a mock implementation of snmpsimclient.ManagementAPI.
+Calls of a method and its Context variant are recorded under the name of the method without the Context suffix.
+*/
+type ManagementClient struct {
+	mock
+}
+
+/*
+NewManagementClient creates a new ManagementClient without any configured results.
+*/
+func NewManagementClient() *ManagementClient {
+	return &ManagementClient{mock{results: managementClientResults}}
+}
+
+var _ snmpsimclient.ManagementAPI = (*ManagementClient)(nil)
+
+// managementClientResults contains the number of results of all mocked methods
+var managementClientResults = map[string]int{
+	"SetUsernameAndPassword":   1,
+	"GetLabs":                  2,
+	"SearchLabs":               2,
+	"GetLab":                   2,
+	"CreateLab":                2,
+	"CreateLabWithTag":         2,
+	"DeleteLab":                1,
+	"UpdateLab":                2,
+	"AddAgentToLab":            1,
+	"RemoveAgentFromLab":       1,
+	"SetLabPower":              1,
+	"AddTagToLab":              1,
+	"RemoveTagFromLab":         1,
+	"GetEngines":               2,
+	"SearchEngines":            2,
+	"GetEngine":                2,
+	"CreateEngine":             2,
+	"CreateEngineWithTag":      2,
+	"DeleteEngine":             1,
+	"UpdateEngine":             2,
+	"AddUserToEngine":          1,
+	"RemoveUserFromEngine":     1,
+	"AddEndpointToEngine":      1,
+	"RemoveEndpointFromEngine": 1,
+	"AddTagToEngine":           1,
+	"RemoveTagFromEngine":      1,
+	"GetAgents":                2,
+	"SearchAgents":             2,
+	"GetAgent":                 2,
+	"CreateAgent":              2,
+	"CreateAgentWithTag":       2,
+	"DeleteAgent":              1,
+	"UpdateAgent":              2,
+	"AddEngineToAgent":         1,
+	"RemoveEngineFromAgent":    1,
+	"AddSelectorToAgent":       2,
+	"RemoveSelectorFromAgent":  2,
+	"AddTagToAgent":            1,
+	"RemoveTagFromAgent":       1,
+	"GetEndpoints":             2,
+	"SearchEndpoints":          2,
+	"GetEndpoint":              2,
+	"CreateEndpoint":           2,
+	"CreateEndpointWithTag":    2,
+	"DeleteEndpoint":           1,
+	"UpdateEndpoint":           2,
+	"AddTagToEndpoint":         1,
+	"RemoveTagFromEndpoint":    1,
+	"GetRecordFiles":           2,
+	"UploadRecordFile":         1,
+	"UploadRecordFileString":   1,
+	"DeleteRecordFile":         1,
+	"GetRecordFile":            2,
+	"UploadRecording":          1,
+	"DownloadRecording":        1,
+	"SyncRecordings":           2,
+	"CreateUser":               2,
+	"CreateUserWithTag":        2,
+	"GetUsers":                 2,
+	"SearchUsers":              2,
+	"GetUser":                  2,
+	"DeleteUser":               1,
+	"UpdateUser":               2,
+	"AddTagToUser":             1,
+	"RemoveTagFromUser":        1,
+	"CreateSelector":           2,
+	"CreateSelectorWithTag":    2,
+	"GetSelectors":             2,
+	"GetSelector":              2,
+	"DeleteSelector":           1,
+	"AddTagToSelector":         1,
+	"RemoveTagFromSelector":    1,
+	"CreateTag":                2,
+	"GetTag":                   2,
+	"GetTags":                  2,
+	"SearchTags":               2,
+	"DeleteTag":                1,
+	"UpdateTag":                2,
+	"DeleteAllObjectsWithTag":  2,
+	"ListLabs":                 1,
+	"ListEngines":              1,
+	"ListAgents":               1,
+	"ListEndpoints":            1,
+	"ListUsers":                1,
+	"ListTags":                 1,
+	"ResolveSelector":          2,
+	"ApplyLab":                 2,
+	"ExportLab":                2,
+	"ApplyManifest":            2,
+	"NewTransaction":           1,
+	"InTransaction":            1,
+}
+
+func (m *ManagementClient) SetUsernameAndPassword(username string, password string) error {
+	results := m.called(context.Background(), "SetUsernameAndPassword", username, password)
+	var r0 error
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) GetLabs(filter map[string]string) (snmpsimclient.Labs, error) {
+	return m.GetLabsContext(context.Background(), filter)
+}
+
+func (m *ManagementClient) GetLabsContext(ctx context.Context, filter map[string]string) (snmpsimclient.Labs, error) {
+	results := m.called(ctx, "GetLabs", filter)
+	var r0 snmpsimclient.Labs
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) SearchLabs(filter snmpsimclient.LabFilter) (snmpsimclient.Labs, error) {
+	return m.SearchLabsContext(context.Background(), filter)
+}
+
+func (m *ManagementClient) SearchLabsContext(ctx context.Context, filter snmpsimclient.LabFilter) (snmpsimclient.Labs, error) {
+	results := m.called(ctx, "SearchLabs", filter)
+	var r0 snmpsimclient.Labs
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) GetLab(id int) (snmpsimclient.Lab, error) {
+	return m.GetLabContext(context.Background(), id)
+}
+
+func (m *ManagementClient) GetLabContext(ctx context.Context, id int) (snmpsimclient.Lab, error) {
+	results := m.called(ctx, "GetLab", id)
+	var r0 snmpsimclient.Lab
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) CreateLab(name string) (snmpsimclient.Lab, error) {
+	return m.CreateLabContext(context.Background(), name)
+}
+
+func (m *ManagementClient) CreateLabContext(ctx context.Context, name string) (snmpsimclient.Lab, error) {
+	results := m.called(ctx, "CreateLab", name)
+	var r0 snmpsimclient.Lab
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) CreateLabWithTag(name string, tagId int) (snmpsimclient.Lab, error) {
+	return m.CreateLabWithTagContext(context.Background(), name, tagId)
+}
+
+func (m *ManagementClient) CreateLabWithTagContext(ctx context.Context, name string, tagId int) (snmpsimclient.Lab, error) {
+	results := m.called(ctx, "CreateLabWithTag", name, tagId)
+	var r0 snmpsimclient.Lab
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) DeleteLab(id int) error {
+	return m.DeleteLabContext(context.Background(), id)
+}
+
+func (m *ManagementClient) DeleteLabContext(ctx context.Context, id int) error {
+	results := m.called(ctx, "DeleteLab", id)
+	var r0 error
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) UpdateLab(id int, update snmpsimclient.LabUpdate) (snmpsimclient.Lab, error) {
+	return m.UpdateLabContext(context.Background(), id, update)
+}
+
+func (m *ManagementClient) UpdateLabContext(ctx context.Context, id int, update snmpsimclient.LabUpdate) (snmpsimclient.Lab, error) {
+	results := m.called(ctx, "UpdateLab", id, update)
+	var r0 snmpsimclient.Lab
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) AddAgentToLab(labId int, agentId int) error {
+	return m.AddAgentToLabContext(context.Background(), labId, agentId)
+}
+
+func (m *ManagementClient) AddAgentToLabContext(ctx context.Context, labId int, agentId int) error {
+	results := m.called(ctx, "AddAgentToLab", labId, agentId)
+	var r0 error
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) RemoveAgentFromLab(labId int, agentId int) error {
+	return m.RemoveAgentFromLabContext(context.Background(), labId, agentId)
+}
+
+func (m *ManagementClient) RemoveAgentFromLabContext(ctx context.Context, labId int, agentId int) error {
+	results := m.called(ctx, "RemoveAgentFromLab", labId, agentId)
+	var r0 error
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) SetLabPower(labId int, power bool) error {
+	return m.SetLabPowerContext(context.Background(), labId, power)
+}
+
+func (m *ManagementClient) SetLabPowerContext(ctx context.Context, labId int, power bool) error {
+	results := m.called(ctx, "SetLabPower", labId, power)
+	var r0 error
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) AddTagToLab(labId int, tagId int) error {
+	return m.AddTagToLabContext(context.Background(), labId, tagId)
+}
+
+func (m *ManagementClient) AddTagToLabContext(ctx context.Context, labId int, tagId int) error {
+	results := m.called(ctx, "AddTagToLab", labId, tagId)
+	var r0 error
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) RemoveTagFromLab(labId int, tagId int) error {
+	return m.RemoveTagFromLabContext(context.Background(), labId, tagId)
+}
+
+func (m *ManagementClient) RemoveTagFromLabContext(ctx context.Context, labId int, tagId int) error {
+	results := m.called(ctx, "RemoveTagFromLab", labId, tagId)
+	var r0 error
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) GetEngines(filter map[string]string) (snmpsimclient.Engines, error) {
+	return m.GetEnginesContext(context.Background(), filter)
+}
+
+func (m *ManagementClient) GetEnginesContext(ctx context.Context, filter map[string]string) (snmpsimclient.Engines, error) {
+	results := m.called(ctx, "GetEngines", filter)
+	var r0 snmpsimclient.Engines
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) SearchEngines(filter snmpsimclient.EngineFilter) (snmpsimclient.Engines, error) {
+	return m.SearchEnginesContext(context.Background(), filter)
+}
+
+func (m *ManagementClient) SearchEnginesContext(ctx context.Context, filter snmpsimclient.EngineFilter) (snmpsimclient.Engines, error) {
+	results := m.called(ctx, "SearchEngines", filter)
+	var r0 snmpsimclient.Engines
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) GetEngine(id int) (snmpsimclient.Engine, error) {
+	return m.GetEngineContext(context.Background(), id)
+}
+
+func (m *ManagementClient) GetEngineContext(ctx context.Context, id int) (snmpsimclient.Engine, error) {
+	results := m.called(ctx, "GetEngine", id)
+	var r0 snmpsimclient.Engine
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) CreateEngine(name string, engineId string) (snmpsimclient.Engine, error) {
+	return m.CreateEngineContext(context.Background(), name, engineId)
+}
+
+func (m *ManagementClient) CreateEngineContext(ctx context.Context, name string, engineId string) (snmpsimclient.Engine, error) {
+	results := m.called(ctx, "CreateEngine", name, engineId)
+	var r0 snmpsimclient.Engine
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) CreateEngineWithTag(name string, engineId string, tagId int) (snmpsimclient.Engine, error) {
+	return m.CreateEngineWithTagContext(context.Background(), name, engineId, tagId)
+}
+
+func (m *ManagementClient) CreateEngineWithTagContext(ctx context.Context, name string, engineId string, tagId int) (snmpsimclient.Engine, error) {
+	results := m.called(ctx, "CreateEngineWithTag", name, engineId, tagId)
+	var r0 snmpsimclient.Engine
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) DeleteEngine(id int) error {
+	return m.DeleteEngineContext(context.Background(), id)
+}
+
+func (m *ManagementClient) DeleteEngineContext(ctx context.Context, id int) error {
+	results := m.called(ctx, "DeleteEngine", id)
+	var r0 error
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) UpdateEngine(id int, update snmpsimclient.EngineUpdate) (snmpsimclient.Engine, error) {
+	return m.UpdateEngineContext(context.Background(), id, update)
+}
+
+func (m *ManagementClient) UpdateEngineContext(ctx context.Context, id int, update snmpsimclient.EngineUpdate) (snmpsimclient.Engine, error) {
+	results := m.called(ctx, "UpdateEngine", id, update)
+	var r0 snmpsimclient.Engine
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) AddUserToEngine(engineId int, userId int) error {
+	return m.AddUserToEngineContext(context.Background(), engineId, userId)
+}
+
+func (m *ManagementClient) AddUserToEngineContext(ctx context.Context, engineId int, userId int) error {
+	results := m.called(ctx, "AddUserToEngine", engineId, userId)
+	var r0 error
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) RemoveUserFromEngine(engineId int, userId int) error {
+	return m.RemoveUserFromEngineContext(context.Background(), engineId, userId)
+}
+
+func (m *ManagementClient) RemoveUserFromEngineContext(ctx context.Context, engineId int, userId int) error {
+	results := m.called(ctx, "RemoveUserFromEngine", engineId, userId)
+	var r0 error
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) AddEndpointToEngine(engineId int, endpointId int) error {
+	return m.AddEndpointToEngineContext(context.Background(), engineId, endpointId)
+}
+
+func (m *ManagementClient) AddEndpointToEngineContext(ctx context.Context, engineId int, endpointId int) error {
+	results := m.called(ctx, "AddEndpointToEngine", engineId, endpointId)
+	var r0 error
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) RemoveEndpointFromEngine(engineId int, endpointId int) error {
+	return m.RemoveEndpointFromEngineContext(context.Background(), engineId, endpointId)
+}
+
+func (m *ManagementClient) RemoveEndpointFromEngineContext(ctx context.Context, engineId int, endpointId int) error {
+	results := m.called(ctx, "RemoveEndpointFromEngine", engineId, endpointId)
+	var r0 error
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) AddTagToEngine(engineId int, tagId int) error {
+	return m.AddTagToEngineContext(context.Background(), engineId, tagId)
+}
+
+func (m *ManagementClient) AddTagToEngineContext(ctx context.Context, engineId int, tagId int) error {
+	results := m.called(ctx, "AddTagToEngine", engineId, tagId)
+	var r0 error
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) RemoveTagFromEngine(engineId int, tagId int) error {
+	return m.RemoveTagFromEngineContext(context.Background(), engineId, tagId)
+}
+
+func (m *ManagementClient) RemoveTagFromEngineContext(ctx context.Context, engineId int, tagId int) error {
+	results := m.called(ctx, "RemoveTagFromEngine", engineId, tagId)
+	var r0 error
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) GetAgents(filters map[string]string) (snmpsimclient.Agents, error) {
+	return m.GetAgentsContext(context.Background(), filters)
+}
+
+func (m *ManagementClient) GetAgentsContext(ctx context.Context, filters map[string]string) (snmpsimclient.Agents, error) {
+	results := m.called(ctx, "GetAgents", filters)
+	var r0 snmpsimclient.Agents
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) SearchAgents(filter snmpsimclient.AgentFilter) (snmpsimclient.Agents, error) {
+	return m.SearchAgentsContext(context.Background(), filter)
+}
+
+func (m *ManagementClient) SearchAgentsContext(ctx context.Context, filter snmpsimclient.AgentFilter) (snmpsimclient.Agents, error) {
+	results := m.called(ctx, "SearchAgents", filter)
+	var r0 snmpsimclient.Agents
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) GetAgent(id int) (snmpsimclient.Agent, error) {
+	return m.GetAgentContext(context.Background(), id)
+}
+
+func (m *ManagementClient) GetAgentContext(ctx context.Context, id int) (snmpsimclient.Agent, error) {
+	results := m.called(ctx, "GetAgent", id)
+	var r0 snmpsimclient.Agent
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) CreateAgent(name string, dataDir string) (snmpsimclient.Agent, error) {
+	return m.CreateAgentContext(context.Background(), name, dataDir)
+}
+
+func (m *ManagementClient) CreateAgentContext(ctx context.Context, name string, dataDir string) (snmpsimclient.Agent, error) {
+	results := m.called(ctx, "CreateAgent", name, dataDir)
+	var r0 snmpsimclient.Agent
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) CreateAgentWithTag(name string, dataDir string, tagId int) (snmpsimclient.Agent, error) {
+	return m.CreateAgentWithTagContext(context.Background(), name, dataDir, tagId)
+}
+
+func (m *ManagementClient) CreateAgentWithTagContext(ctx context.Context, name string, dataDir string, tagId int) (snmpsimclient.Agent, error) {
+	results := m.called(ctx, "CreateAgentWithTag", name, dataDir, tagId)
+	var r0 snmpsimclient.Agent
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) DeleteAgent(id int) error {
+	return m.DeleteAgentContext(context.Background(), id)
+}
+
+func (m *ManagementClient) DeleteAgentContext(ctx context.Context, id int) error {
+	results := m.called(ctx, "DeleteAgent", id)
+	var r0 error
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) UpdateAgent(id int, update snmpsimclient.AgentUpdate) (snmpsimclient.Agent, error) {
+	return m.UpdateAgentContext(context.Background(), id, update)
+}
+
+func (m *ManagementClient) UpdateAgentContext(ctx context.Context, id int, update snmpsimclient.AgentUpdate) (snmpsimclient.Agent, error) {
+	results := m.called(ctx, "UpdateAgent", id, update)
+	var r0 snmpsimclient.Agent
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) AddEngineToAgent(agentId int, engineId int) error {
+	return m.AddEngineToAgentContext(context.Background(), agentId, engineId)
+}
+
+func (m *ManagementClient) AddEngineToAgentContext(ctx context.Context, agentId int, engineId int) error {
+	results := m.called(ctx, "AddEngineToAgent", agentId, engineId)
+	var r0 error
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) RemoveEngineFromAgent(agentId int, engineId int) error {
+	return m.RemoveEngineFromAgentContext(context.Background(), agentId, engineId)
+}
+
+func (m *ManagementClient) RemoveEngineFromAgentContext(ctx context.Context, agentId int, engineId int) error {
+	results := m.called(ctx, "RemoveEngineFromAgent", agentId, engineId)
+	var r0 error
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) AddSelectorToAgent(agentId int, selectorId int) (snmpsimclient.Agent, error) {
+	return m.AddSelectorToAgentContext(context.Background(), agentId, selectorId)
+}
+
+func (m *ManagementClient) AddSelectorToAgentContext(ctx context.Context, agentId int, selectorId int) (snmpsimclient.Agent, error) {
+	results := m.called(ctx, "AddSelectorToAgent", agentId, selectorId)
+	var r0 snmpsimclient.Agent
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) RemoveSelectorFromAgent(agentId int, selectorId int) (snmpsimclient.Agent, error) {
+	return m.RemoveSelectorFromAgentContext(context.Background(), agentId, selectorId)
+}
+
+func (m *ManagementClient) RemoveSelectorFromAgentContext(ctx context.Context, agentId int, selectorId int) (snmpsimclient.Agent, error) {
+	results := m.called(ctx, "RemoveSelectorFromAgent", agentId, selectorId)
+	var r0 snmpsimclient.Agent
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) AddTagToAgent(agentId int, tagId int) error {
+	return m.AddTagToAgentContext(context.Background(), agentId, tagId)
+}
+
+func (m *ManagementClient) AddTagToAgentContext(ctx context.Context, agentId int, tagId int) error {
+	results := m.called(ctx, "AddTagToAgent", agentId, tagId)
+	var r0 error
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) RemoveTagFromAgent(agentId int, tagId int) error {
+	return m.RemoveTagFromAgentContext(context.Background(), agentId, tagId)
+}
+
+func (m *ManagementClient) RemoveTagFromAgentContext(ctx context.Context, agentId int, tagId int) error {
+	results := m.called(ctx, "RemoveTagFromAgent", agentId, tagId)
+	var r0 error
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) GetEndpoints(filters map[string]string) (snmpsimclient.Endpoints, error) {
+	return m.GetEndpointsContext(context.Background(), filters)
+}
+
+func (m *ManagementClient) GetEndpointsContext(ctx context.Context, filters map[string]string) (snmpsimclient.Endpoints, error) {
+	results := m.called(ctx, "GetEndpoints", filters)
+	var r0 snmpsimclient.Endpoints
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) SearchEndpoints(filter snmpsimclient.EndpointFilter) (snmpsimclient.Endpoints, error) {
+	return m.SearchEndpointsContext(context.Background(), filter)
+}
+
+func (m *ManagementClient) SearchEndpointsContext(ctx context.Context, filter snmpsimclient.EndpointFilter) (snmpsimclient.Endpoints, error) {
+	results := m.called(ctx, "SearchEndpoints", filter)
+	var r0 snmpsimclient.Endpoints
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) GetEndpoint(id int) (snmpsimclient.Endpoint, error) {
+	return m.GetEndpointContext(context.Background(), id)
+}
+
+func (m *ManagementClient) GetEndpointContext(ctx context.Context, id int) (snmpsimclient.Endpoint, error) {
+	results := m.called(ctx, "GetEndpoint", id)
+	var r0 snmpsimclient.Endpoint
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) CreateEndpoint(name string, address string, protocol string) (snmpsimclient.Endpoint, error) {
+	return m.CreateEndpointContext(context.Background(), name, address, protocol)
+}
+
+func (m *ManagementClient) CreateEndpointContext(ctx context.Context, name string, address string, protocol string) (snmpsimclient.Endpoint, error) {
+	results := m.called(ctx, "CreateEndpoint", name, address, protocol)
+	var r0 snmpsimclient.Endpoint
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) CreateEndpointWithTag(name string, address string, protocol string, tagId int) (snmpsimclient.Endpoint, error) {
+	return m.CreateEndpointWithTagContext(context.Background(), name, address, protocol, tagId)
+}
+
+func (m *ManagementClient) CreateEndpointWithTagContext(ctx context.Context, name string, address string, protocol string, tagId int) (snmpsimclient.Endpoint, error) {
+	results := m.called(ctx, "CreateEndpointWithTag", name, address, protocol, tagId)
+	var r0 snmpsimclient.Endpoint
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) DeleteEndpoint(id int) error {
+	return m.DeleteEndpointContext(context.Background(), id)
+}
+
+func (m *ManagementClient) DeleteEndpointContext(ctx context.Context, id int) error {
+	results := m.called(ctx, "DeleteEndpoint", id)
+	var r0 error
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) UpdateEndpoint(id int, update snmpsimclient.EndpointUpdate) (snmpsimclient.Endpoint, error) {
+	return m.UpdateEndpointContext(context.Background(), id, update)
+}
+
+func (m *ManagementClient) UpdateEndpointContext(ctx context.Context, id int, update snmpsimclient.EndpointUpdate) (snmpsimclient.Endpoint, error) {
+	results := m.called(ctx, "UpdateEndpoint", id, update)
+	var r0 snmpsimclient.Endpoint
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) AddTagToEndpoint(endpointId int, tagId int) error {
+	return m.AddTagToEndpointContext(context.Background(), endpointId, tagId)
+}
+
+func (m *ManagementClient) AddTagToEndpointContext(ctx context.Context, endpointId int, tagId int) error {
+	results := m.called(ctx, "AddTagToEndpoint", endpointId, tagId)
+	var r0 error
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) RemoveTagFromEndpoint(endpointId int, tagId int) error {
+	return m.RemoveTagFromEndpointContext(context.Background(), endpointId, tagId)
+}
+
+func (m *ManagementClient) RemoveTagFromEndpointContext(ctx context.Context, endpointId int, tagId int) error {
+	results := m.called(ctx, "RemoveTagFromEndpoint", endpointId, tagId)
+	var r0 error
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) GetRecordFiles() (snmpsimclient.Recordings, error) {
+	return m.GetRecordFilesContext(context.Background())
+}
+
+func (m *ManagementClient) GetRecordFilesContext(ctx context.Context) (snmpsimclient.Recordings, error) {
+	results := m.called(ctx, "GetRecordFiles")
+	var r0 snmpsimclient.Recordings
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) UploadRecordFile(localPath string, remotePath string) error {
+	return m.UploadRecordFileContext(context.Background(), localPath, remotePath)
+}
+
+func (m *ManagementClient) UploadRecordFileContext(ctx context.Context, localPath string, remotePath string) error {
+	results := m.called(ctx, "UploadRecordFile", localPath, remotePath)
+	var r0 error
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) UploadRecordFileString(recordContents *string, remotePath string) error {
+	return m.UploadRecordFileStringContext(context.Background(), recordContents, remotePath)
+}
+
+func (m *ManagementClient) UploadRecordFileStringContext(ctx context.Context, recordContents *string, remotePath string) error {
+	results := m.called(ctx, "UploadRecordFileString", recordContents, remotePath)
+	var r0 error
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) DeleteRecordFile(remotePath string) error {
+	return m.DeleteRecordFileContext(context.Background(), remotePath)
+}
+
+func (m *ManagementClient) DeleteRecordFileContext(ctx context.Context, remotePath string) error {
+	results := m.called(ctx, "DeleteRecordFile", remotePath)
+	var r0 error
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) GetRecordFile(remotePath string) (string, error) {
+	return m.GetRecordFileContext(context.Background(), remotePath)
+}
+
+func (m *ManagementClient) GetRecordFileContext(ctx context.Context, remotePath string) (string, error) {
+	results := m.called(ctx, "GetRecordFile", remotePath)
+	var r0 string
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) UploadRecording(ctx context.Context, remotePath string, r io.Reader, opts ...snmpsimclient.TransferOption) error {
+	results := m.called(ctx, "UploadRecording", remotePath, r, opts)
+	var r0 error
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) DownloadRecording(ctx context.Context, remotePath string, w io.Writer, opts ...snmpsimclient.TransferOption) error {
+	results := m.called(ctx, "DownloadRecording", remotePath, w, opts)
+	var r0 error
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) SyncRecordings(localDir string, remotePrefix string, opts snmpsimclient.SyncOptions) (snmpsimclient.SyncReport, error) {
+	return m.SyncRecordingsContext(context.Background(), localDir, remotePrefix, opts)
+}
+
+func (m *ManagementClient) SyncRecordingsContext(ctx context.Context, localDir string, remotePrefix string, opts snmpsimclient.SyncOptions) (snmpsimclient.SyncReport, error) {
+	results := m.called(ctx, "SyncRecordings", localDir, remotePrefix, opts)
+	var r0 snmpsimclient.SyncReport
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) CreateUser(user string, name string, authKey string, authProto string, privKey string, privProto string) (snmpsimclient.User, error) {
+	return m.CreateUserContext(context.Background(), user, name, authKey, authProto, privKey, privProto)
+}
+
+func (m *ManagementClient) CreateUserContext(ctx context.Context, user string, name string, authKey string, authProto string, privKey string, privProto string) (snmpsimclient.User, error) {
+	results := m.called(ctx, "CreateUser", user, name, authKey, authProto, privKey, privProto)
+	var r0 snmpsimclient.User
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) CreateUserWithTag(user string, name string, authKey string, authProto string, privKey string, privProto string, tagId int) (snmpsimclient.User, error) {
+	return m.CreateUserWithTagContext(context.Background(), user, name, authKey, authProto, privKey, privProto, tagId)
+}
+
+func (m *ManagementClient) CreateUserWithTagContext(ctx context.Context, user string, name string, authKey string, authProto string, privKey string, privProto string, tagId int) (snmpsimclient.User, error) {
+	results := m.called(ctx, "CreateUserWithTag", user, name, authKey, authProto, privKey, privProto, tagId)
+	var r0 snmpsimclient.User
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) GetUsers(filters map[string]string) (snmpsimclient.Users, error) {
+	return m.GetUsersContext(context.Background(), filters)
+}
+
+func (m *ManagementClient) GetUsersContext(ctx context.Context, filters map[string]string) (snmpsimclient.Users, error) {
+	results := m.called(ctx, "GetUsers", filters)
+	var r0 snmpsimclient.Users
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) SearchUsers(filter snmpsimclient.UserFilter) (snmpsimclient.Users, error) {
+	return m.SearchUsersContext(context.Background(), filter)
+}
+
+func (m *ManagementClient) SearchUsersContext(ctx context.Context, filter snmpsimclient.UserFilter) (snmpsimclient.Users, error) {
+	results := m.called(ctx, "SearchUsers", filter)
+	var r0 snmpsimclient.Users
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) GetUser(id int) (snmpsimclient.User, error) {
+	return m.GetUserContext(context.Background(), id)
+}
+
+func (m *ManagementClient) GetUserContext(ctx context.Context, id int) (snmpsimclient.User, error) {
+	results := m.called(ctx, "GetUser", id)
+	var r0 snmpsimclient.User
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) DeleteUser(id int) error {
+	return m.DeleteUserContext(context.Background(), id)
+}
+
+func (m *ManagementClient) DeleteUserContext(ctx context.Context, id int) error {
+	results := m.called(ctx, "DeleteUser", id)
+	var r0 error
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) UpdateUser(id int, update snmpsimclient.UserUpdate) (snmpsimclient.User, error) {
+	return m.UpdateUserContext(context.Background(), id, update)
+}
+
+func (m *ManagementClient) UpdateUserContext(ctx context.Context, id int, update snmpsimclient.UserUpdate) (snmpsimclient.User, error) {
+	results := m.called(ctx, "UpdateUser", id, update)
+	var r0 snmpsimclient.User
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) AddTagToUser(userId int, tagId int) error {
+	return m.AddTagToUserContext(context.Background(), userId, tagId)
+}
+
+func (m *ManagementClient) AddTagToUserContext(ctx context.Context, userId int, tagId int) error {
+	results := m.called(ctx, "AddTagToUser", userId, tagId)
+	var r0 error
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) RemoveTagFromUser(userId int, tagId int) error {
+	return m.RemoveTagFromUserContext(context.Background(), userId, tagId)
+}
+
+func (m *ManagementClient) RemoveTagFromUserContext(ctx context.Context, userId int, tagId int) error {
+	results := m.called(ctx, "RemoveTagFromUser", userId, tagId)
+	var r0 error
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) CreateSelector(comment string, template string) (snmpsimclient.Selector, error) {
+	return m.CreateSelectorContext(context.Background(), comment, template)
+}
+
+func (m *ManagementClient) CreateSelectorContext(ctx context.Context, comment string, template string) (snmpsimclient.Selector, error) {
+	results := m.called(ctx, "CreateSelector", comment, template)
+	var r0 snmpsimclient.Selector
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) CreateSelectorWithTag(comment string, template string, tagId int) (snmpsimclient.Selector, error) {
+	return m.CreateSelectorWithTagContext(context.Background(), comment, template, tagId)
+}
+
+func (m *ManagementClient) CreateSelectorWithTagContext(ctx context.Context, comment string, template string, tagId int) (snmpsimclient.Selector, error) {
+	results := m.called(ctx, "CreateSelectorWithTag", comment, template, tagId)
+	var r0 snmpsimclient.Selector
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) GetSelectors() (snmpsimclient.Selectors, error) {
+	return m.GetSelectorsContext(context.Background())
+}
+
+func (m *ManagementClient) GetSelectorsContext(ctx context.Context) (snmpsimclient.Selectors, error) {
+	results := m.called(ctx, "GetSelectors")
+	var r0 snmpsimclient.Selectors
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) GetSelector(id int) (snmpsimclient.Selector, error) {
+	return m.GetSelectorContext(context.Background(), id)
+}
+
+func (m *ManagementClient) GetSelectorContext(ctx context.Context, id int) (snmpsimclient.Selector, error) {
+	results := m.called(ctx, "GetSelector", id)
+	var r0 snmpsimclient.Selector
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) DeleteSelector(id int) error {
+	return m.DeleteSelectorContext(context.Background(), id)
+}
+
+func (m *ManagementClient) DeleteSelectorContext(ctx context.Context, id int) error {
+	results := m.called(ctx, "DeleteSelector", id)
+	var r0 error
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) AddTagToSelector(selectorId int, tagId int) error {
+	return m.AddTagToSelectorContext(context.Background(), selectorId, tagId)
+}
+
+func (m *ManagementClient) AddTagToSelectorContext(ctx context.Context, selectorId int, tagId int) error {
+	results := m.called(ctx, "AddTagToSelector", selectorId, tagId)
+	var r0 error
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) RemoveTagFromSelector(selectorId int, tagId int) error {
+	return m.RemoveTagFromSelectorContext(context.Background(), selectorId, tagId)
+}
+
+func (m *ManagementClient) RemoveTagFromSelectorContext(ctx context.Context, selectorId int, tagId int) error {
+	results := m.called(ctx, "RemoveTagFromSelector", selectorId, tagId)
+	var r0 error
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) CreateTag(name string, description string) (snmpsimclient.Tag, error) {
+	return m.CreateTagContext(context.Background(), name, description)
+}
+
+func (m *ManagementClient) CreateTagContext(ctx context.Context, name string, description string) (snmpsimclient.Tag, error) {
+	results := m.called(ctx, "CreateTag", name, description)
+	var r0 snmpsimclient.Tag
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) GetTag(id int) (snmpsimclient.Tag, error) {
+	return m.GetTagContext(context.Background(), id)
+}
+
+func (m *ManagementClient) GetTagContext(ctx context.Context, id int) (snmpsimclient.Tag, error) {
+	results := m.called(ctx, "GetTag", id)
+	var r0 snmpsimclient.Tag
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) GetTags(filters map[string]string) (snmpsimclient.Tags, error) {
+	return m.GetTagsContext(context.Background(), filters)
+}
+
+func (m *ManagementClient) GetTagsContext(ctx context.Context, filters map[string]string) (snmpsimclient.Tags, error) {
+	results := m.called(ctx, "GetTags", filters)
+	var r0 snmpsimclient.Tags
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) SearchTags(filter snmpsimclient.TagFilter) (snmpsimclient.Tags, error) {
+	return m.SearchTagsContext(context.Background(), filter)
+}
+
+func (m *ManagementClient) SearchTagsContext(ctx context.Context, filter snmpsimclient.TagFilter) (snmpsimclient.Tags, error) {
+	results := m.called(ctx, "SearchTags", filter)
+	var r0 snmpsimclient.Tags
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) DeleteTag(id int) error {
+	return m.DeleteTagContext(context.Background(), id)
+}
+
+func (m *ManagementClient) DeleteTagContext(ctx context.Context, id int) error {
+	results := m.called(ctx, "DeleteTag", id)
+	var r0 error
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) UpdateTag(id int, update snmpsimclient.TagUpdate) (snmpsimclient.Tag, error) {
+	return m.UpdateTagContext(context.Background(), id, update)
+}
+
+func (m *ManagementClient) UpdateTagContext(ctx context.Context, id int, update snmpsimclient.TagUpdate) (snmpsimclient.Tag, error) {
+	results := m.called(ctx, "UpdateTag", id, update)
+	var r0 snmpsimclient.Tag
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) DeleteAllObjectsWithTag(tagId int) (snmpsimclient.Tag, error) {
+	return m.DeleteAllObjectsWithTagContext(context.Background(), tagId)
+}
+
+func (m *ManagementClient) DeleteAllObjectsWithTagContext(ctx context.Context, tagId int) (snmpsimclient.Tag, error) {
+	results := m.called(ctx, "DeleteAllObjectsWithTag", tagId)
+	var r0 snmpsimclient.Tag
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) ListLabs(ctx context.Context, opts snmpsimclient.ListOptions) *snmpsimclient.LabPager {
+	results := m.called(ctx, "ListLabs", opts)
+	var r0 *snmpsimclient.LabPager
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) ListEngines(ctx context.Context, opts snmpsimclient.ListOptions) *snmpsimclient.EnginePager {
+	results := m.called(ctx, "ListEngines", opts)
+	var r0 *snmpsimclient.EnginePager
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) ListAgents(ctx context.Context, opts snmpsimclient.ListOptions) *snmpsimclient.AgentPager {
+	results := m.called(ctx, "ListAgents", opts)
+	var r0 *snmpsimclient.AgentPager
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) ListEndpoints(ctx context.Context, opts snmpsimclient.ListOptions) *snmpsimclient.EndpointPager {
+	results := m.called(ctx, "ListEndpoints", opts)
+	var r0 *snmpsimclient.EndpointPager
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) ListUsers(ctx context.Context, opts snmpsimclient.ListOptions) *snmpsimclient.UserPager {
+	results := m.called(ctx, "ListUsers", opts)
+	var r0 *snmpsimclient.UserPager
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) ListTags(ctx context.Context, opts snmpsimclient.ListOptions) *snmpsimclient.TagPager {
+	results := m.called(ctx, "ListTags", opts)
+	var r0 *snmpsimclient.TagPager
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) ResolveSelector(agent snmpsimclient.Agent, template string, request snmpsimclient.SelectorRequest) (snmpsimclient.SelectorRoute, error) {
+	return m.ResolveSelectorContext(context.Background(), agent, template, request)
+}
+
+func (m *ManagementClient) ResolveSelectorContext(ctx context.Context, agent snmpsimclient.Agent, template string, request snmpsimclient.SelectorRequest) (snmpsimclient.SelectorRoute, error) {
+	results := m.called(ctx, "ResolveSelector", agent, template, request)
+	var r0 snmpsimclient.SelectorRoute
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) ApplyLab(spec snmpsimclient.LabSpec) (snmpsimclient.Lab, error) {
+	return m.ApplyLabContext(context.Background(), spec)
+}
+
+func (m *ManagementClient) ApplyLabContext(ctx context.Context, spec snmpsimclient.LabSpec) (snmpsimclient.Lab, error) {
+	results := m.called(ctx, "ApplyLab", spec)
+	var r0 snmpsimclient.Lab
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) ExportLab(labId int) (snmpsimclient.Manifest, error) {
+	return m.ExportLabContext(context.Background(), labId)
+}
+
+func (m *ManagementClient) ExportLabContext(ctx context.Context, labId int) (snmpsimclient.Manifest, error) {
+	results := m.called(ctx, "ExportLab", labId)
+	var r0 snmpsimclient.Manifest
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) ApplyManifest(manifest snmpsimclient.Manifest) (snmpsimclient.Labs, error) {
+	return m.ApplyManifestContext(context.Background(), manifest)
+}
+
+func (m *ManagementClient) ApplyManifestContext(ctx context.Context, manifest snmpsimclient.Manifest) (snmpsimclient.Labs, error) {
+	results := m.called(ctx, "ApplyManifest", manifest)
+	var r0 snmpsimclient.Labs
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *ManagementClient) NewTransaction() *snmpsimclient.Transaction {
+	return m.NewTransactionContext(context.Background())
+}
+
+func (m *ManagementClient) NewTransactionContext(ctx context.Context) *snmpsimclient.Transaction {
+	results := m.called(ctx, "NewTransaction")
+	var r0 *snmpsimclient.Transaction
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *ManagementClient) InTransaction(build func(*snmpsimclient.Transaction) error) error {
+	return m.InTransactionContext(context.Background(), build)
+}
+
+func (m *ManagementClient) InTransactionContext(ctx context.Context, build func(*snmpsimclient.Transaction) error) error {
+	results := m.called(ctx, "InTransaction", build)
+	var r0 error
+	results.assign(0, &r0)
+	return r0
+}
+
+/*
+MetricsClient is a mock implementation of snmpsimclient.MetricsAPI.
+Calls of a method and its Context variant are recorded under the name of the method without the Context suffix.
+*/
+type MetricsClient struct {
+	mock
+}
+
+/*
+NewMetricsClient creates a new MetricsClient without any configured results.
+*/
+func NewMetricsClient() *MetricsClient {
+	return &MetricsClient{mock{results: metricsClientResults}}
+}
+
+var _ snmpsimclient.MetricsAPI = (*MetricsClient)(nil)
+
+// metricsClientResults contains the number of results of all mocked methods
+var metricsClientResults = map[string]int{
+	"SetUsernameAndPassword":            1,
+	"GetProcesses":                      2,
+	"GetProcess":                        2,
+	"GetProcessEndpoints":               2,
+	"GetProcessEndpoint":                2,
+	"GetProcessConsolePages":            2,
+	"GetProcessConsolePage":             2,
+	"GetPackets":                        2,
+	"SearchPackets":                     2,
+	"GetPacketFilters":                  2,
+	"GetPossibleValuesForPacketFilter":  2,
+	"GetMessages":                       2,
+	"SearchMessages":                    2,
+	"GetMessageFilters":                 2,
+	"GetPossibleValuesForMessageFilter": 2,
+	"ListProcesses":                     1,
+	"PollMetrics":                       2,
+	"FilterCatalog":                     1,
+}
+
+func (m *MetricsClient) SetUsernameAndPassword(username string, password string) error {
+	results := m.called(context.Background(), "SetUsernameAndPassword", username, password)
+	var r0 error
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *MetricsClient) GetProcesses(filters map[string]string) (snmpsimclient.ProcessesMetrics, error) {
+	return m.GetProcessesContext(context.Background(), filters)
+}
+
+func (m *MetricsClient) GetProcessesContext(ctx context.Context, filters map[string]string) (snmpsimclient.ProcessesMetrics, error) {
+	results := m.called(ctx, "GetProcesses", filters)
+	var r0 snmpsimclient.ProcessesMetrics
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *MetricsClient) GetProcess(id int) (snmpsimclient.ProcessMetrics, error) {
+	return m.GetProcessContext(context.Background(), id)
+}
+
+func (m *MetricsClient) GetProcessContext(ctx context.Context, id int) (snmpsimclient.ProcessMetrics, error) {
+	results := m.called(ctx, "GetProcess", id)
+	var r0 snmpsimclient.ProcessMetrics
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *MetricsClient) GetProcessEndpoints(id int) (snmpsimclient.ProcessEndpoints, error) {
+	return m.GetProcessEndpointsContext(context.Background(), id)
+}
+
+func (m *MetricsClient) GetProcessEndpointsContext(ctx context.Context, id int) (snmpsimclient.ProcessEndpoints, error) {
+	results := m.called(ctx, "GetProcessEndpoints", id)
+	var r0 snmpsimclient.ProcessEndpoints
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *MetricsClient) GetProcessEndpoint(processId int, endpointId int) (snmpsimclient.ProcessEndpoint, error) {
+	return m.GetProcessEndpointContext(context.Background(), processId, endpointId)
+}
+
+func (m *MetricsClient) GetProcessEndpointContext(ctx context.Context, processId int, endpointId int) (snmpsimclient.ProcessEndpoint, error) {
+	results := m.called(ctx, "GetProcessEndpoint", processId, endpointId)
+	var r0 snmpsimclient.ProcessEndpoint
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *MetricsClient) GetProcessConsolePages(processId int) (snmpsimclient.Consoles, error) {
+	return m.GetProcessConsolePagesContext(context.Background(), processId)
+}
+
+func (m *MetricsClient) GetProcessConsolePagesContext(ctx context.Context, processId int) (snmpsimclient.Consoles, error) {
+	results := m.called(ctx, "GetProcessConsolePages", processId)
+	var r0 snmpsimclient.Consoles
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *MetricsClient) GetProcessConsolePage(processId int, pageId int) (snmpsimclient.Console, error) {
+	return m.GetProcessConsolePageContext(context.Background(), processId, pageId)
+}
+
+func (m *MetricsClient) GetProcessConsolePageContext(ctx context.Context, processId int, pageId int) (snmpsimclient.Console, error) {
+	results := m.called(ctx, "GetProcessConsolePage", processId, pageId)
+	var r0 snmpsimclient.Console
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *MetricsClient) GetPackets(filters map[string]string) (snmpsimclient.PacketMetrics, error) {
+	return m.GetPacketsContext(context.Background(), filters)
+}
+
+func (m *MetricsClient) GetPacketsContext(ctx context.Context, filters map[string]string) (snmpsimclient.PacketMetrics, error) {
+	results := m.called(ctx, "GetPackets", filters)
+	var r0 snmpsimclient.PacketMetrics
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *MetricsClient) SearchPackets(filter snmpsimclient.PacketFilter) (snmpsimclient.PacketMetrics, error) {
+	return m.SearchPacketsContext(context.Background(), filter)
+}
+
+func (m *MetricsClient) SearchPacketsContext(ctx context.Context, filter snmpsimclient.PacketFilter) (snmpsimclient.PacketMetrics, error) {
+	results := m.called(ctx, "SearchPackets", filter)
+	var r0 snmpsimclient.PacketMetrics
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *MetricsClient) GetPacketFilters() (snmpsimclient.PacketFilters, error) {
+	return m.GetPacketFiltersContext(context.Background())
+}
+
+func (m *MetricsClient) GetPacketFiltersContext(ctx context.Context) (snmpsimclient.PacketFilters, error) {
+	results := m.called(ctx, "GetPacketFilters")
+	var r0 snmpsimclient.PacketFilters
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *MetricsClient) GetPossibleValuesForPacketFilter(filter string) ([]string, error) {
+	return m.GetPossibleValuesForPacketFilterContext(context.Background(), filter)
+}
+
+func (m *MetricsClient) GetPossibleValuesForPacketFilterContext(ctx context.Context, filter string) ([]string, error) {
+	results := m.called(ctx, "GetPossibleValuesForPacketFilter", filter)
+	var r0 []string
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *MetricsClient) GetMessages(filters map[string]string) (snmpsimclient.MessageMetrics, error) {
+	return m.GetMessagesContext(context.Background(), filters)
+}
+
+func (m *MetricsClient) GetMessagesContext(ctx context.Context, filters map[string]string) (snmpsimclient.MessageMetrics, error) {
+	results := m.called(ctx, "GetMessages", filters)
+	var r0 snmpsimclient.MessageMetrics
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *MetricsClient) SearchMessages(filter snmpsimclient.MessageFilter) (snmpsimclient.MessageMetrics, error) {
+	return m.SearchMessagesContext(context.Background(), filter)
+}
+
+func (m *MetricsClient) SearchMessagesContext(ctx context.Context, filter snmpsimclient.MessageFilter) (snmpsimclient.MessageMetrics, error) {
+	results := m.called(ctx, "SearchMessages", filter)
+	var r0 snmpsimclient.MessageMetrics
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *MetricsClient) GetMessageFilters() (snmpsimclient.MessageFilters, error) {
+	return m.GetMessageFiltersContext(context.Background())
+}
+
+func (m *MetricsClient) GetMessageFiltersContext(ctx context.Context) (snmpsimclient.MessageFilters, error) {
+	results := m.called(ctx, "GetMessageFilters")
+	var r0 snmpsimclient.MessageFilters
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *MetricsClient) GetPossibleValuesForMessageFilter(filter string) ([]string, error) {
+	return m.GetPossibleValuesForMessageFilterContext(context.Background(), filter)
+}
+
+func (m *MetricsClient) GetPossibleValuesForMessageFilterContext(ctx context.Context, filter string) ([]string, error) {
+	results := m.called(ctx, "GetPossibleValuesForMessageFilter", filter)
+	var r0 []string
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *MetricsClient) ListProcesses(ctx context.Context, opts snmpsimclient.ListOptions) *snmpsimclient.ProcessPager {
+	results := m.called(ctx, "ListProcesses", opts)
+	var r0 *snmpsimclient.ProcessPager
+	results.assign(0, &r0)
+	return r0
+}
+
+func (m *MetricsClient) PollMetrics(ctx context.Context, opts snmpsimclient.PollOptions) (*snmpsimclient.MetricsPoller, error) {
+	results := m.called(ctx, "PollMetrics", opts)
+	var r0 *snmpsimclient.MetricsPoller
+	var r1 error
+	results.assign(0, &r0)
+	results.assign(1, &r1)
+	return r0, r1
+}
+
+func (m *MetricsClient) FilterCatalog() *snmpsimclient.FilterCatalog {
+	results := m.called(context.Background(), "FilterCatalog")
+	var r0 *snmpsimclient.FilterCatalog
+	results.assign(0, &r0)
+	return r0
+}
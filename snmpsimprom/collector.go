@@ -0,0 +1,353 @@
+/*
+Package snmpsimprom exports the metrics of the snmpsim metrics api to prometheus.
+
+The Collector requests the metrics from a snmpsimclient.MetricsClient on every scrape:
+
+	client, err := snmpsimclient.NewMetricsClient("http://127.0.0.1:8001")
+	collector, err := snmpsimprom.NewCollector(client, snmpsimprom.Options{PacketLabels: []string{"local_address"}})
+	prometheus.MustRegister(collector)
+
+A ready to use exporter is provided in cmd/snmpsim-exporter.
+*/
+package snmpsimprom
+
+import (
+	"context"
+	"github.com/inexio/snmpsim-restapi-go-client"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+DefaultNamespace is the namespace of all metrics if Options.Namespace is not set.
+*/
+const DefaultNamespace = "snmpsim"
+
+/*
+DefaultTimeout is the timeout of a scrape if Options.Timeout is not set.
+*/
+const DefaultTimeout = 10 * time.Second
+
+/*
+DefaultMaxCombinations is the maximum number of combinations of label values if Options.MaxCombinations is not set.
+*/
+const DefaultMaxCombinations = 100
+
+/*
+Options controls which metrics a Collector exports.
+*/
+type Options struct {
+	//Namespace is the prefix of all metric names. DefaultNamespace is used if it is empty.
+	Namespace string
+	//PacketLabels are packet filters of the metrics api, e.g. "local_address" or "protocol". The packet counters are
+	//exported for every combination of their values with activity. Without labels the counters of all packets are exported.
+	//Every combination is requested separately on each scrape, so labels with many values like "peer_address" multiply
+	//the number of requests, see MaxCombinations.
+	PacketLabels []string
+	//MessageLabels are message filters of the metrics api which are used like the PacketLabels for the message and variation counters.
+	MessageLabels []string
+	//Timeout is the timeout of all requests of a scrape. DefaultTimeout is used if it is 0.
+	Timeout time.Duration
+	//MaxCombinations limits the number of combinations of the values of the PacketLabels and of the MessageLabels.
+	//A scrape fails if there are more combinations. DefaultMaxCombinations is used if it is 0.
+	MaxCombinations int
+}
+
+/*
+Collector is a prometheus.Collector which exports the packet, message, variation and process metrics of the metrics api.
+*/
+type Collector struct {
+	client *snmpsimclient.MetricsClient
+	opts   Options
+
+	up *prometheus.Desc
+
+	packetsTotal          *prometheus.Desc
+	packetParseFailures   *prometheus.Desc
+	packetAuthFailures    *prometheus.Desc
+	packetContextFailures *prometheus.Desc
+	messagePdus           *prometheus.Desc
+	messageVarBinds       *prometheus.Desc
+	messageFailures       *prometheus.Desc
+	variationCalls        *prometheus.Desc
+	variationFailures     *prometheus.Desc
+	processCpu            *prometheus.Desc
+	processMemory         *prometheus.Desc
+	processFiles          *prometheus.Desc
+	processExits          *prometheus.Desc
+	processChanges        *prometheus.Desc
+	processRuntimeSeconds *prometheus.Desc
+}
+
+/*
+NewCollector creates a new Collector which requests the metrics from the given client.
+The labels of the options are validated against the filters offered by the api on every scrape.
+*/
+func NewCollector(client *snmpsimclient.MetricsClient, opts Options) (*Collector, error) {
+	if client == nil {
+		return nil, errors.New("invalid client")
+	}
+	if opts.Timeout < 0 {
+		return nil, errors.New("invalid timeout")
+	}
+	if opts.MaxCombinations < 0 {
+		return nil, errors.New("invalid max combinations")
+	}
+	if opts.Namespace == "" {
+		opts.Namespace = DefaultNamespace
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.MaxCombinations == 0 {
+		opts.MaxCombinations = DefaultMaxCombinations
+	}
+	if err := checkLabels(opts.PacketLabels); err != nil {
+		return nil, errors.Wrap(err, "invalid packet labels")
+	}
+	if err := checkLabels(opts.MessageLabels); err != nil {
+		return nil, errors.Wrap(err, "invalid message labels")
+	}
+
+	desc := func(name, help string, labels []string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(opts.Namespace, "", name), help, labels, nil)
+	}
+	variationLabels := append(append([]string(nil), opts.MessageLabels...), "variation")
+	processLabels := []string{"id", "path"}
+	return &Collector{
+		client: client,
+		opts:   opts,
+
+		up: desc("up", "Whether the last scrape of the metrics api was successful.", nil),
+
+		packetsTotal:          desc("packets_total", "Number of received packets.", opts.PacketLabels),
+		packetParseFailures:   desc("packet_parse_failures_total", "Number of packets which could not be parsed.", opts.PacketLabels),
+		packetAuthFailures:    desc("packet_auth_failures_total", "Number of packets which failed authentication.", opts.PacketLabels),
+		packetContextFailures: desc("packet_context_failures_total", "Number of packets with an unknown context.", opts.PacketLabels),
+		messagePdus:           desc("message_pdus_total", "Number of processed pdus.", opts.MessageLabels),
+		messageVarBinds:       desc("message_var_binds_total", "Number of processed variable bindings.", opts.MessageLabels),
+		messageFailures:       desc("message_failures_total", "Number of messages which could not be processed.", opts.MessageLabels),
+		variationCalls:        desc("variation_calls_total", "Number of calls of a variation module.", variationLabels),
+		variationFailures:     desc("variation_failures_total", "Number of failed calls of a variation module.", variationLabels),
+		processCpu:            desc("process_cpu", "Cpu usage of a simulator process.", processLabels),
+		processMemory:         desc("process_memory", "Memory usage of a simulator process.", processLabels),
+		processFiles:          desc("process_open_files", "Number of files opened by a simulator process.", processLabels),
+		processExits:          desc("process_exits_total", "Number of exits of a simulator process.", processLabels),
+		processChanges:        desc("process_changes_total", "Number of changes of a simulator process.", processLabels),
+		processRuntimeSeconds: desc("process_runtime_seconds", "Runtime of a simulator process.", processLabels),
+	}, nil
+}
+
+/*
+Describe sends the descriptions of all metrics of the collector.
+*/
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		c.up, c.packetsTotal, c.packetParseFailures, c.packetAuthFailures, c.packetContextFailures,
+		c.messagePdus, c.messageVarBinds, c.messageFailures, c.variationCalls, c.variationFailures,
+		c.processCpu, c.processMemory, c.processFiles, c.processExits, c.processChanges, c.processRuntimeSeconds,
+	} {
+		ch <- desc
+	}
+}
+
+/*
+Collect requests the metrics from the api and sends them. If one of the requests fails, only the up metric is sent with the value 0.
+*/
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.opts.Timeout)
+	defer cancel()
+
+	collectors := []func(context.Context) ([]prometheus.Metric, error){c.collectPackets, c.collectMessages, c.collectProcesses}
+	results := make([][]prometheus.Metric, len(collectors))
+	errs := make([]error, len(collectors))
+	var wg sync.WaitGroup
+	for i, collect := range collectors {
+		wg.Add(1)
+		go func(i int, collect func(context.Context) ([]prometheus.Metric, error)) {
+			defer wg.Done()
+			results[i], errs[i] = collect(ctx)
+		}(i, collect)
+	}
+	wg.Wait()
+
+	//metrics are only sent if all requests succeeded, so a failed scrape does not result in partial series
+	up := 1.0
+	var metrics []prometheus.Metric
+	for i := range collectors {
+		if errs[i] != nil {
+			up = 0
+			metrics = nil
+			break
+		}
+		metrics = append(metrics, results[i]...)
+	}
+	ch <- prometheus.MustNewConstMetric(c.up, prometheus.GaugeValue, up)
+	for _, metric := range metrics {
+		ch <- metric
+	}
+}
+
+//collectPackets creates the packet metrics of all combinations of label values
+func (c *Collector) collectPackets(ctx context.Context) ([]prometheus.Metric, error) {
+	combinations, err := c.combinations(ctx, c.opts.PacketLabels, func(ctx context.Context) ([]snmpsimclient.MetricsFilter, error) {
+		return c.client.FilterCatalog().PacketFiltersContext(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var metrics []prometheus.Metric
+	for _, values := range combinations {
+		packets, err := c.client.GetPacketsContext(ctx, filterMap(c.opts.PacketLabels, values))
+		if err != nil {
+			return nil, err
+		}
+		if len(values) != 0 && value(packets.Total) == 0 {
+			continue
+		}
+		metrics = append(metrics,
+			counter(c.packetsTotal, packets.Total, values),
+			counter(c.packetParseFailures, packets.ParseFailures, values),
+			counter(c.packetAuthFailures, packets.AuthFailures, values),
+			counter(c.packetContextFailures, packets.ContextFailures, values),
+		)
+	}
+	return metrics, nil
+}
+
+//collectMessages creates the message and variation metrics of all combinations of label values
+func (c *Collector) collectMessages(ctx context.Context) ([]prometheus.Metric, error) {
+	combinations, err := c.combinations(ctx, c.opts.MessageLabels, func(ctx context.Context) ([]snmpsimclient.MetricsFilter, error) {
+		return c.client.FilterCatalog().MessageFiltersContext(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var metrics []prometheus.Metric
+	for _, values := range combinations {
+		messages, err := c.client.GetMessagesContext(ctx, filterMap(c.opts.MessageLabels, values))
+		if err != nil {
+			return nil, err
+		}
+		if len(values) != 0 && value(messages.Pdus) == 0 {
+			continue
+		}
+		metrics = append(metrics,
+			counter(c.messagePdus, messages.Pdus, values),
+			counter(c.messageVarBinds, messages.VarBinds, values),
+			counter(c.messageFailures, messages.Failures, values),
+		)
+		for _, variation := range messages.Variations {
+			if variation.Name == nil {
+				continue
+			}
+			variationValues := append(append([]string(nil), values...), *variation.Name)
+			metrics = append(metrics,
+				counter(c.variationCalls, variation.Total, variationValues),
+				counter(c.variationFailures, variation.Failures, variationValues),
+			)
+		}
+	}
+	return metrics, nil
+}
+
+//collectProcesses creates the metrics of all simulator processes
+func (c *Collector) collectProcesses(ctx context.Context) ([]prometheus.Metric, error) {
+	processes, err := c.client.GetProcessesContext(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	var metrics []prometheus.Metric
+	for _, p := range processes {
+		values := []string{strconv.Itoa(p.Id), p.Path}
+		metrics = append(metrics,
+			prometheus.MustNewConstMetric(c.processCpu, prometheus.GaugeValue, float64(p.Cpu), values...),
+			prometheus.MustNewConstMetric(c.processMemory, prometheus.GaugeValue, float64(p.Memory), values...),
+			prometheus.MustNewConstMetric(c.processFiles, prometheus.GaugeValue, float64(p.Files), values...),
+			prometheus.MustNewConstMetric(c.processExits, prometheus.CounterValue, float64(p.Exits), values...),
+			prometheus.MustNewConstMetric(c.processChanges, prometheus.CounterValue, float64(p.Changes), values...),
+			prometheus.MustNewConstMetric(c.processRuntimeSeconds, prometheus.GaugeValue, float64(p.Runtime), values...),
+		)
+	}
+	return metrics, nil
+}
+
+//combinations returns all combinations of the possible values of the labels, without labels there is one empty combination
+func (c *Collector) combinations(ctx context.Context, labels []string, filters func(context.Context) ([]snmpsimclient.MetricsFilter, error)) ([][]string, error) {
+	if len(labels) == 0 {
+		return [][]string{nil}, nil
+	}
+	offered, err := filters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	possibleValues := make(map[string][]string)
+	for _, filter := range offered {
+		possibleValues[filter.Name] = filter.Values
+	}
+
+	//the number of combinations is checked before they are built, each of them costs a request per scrape
+	count := 1
+	for _, label := range labels {
+		values, ok := possibleValues[label]
+		if !ok {
+			return nil, errors.New("label '" + label + "' is not a filter of the metrics api")
+		}
+		if count *= len(values); count > c.opts.MaxCombinations {
+			return nil, errors.New("the values of the labels " + strings.Join(labels, ", ") + " result in more than " +
+				strconv.Itoa(c.opts.MaxCombinations) + " combinations")
+		}
+	}
+
+	combinations := [][]string{nil}
+	for _, label := range labels {
+		values := possibleValues[label]
+		var next [][]string
+		for _, combination := range combinations {
+			for _, v := range values {
+				next = append(next, append(append([]string(nil), combination...), v))
+			}
+		}
+		combinations = next
+	}
+	return combinations, nil
+}
+
+//checkLabels returns an error if a label is empty or given twice
+func checkLabels(labels []string) error {
+	seen := make(map[string]bool)
+	for _, label := range labels {
+		if label == "" || seen[label] {
+			return errors.New("empty or duplicate label '" + label + "'")
+		}
+		seen[label] = true
+	}
+	return nil
+}
+
+//filterMap creates the filters of a request from the labels and their values
+func filterMap(labels, values []string) map[string]string {
+	if len(labels) == 0 {
+		return nil
+	}
+	filters := make(map[string]string)
+	for i, label := range labels {
+		filters[label] = values[i]
+	}
+	return filters
+}
+
+func counter(desc *prometheus.Desc, v *int64, labelValues []string) prometheus.Metric {
+	return prometheus.MustNewConstMetric(desc, prometheus.CounterValue, float64(value(v)), labelValues...)
+}
+
+func value(v *int64) int64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
@@ -0,0 +1,96 @@
+package snmpsimprom_test
+
+import (
+	"github.com/inexio/snmpsim-restapi-go-client"
+	"github.com/inexio/snmpsim-restapi-go-client/snmpsimprom"
+	"github.com/inexio/snmpsim-restapi-go-client/snmpsimtest"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestCollector(t *testing.T) {
+	server := snmpsimtest.NewServer()
+	defer server.Close()
+	server.AddPacketActivity(snmpsimtest.PacketActivity{
+		Labels: map[string]string{"local_address": "127.0.0.1:1161", "protocol": "udpv4"},
+		Total:  10,
+	})
+	server.AddPacketActivity(snmpsimtest.PacketActivity{
+		Labels:       map[string]string{"local_address": "127.0.0.1:1162", "protocol": "udpv4"},
+		Total:        5,
+		AuthFailures: 1,
+	})
+	server.AddMessageActivity(snmpsimtest.MessageActivity{
+		Labels:     map[string]string{"context_name": "public"},
+		Pdus:       3,
+		VarBinds:   6,
+		Variations: []snmpsimtest.VariationActivity{{Name: "numeric", Total: 2, Failures: 1}},
+	})
+
+	client, err := snmpsimclient.NewMetricsClient(server.URL)
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+	collector, err := snmpsimprom.NewCollector(client, snmpsimprom.Options{
+		PacketLabels:  []string{"local_address", "protocol"},
+		MessageLabels: []string{"context_name"},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	//combinations of label values without activity are not exported
+	err = testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP snmpsim_up Whether the last scrape of the metrics api was successful.
+# TYPE snmpsim_up gauge
+snmpsim_up 1
+# HELP snmpsim_packets_total Number of received packets.
+# TYPE snmpsim_packets_total counter
+snmpsim_packets_total{local_address="127.0.0.1:1161",protocol="udpv4"} 10
+snmpsim_packets_total{local_address="127.0.0.1:1162",protocol="udpv4"} 5
+# HELP snmpsim_packet_auth_failures_total Number of packets which failed authentication.
+# TYPE snmpsim_packet_auth_failures_total counter
+snmpsim_packet_auth_failures_total{local_address="127.0.0.1:1161",protocol="udpv4"} 0
+snmpsim_packet_auth_failures_total{local_address="127.0.0.1:1162",protocol="udpv4"} 1
+# HELP snmpsim_message_pdus_total Number of processed pdus.
+# TYPE snmpsim_message_pdus_total counter
+snmpsim_message_pdus_total{context_name="public"} 3
+# HELP snmpsim_variation_failures_total Number of failed calls of a variation module.
+# TYPE snmpsim_variation_failures_total counter
+snmpsim_variation_failures_total{context_name="public",variation="numeric"} 1
+`), "snmpsim_up", "snmpsim_packets_total", "snmpsim_packet_auth_failures_total", "snmpsim_message_pdus_total", "snmpsim_variation_failures_total")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, testutil.CollectAndCount(collector, "snmpsim_process_open_files"), "process metrics are missing")
+
+	//without labels the counters of all activity are exported
+	collector, err = snmpsimprom.NewCollector(client, snmpsimprom.Options{Namespace: "sim"})
+	if assert.NoError(t, err) {
+		err = testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP sim_packets_total Number of received packets.
+# TYPE sim_packets_total counter
+sim_packets_total 15
+`), "sim_packets_total")
+		assert.NoError(t, err)
+	}
+
+	//labels which are no filters of the api and failed requests result in up 0 without other metrics
+	collector, err = snmpsimprom.NewCollector(client, snmpsimprom.Options{PacketLabels: []string{"unknown"}})
+	if assert.NoError(t, err) {
+		assert.Equal(t, float64(0), testutil.ToFloat64(collector))
+	}
+
+	//scrapes fail instead of sending a request for each of too many combinations of label values
+	collector, err = snmpsimprom.NewCollector(client, snmpsimprom.Options{PacketLabels: []string{"local_address", "protocol"}, MaxCombinations: 1})
+	if assert.NoError(t, err) {
+		assert.Equal(t, float64(0), testutil.ToFloat64(collector))
+	}
+
+	_, err = snmpsimprom.NewCollector(client, snmpsimprom.Options{MaxCombinations: -1})
+	assert.Error(t, err, "negative max combinations were accepted")
+	_, err = snmpsimprom.NewCollector(client, snmpsimprom.Options{PacketLabels: []string{"protocol", "protocol"}})
+	assert.Error(t, err, "duplicate label was accepted")
+	_, err = snmpsimprom.NewCollector(nil, snmpsimprom.Options{})
+	assert.Error(t, err, "missing client was accepted")
+}
@@ -0,0 +1,386 @@
+/*
+Package snmpsimrecorder records the data of a live SNMP agent into snmprec files, which can be uploaded to the data root
+of snmpsim right away:
+
+	recorder, err := snmpsimrecorder.NewRecorder(snmpsimrecorder.Options{
+		Target:    "192.0.2.1",
+		Community: "public",
+		Subtrees:  []string{"1.3.6.1.2.1"},
+	})
+	result, err := recorder.Upload(ctx, client, "lab/device.snmprec")
+*/
+package snmpsimrecorder
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"github.com/inexio/snmpsim-restapi-go-client"
+	"github.com/inexio/snmpsim-restapi-go-client/snmprec"
+	"github.com/pkg/errors"
+	"github.com/soniah/gosnmp"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	//DefaultPort is the port of the agent if the target has no port
+	DefaultPort = 161
+	//DefaultBulkSize is the max-repetitions of GETBULK requests if Options.BulkSize is not set
+	DefaultBulkSize = 25
+	//DefaultTimeout is the timeout of a request if Options.Timeout is not set
+	DefaultTimeout = 2 * time.Second
+)
+
+/*
+DefaultSubtrees are the subtrees which are walked if Options.Subtrees is empty.
+*/
+var DefaultSubtrees = []string{"1.3.6.1"}
+
+/*
+Version is a SNMP version.
+*/
+type Version string
+
+const (
+	//Version1 walks the agent with GETNEXT requests of SNMP v1
+	Version1 Version = "1"
+	//Version2c walks the agent with GETBULK requests of SNMP v2c
+	Version2c Version = "2c"
+	//Version3 walks the agent with GETBULK requests of SNMP v3
+	Version3 Version = "3"
+)
+
+/*
+Options configures how a Recorder walks an agent.
+*/
+type Options struct {
+	//Target is the address of the agent, e.g. "192.0.2.1" or "192.0.2.1:1161". DefaultPort is used if it has no port.
+	Target string
+	//Version is the SNMP version. Version2c is used if it is empty.
+	Version Version
+	//Community is the community of SNMP v1 and v2c requests, "public" is used if it is empty.
+	Community string
+	//V3 contains the credentials of SNMP v3 requests.
+	V3 V3Options
+	//Subtrees are the OIDs of the subtrees which are walked. DefaultSubtrees are walked if it is empty.
+	Subtrees []string
+	//BulkSize is the max-repetitions of GETBULK requests. DefaultBulkSize is used if it is 0.
+	BulkSize uint8
+	//Retries is the number of retries of a request which timed out.
+	Retries int
+	//Timeout is the timeout of a request. DefaultTimeout is used if it is 0.
+	Timeout time.Duration
+}
+
+/*
+V3Options contains the USM credentials of SNMP v3 requests. Authentication is disabled if AuthProtocol is not set and
+privacy is disabled if PrivProtocol is not set.
+*/
+type V3Options struct {
+	User string
+	//AuthProtocol is gosnmp.MD5 or gosnmp.SHA.
+	AuthProtocol   gosnmp.SnmpV3AuthProtocol
+	AuthPassphrase string
+	//PrivProtocol is gosnmp.DES or gosnmp.AES.
+	PrivProtocol   gosnmp.SnmpV3PrivProtocol
+	PrivPassphrase string
+	ContextName    string
+}
+
+/*
+Result is the result of a recording.
+*/
+type Result struct {
+	//File contains the recorded values sorted by OID.
+	File *snmprec.File
+	//Skipped are the OIDs whose values could not be recorded because their type can not be written to snmprec files.
+	Skipped []string
+}
+
+/*
+Recorder walks a SNMP agent and records its values in the snmprec format.
+*/
+type Recorder struct {
+	opts Options
+	host string
+	port uint16
+}
+
+/*
+NewRecorder creates a recorder with the given options.
+*/
+func NewRecorder(opts Options) (*Recorder, error) {
+	r := &Recorder{opts: opts, port: DefaultPort}
+	r.host = strings.TrimSpace(opts.Target)
+	if host, port, err := net.SplitHostPort(r.host); err == nil {
+		p, err := strconv.ParseUint(port, 10, 16)
+		if err != nil {
+			return nil, errors.New("invalid port '" + port + "'")
+		}
+		r.host, r.port = host, uint16(p)
+	}
+	if r.host == "" {
+		return nil, errors.New("invalid target")
+	}
+
+	switch r.opts.Version {
+	case "":
+		r.opts.Version = Version2c
+	case Version1, Version2c:
+	case Version3:
+		if opts.V3.User == "" {
+			return nil, errors.New("a user is required for SNMP v3")
+		}
+		if opts.V3.PrivProtocol > gosnmp.NoPriv && opts.V3.AuthProtocol <= gosnmp.NoAuth {
+			return nil, errors.New("SNMP v3 privacy requires authentication")
+		}
+	default:
+		return nil, errors.New("unsupported SNMP version '" + string(opts.Version) + "'")
+	}
+	if r.opts.Community == "" {
+		r.opts.Community = "public"
+	}
+
+	if len(r.opts.Subtrees) == 0 {
+		r.opts.Subtrees = DefaultSubtrees
+	}
+	subtrees := make([]string, 0, len(r.opts.Subtrees))
+	for _, subtree := range r.opts.Subtrees {
+		subtree = strings.TrimPrefix(strings.TrimSpace(subtree), ".")
+		if !validOID(subtree) {
+			return nil, errors.New("invalid subtree '" + subtree + "'")
+		}
+		subtrees = append(subtrees, subtree)
+	}
+	r.opts.Subtrees = subtrees
+
+	if r.opts.BulkSize == 0 {
+		r.opts.BulkSize = DefaultBulkSize
+	}
+	if r.opts.Retries < 0 {
+		return nil, errors.New("invalid number of retries")
+	}
+	if r.opts.Timeout == 0 {
+		r.opts.Timeout = DefaultTimeout
+	}
+	return r, nil
+}
+
+/*
+Record walks all subtrees of the agent and returns the recorded values. Values which are found in more than one subtree
+are recorded once.
+*/
+func (r *Recorder) Record(ctx context.Context) (Result, error) {
+	snmp := r.client()
+	if err := snmp.Connect(); err != nil {
+		return Result{}, errors.Wrap(err, "error during snmp connect")
+	}
+	//gosnmp does not support contexts, closing the connection aborts a pending request
+	stop := make(chan struct{})
+	defer func() {
+		close(stop)
+		_ = snmp.Conn.Close()
+	}()
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = snmp.Conn.Close()
+		case <-stop:
+		}
+	}()
+
+	walk := snmp.BulkWalk
+	if r.opts.Version == Version1 {
+		walk = snmp.Walk
+	}
+	var result Result
+	var records []snmprec.Record
+	seen := make(map[string]bool)
+	add := func(pdu gosnmp.SnmpPDU) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		record, ok := convertPDU(pdu)
+		if seen[record.OID] {
+			return nil
+		}
+		seen[record.OID] = true
+		if !ok {
+			result.Skipped = append(result.Skipped, record.OID)
+			return nil
+		}
+		records = append(records, record)
+		return nil
+	}
+	for _, subtree := range r.opts.Subtrees {
+		count := len(records) + len(result.Skipped)
+		err := walk(subtree, add)
+		//a walk of a single value returns nothing, like snmpwalk it is requested with a GET then
+		if err == nil && len(records)+len(result.Skipped) == count {
+			err = get(snmp, subtree, add)
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return Result{}, ctxErr
+		}
+		if err != nil {
+			return Result{}, errors.Wrap(err, "error while walking subtree "+subtree)
+		}
+	}
+	result.File = snmprec.NewFile(records)
+	return result, nil
+}
+
+/*
+Upload records the agent and uploads the recording to the given remote path inside of the data dir, which has to be a
+.snmprec file. The upload fails if a recording already exists at the remote path.
+*/
+func (r *Recorder) Upload(ctx context.Context, client snmpsimclient.ManagementAPI, remotePath string) (Result, error) {
+	if client == nil {
+		return Result{}, errors.New("invalid client")
+	}
+	format, compressed, err := snmpsimclient.RecordingFormatOf(remotePath)
+	if err != nil {
+		return Result{}, err
+	}
+	if format != snmpsimclient.RecordingFormatSnmprec || compressed {
+		return Result{}, errors.New("recordings can only be uploaded as uncompressed snmprec files")
+	}
+
+	result, err := r.Record(ctx)
+	if err != nil {
+		return Result{}, err
+	}
+	content := string(result.File.Bytes())
+	if err = client.UploadRecordFileStringContext(ctx, &content, remotePath); err != nil {
+		return Result{}, errors.Wrap(err, "error while uploading recording")
+	}
+	return result, nil
+}
+
+//get requests the value of the OID and adds it if it exists
+func get(snmp *gosnmp.GoSNMP, oid string, add func(gosnmp.SnmpPDU) error) error {
+	response, err := snmp.Get([]string{oid})
+	if err != nil {
+		return err
+	}
+	if response.Error != gosnmp.NoError {
+		return nil
+	}
+	for _, pdu := range response.Variables {
+		switch pdu.Type {
+		case gosnmp.NoSuchObject, gosnmp.NoSuchInstance, gosnmp.EndOfMibView:
+		default:
+			if err = add(pdu); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+//client creates the gosnmp client of the options
+func (r *Recorder) client() *gosnmp.GoSNMP {
+	snmp := &gosnmp.GoSNMP{
+		Target:         r.host,
+		Port:           r.port,
+		Transport:      "udp",
+		Community:      r.opts.Community,
+		Timeout:        r.opts.Timeout,
+		Retries:        r.opts.Retries,
+		MaxOids:        gosnmp.MaxOids,
+		MaxRepetitions: r.opts.BulkSize,
+	}
+	switch r.opts.Version {
+	case Version1:
+		snmp.Version = gosnmp.Version1
+	case Version2c:
+		snmp.Version = gosnmp.Version2c
+	case Version3:
+		v3 := r.opts.V3
+		snmp.Version = gosnmp.Version3
+		snmp.SecurityModel = gosnmp.UserSecurityModel
+		snmp.ContextName = v3.ContextName
+		snmp.MsgFlags = gosnmp.NoAuthNoPriv
+		params := &gosnmp.UsmSecurityParameters{
+			UserName:               v3.User,
+			AuthenticationProtocol: gosnmp.NoAuth,
+			PrivacyProtocol:        gosnmp.NoPriv,
+		}
+		if v3.AuthProtocol > gosnmp.NoAuth {
+			snmp.MsgFlags = gosnmp.AuthNoPriv
+			params.AuthenticationProtocol = v3.AuthProtocol
+			params.AuthenticationPassphrase = v3.AuthPassphrase
+		}
+		if v3.PrivProtocol > gosnmp.NoPriv {
+			snmp.MsgFlags = gosnmp.AuthPriv
+			params.PrivacyProtocol = v3.PrivProtocol
+			params.PrivacyPassphrase = v3.PrivPassphrase
+		}
+		snmp.SecurityParameters = params
+	}
+	return snmp
+}
+
+//convertPDU converts a value received from the agent into a record, ok is false if the type of the value is not
+//supported by snmprec files
+func convertPDU(pdu gosnmp.SnmpPDU) (record snmprec.Record, ok bool) {
+	oid := strings.TrimPrefix(pdu.Name, ".")
+	switch pdu.Type {
+	case gosnmp.Integer:
+		if value, ok := pdu.Value.(int); ok {
+			return snmprec.Record{OID: oid, Tag: snmprec.Integer32, Value: strconv.Itoa(value)}, true
+		}
+	case gosnmp.OctetString:
+		if value, ok := pdu.Value.([]byte); ok {
+			return snmprec.NewOctetString(oid, value), true
+		}
+	case gosnmp.Null:
+		return snmprec.Record{OID: oid, Tag: snmprec.Null}, true
+	case gosnmp.ObjectIdentifier:
+		if value, ok := pdu.Value.(string); ok {
+			return snmprec.Record{OID: oid, Tag: snmprec.ObjectIdentifier, Value: strings.TrimPrefix(value, ".")}, true
+		}
+	case gosnmp.IPAddress:
+		//snmprec files only support IPv4 addresses
+		if value, ok := pdu.Value.(string); ok && net.ParseIP(value).To4() != nil {
+			return snmprec.Record{OID: oid, Tag: snmprec.IpAddress, Value: value}, true
+		}
+	case gosnmp.Counter32, gosnmp.Gauge32, gosnmp.TimeTicks, gosnmp.Counter64, gosnmp.Uinteger32:
+		tag := snmprec.Tag(pdu.Type)
+		if pdu.Type == gosnmp.Uinteger32 {
+			//the obsolete UInteger32 is recorded as its successor Unsigned32, which shares the tag of Gauge32
+			tag = snmprec.Gauge32
+		}
+		return snmprec.Record{OID: oid, Tag: tag, Value: gosnmp.ToBigInt(pdu.Value).String()}, true
+	case gosnmp.OpaqueFloat:
+		//gosnmp decodes floats wrapped into opaque values, they are encoded again like net-snmp does
+		if value, ok := pdu.Value.(float32); ok {
+			b := binary.BigEndian.AppendUint32([]byte{0x9f, 0x78, 0x04}, math.Float32bits(value))
+			return snmprec.Record{OID: oid, Tag: snmprec.Opaque, Hex: true, Value: hex.EncodeToString(b)}, true
+		}
+	case gosnmp.OpaqueDouble:
+		if value, ok := pdu.Value.(float64); ok {
+			b := binary.BigEndian.AppendUint64([]byte{0x9f, 0x79, 0x08}, math.Float64bits(value))
+			return snmprec.Record{OID: oid, Tag: snmprec.Opaque, Hex: true, Value: hex.EncodeToString(b)}, true
+		}
+	}
+	return snmprec.Record{OID: oid}, false
+}
+
+//validOID checks whether the OID is numeric and has at least two arcs
+func validOID(oid string) bool {
+	parts := strings.Split(oid, ".")
+	if len(parts) < 2 {
+		return false
+	}
+	for _, part := range parts {
+		if _, err := strconv.ParseUint(part, 10, 64); err != nil {
+			return false
+		}
+	}
+	return true
+}
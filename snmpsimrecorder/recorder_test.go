@@ -0,0 +1,200 @@
+package snmpsimrecorder
+
+import (
+	"context"
+	"github.com/inexio/snmpsim-restapi-go-client"
+	"github.com/inexio/snmpsim-restapi-go-client/snmprec"
+	"github.com/inexio/snmpsim-restapi-go-client/snmpsimtest"
+	"github.com/pkg/errors"
+	"github.com/soniah/gosnmp"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+	"time"
+)
+
+//recording contains all types which can be recorded, sorted like a recording is written
+const recording = `1.3.6.1.2.1.1.1.0|4|Linux router 5.4.0
+1.3.6.1.2.1.1.2.0|6|1.3.6.1.4.1.8072.3.2.10
+1.3.6.1.2.1.1.3.0|67|8843700
+1.3.6.1.2.1.1.5.0|4|router
+1.3.6.1.2.1.2.2.1.2.1|4x|6c6f0a
+1.3.6.1.2.1.2.2.1.5.1|66|4294967295
+1.3.6.1.2.1.2.2.1.6.1|4|
+1.3.6.1.2.1.2.2.1.10.1|65|1234
+1.3.6.1.2.1.2.2.1.13.1|2|-5
+1.3.6.1.2.1.4.20.1.1.127.0.0.1|64|127.0.0.1
+1.3.6.1.2.1.31.1.1.1.6.1|70|18446744073709551615
+1.3.6.1.4.1.2021.10.1.6.1|68x|9f780440490fdb
+1.3.6.1.4.1.2021.10.1.7.1|5|
+`
+
+//unsupported is an opaque value which is not a float and can not be decoded by gosnmp
+const unsupported = "1.3.6.1.4.1.2021.10.1.8.1"
+
+func startAgent(t *testing.T, content string) *snmpsimtest.Agent {
+	file, err := snmprec.ParseString(content)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	agent, err := snmpsimtest.NewAgent("public", file)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(agent.Close)
+	return agent
+}
+
+func TestRecorder_Record(t *testing.T) {
+	agent := startAgent(t, recording+unsupported+"|68|hi\n")
+	agent.SetUser("simulator")
+	ctx := context.Background()
+
+	for _, version := range []Version{Version1, Version2c, Version3} {
+		before := agent.Requests()
+		recorder, err := NewRecorder(Options{Target: agent.Addr, Version: version, BulkSize: 5,
+			V3: V3Options{User: "simulator"}})
+		if !assert.NoError(t, err) {
+			continue
+		}
+		result, err := recorder.Record(ctx)
+		if assert.NoError(t, err, "error while recording with version %s", version) {
+			assert.Equal(t, recording, string(result.File.Bytes()), "version %s", version)
+			assert.Equal(t, []string{unsupported}, result.Skipped)
+			assert.Empty(t, snmprec.Lint(result.File))
+		}
+		//SNMP v1 needs a GETNEXT for every value, GETBULK requests return 5 values at once and SNMP v3 discovers the
+		//engine id of the agent with an additional request
+		requests := agent.Requests() - before
+		switch version {
+		case Version1:
+			assert.Equal(t, 15, requests)
+		case Version2c:
+			assert.Equal(t, 3, requests)
+		case Version3:
+			assert.Equal(t, 4, requests)
+		}
+	}
+
+	//overlapping subtrees are recorded once, a single value is requested with a GET
+	recorder, err := NewRecorder(Options{Target: agent.Host + ":" + strconv.Itoa(int(agent.Port)),
+		Subtrees: []string{".1.3.6.1.2.1.2", "1.3.6.1.2.1.2.2.1.2", "1.3.6.1.2.1.1.5.0"}})
+	if assert.NoError(t, err) {
+		result, err := recorder.Record(ctx)
+		if assert.NoError(t, err) {
+			assert.Equal(t, `1.3.6.1.2.1.1.5.0|4|router
+1.3.6.1.2.1.2.2.1.2.1|4x|6c6f0a
+1.3.6.1.2.1.2.2.1.5.1|66|4294967295
+1.3.6.1.2.1.2.2.1.6.1|4|
+1.3.6.1.2.1.2.2.1.10.1|65|1234
+1.3.6.1.2.1.2.2.1.13.1|2|-5
+`, string(result.File.Bytes()))
+		}
+	}
+
+	//agents do not answer requests with another community
+	recorder, err = NewRecorder(Options{Target: agent.Addr, Community: "private", Timeout: 50 * time.Millisecond, Retries: 1})
+	if assert.NoError(t, err) {
+		before := agent.Requests()
+		_, err = recorder.Record(ctx)
+		assert.Error(t, err)
+		assert.Equal(t, 2, agent.Requests()-before, "the request was not retried")
+	}
+
+	//agents do not answer SNMP v3 requests of another user
+	recorder, err = NewRecorder(Options{Target: agent.Addr, Version: Version3, V3: V3Options{User: "other"},
+		Timeout: 50 * time.Millisecond})
+	if assert.NoError(t, err) {
+		_, err = recorder.Record(ctx)
+		assert.Error(t, err)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	recorder, err = NewRecorder(Options{Target: agent.Addr})
+	if assert.NoError(t, err) {
+		_, err = recorder.Record(canceled)
+		assert.Equal(t, context.Canceled, err)
+	}
+}
+
+func TestRecorder_Upload(t *testing.T) {
+	agent := startAgent(t, recording)
+	server := snmpsimtest.NewServer()
+	defer server.Close()
+	client, err := snmpsimclient.NewManagementClient(server.URL, snmpsimclient.WithRecordFileValidation())
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+
+	recorder, err := NewRecorder(Options{Target: agent.Addr})
+	if !assert.NoError(t, err) {
+		return
+	}
+	ctx := context.Background()
+	_, err = recorder.Upload(ctx, client, "lab/router.snmprec")
+	if assert.NoError(t, err) {
+		content, ok := server.Recording("lab/router.snmprec")
+		assert.True(t, ok)
+		assert.Equal(t, recording, string(content))
+	}
+	_, err = recorder.Upload(ctx, client, "lab/router.snmprec")
+	assert.True(t, errors.Is(err, snmpsimclient.ErrConflict), "unexpected error %v", err)
+	_, err = recorder.Upload(ctx, client, "lab/router.snmpwalk")
+	assert.Error(t, err)
+	_, err = recorder.Upload(ctx, nil, "lab/other.snmprec")
+	assert.Error(t, err)
+}
+
+func TestNewRecorder(t *testing.T) {
+	recorder, err := NewRecorder(Options{Target: "192.0.2.1"})
+	if assert.NoError(t, err) {
+		snmp := recorder.client()
+		assert.Equal(t, "192.0.2.1", snmp.Target)
+		assert.Equal(t, uint16(DefaultPort), snmp.Port)
+		assert.Equal(t, gosnmp.Version2c, snmp.Version)
+		assert.Equal(t, "public", snmp.Community)
+		assert.Equal(t, uint8(DefaultBulkSize), snmp.MaxRepetitions)
+		assert.Equal(t, DefaultTimeout, snmp.Timeout)
+		assert.Equal(t, DefaultSubtrees, recorder.opts.Subtrees)
+	}
+
+	recorder, err = NewRecorder(Options{Target: "[2001:db8::1]:1161", Version: Version3, V3: V3Options{
+		User:           "simulator",
+		AuthProtocol:   gosnmp.SHA,
+		AuthPassphrase: "auctoritas",
+		PrivProtocol:   gosnmp.AES,
+		PrivPassphrase: "privatus",
+		ContextName:    "device",
+	}})
+	if assert.NoError(t, err) {
+		snmp := recorder.client()
+		assert.Equal(t, "2001:db8::1", snmp.Target)
+		assert.Equal(t, uint16(1161), snmp.Port)
+		assert.Equal(t, gosnmp.Version3, snmp.Version)
+		assert.Equal(t, gosnmp.AuthPriv, snmp.MsgFlags)
+		assert.Equal(t, "device", snmp.ContextName)
+		params := snmp.SecurityParameters.(*gosnmp.UsmSecurityParameters)
+		assert.Equal(t, "simulator", params.UserName)
+		assert.Equal(t, gosnmp.SHA, params.AuthenticationProtocol)
+		assert.Equal(t, gosnmp.AES, params.PrivacyProtocol)
+	}
+
+	recorder, err = NewRecorder(Options{Target: "192.0.2.1", Version: Version3, V3: V3Options{User: "simulator"}})
+	if assert.NoError(t, err) {
+		assert.Equal(t, gosnmp.NoAuthNoPriv, recorder.client().MsgFlags)
+	}
+
+	for _, opts := range []Options{
+		{},
+		{Target: "192.0.2.1:port"},
+		{Target: "192.0.2.1", Version: "4"},
+		{Target: "192.0.2.1", Version: Version3},
+		{Target: "192.0.2.1", Version: Version3, V3: V3Options{User: "simulator", PrivProtocol: gosnmp.DES}},
+		{Target: "192.0.2.1", Subtrees: []string{"iso.3.6"}},
+		{Target: "192.0.2.1", Retries: -1},
+	} {
+		_, err := NewRecorder(opts)
+		assert.Error(t, err, "%+v", opts)
+	}
+}
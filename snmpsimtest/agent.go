@@ -0,0 +1,665 @@
+package snmpsimtest
+
+import (
+	"bytes"
+	"encoding/hex"
+	"github.com/inexio/snmpsim-restapi-go-client/snmprec"
+	"github.com/pkg/errors"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+//BER tags of the messages handled by the agent
+const (
+	berInteger     = 0x02
+	berOctetString = 0x04
+	berOID         = 0x06
+	berSequence    = 0x30
+	pduGet         = 0xa0
+	pduGetNext     = 0xa1
+	pduResponse    = 0xa2
+	pduGetBulk     = 0xa5
+	pduReport      = 0xa8
+)
+
+const (
+	//noSuchName is the error status of SNMP v1 responses for OIDs without value
+	noSuchName = 2
+	//maxResponseSize limits the size of a response, GETBULK responses are truncated to it
+	maxResponseSize = 60000
+	//version3 is the version of SNMP v3 messages
+	version3 = 3
+	//userSecurityModel is the security model of SNMP v3 messages with USM security parameters
+	userSecurityModel = 3
+	//flagsAuthPriv are the flags of SNMP v3 messages requesting authentication or privacy
+	flagsAuthPriv = 0x03
+)
+
+//agentEngineId is the SNMP v3 engine id of all agents, it is a net-snmp engine id in text format
+var agentEngineId = append([]byte{0x80, 0x00, 0x1f, 0x88, 0x04}, "snmpsimtest"...)
+
+//usmStatsUnknownEngineIds is reported to SNMP v3 requests without engine id, which discover the engine id
+var usmStatsUnknownEngineIds = []uint64{1, 3, 6, 1, 6, 3, 15, 1, 1, 4, 0}
+
+/*
+Agent is a SNMP agent serving the records of a snmprec file like snmpsim does. It answers GET, GETNEXT and GETBULK
+requests of SNMP v1 and v2c, and of SNMP v3 without authentication and privacy if a user was set with SetUser. So it
+can stand in for a simulated or real device in tests:
+
+	agent, err := snmpsimtest.NewAgent("public", file)
+	defer agent.Close()
+
+SNMP v3 authentication, privacy and SET requests are not supported. Requests with another community or user are not
+answered.
+*/
+type Agent struct {
+	//Addr is the address the agent listens on, e.g. "127.0.0.1:40123".
+	Addr string
+	//Host is the host of Addr.
+	Host string
+	//Port is the UDP port of Addr.
+	Port uint16
+
+	community string
+	conn      net.PacketConn
+	records   []agentRecord
+	requests  int64
+	done      chan struct{}
+	started   time.Time
+
+	mtx  sync.Mutex
+	user string
+}
+
+//agentRecord is a record of the agent with its BER encoded value
+type agentRecord struct {
+	oid   []uint64
+	value []byte
+}
+
+/*
+NewAgent starts an agent on a random UDP port of the loopback interface, which serves the records of the file to
+requests with the given community. Records with a variation module are not supported. The caller should call Close
+when finished, to shut it down.
+*/
+func NewAgent(community string, file *snmprec.File) (*Agent, error) {
+	var records []agentRecord
+	for _, r := range file.Records() {
+		if r.Variation != "" {
+			return nil, errors.New("record " + r.OID + ": variation modules are not supported")
+		}
+		oid, ok := parseOID(r.OID)
+		if !ok {
+			return nil, errors.New("record " + r.OID + ": invalid OID")
+		}
+		value, err := encodeValue(r)
+		if err != nil {
+			return nil, errors.Wrap(err, "record "+r.OID)
+		}
+		records = append(records, agentRecord{oid: oid, value: value})
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return compareOIDs(records[i].oid, records[j].oid) < 0
+	})
+	for i := 1; i < len(records); i++ {
+		if compareOIDs(records[i-1].oid, records[i].oid) == 0 {
+			return nil, errors.New("duplicate record " + formatOID(records[i].oid))
+		}
+	}
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		return nil, errors.Wrap(err, "error while listening for snmp requests")
+	}
+	addr := conn.LocalAddr().(*net.UDPAddr)
+	a := &Agent{
+		Addr:      addr.String(),
+		Host:      addr.IP.String(),
+		Port:      uint16(addr.Port),
+		community: community,
+		conn:      conn,
+		records:   records,
+		done:      make(chan struct{}),
+		started:   time.Now(),
+	}
+	go a.serve()
+	return a, nil
+}
+
+/*
+Requests returns the number of requests received by the agent, including requests which were not answered.
+*/
+func (a *Agent) Requests() int {
+	return int(atomic.LoadInt64(&a.requests))
+}
+
+/*
+SetUser makes the agent answer SNMP v3 requests of the given user which use neither authentication nor privacy.
+An empty user disables SNMP v3 again.
+*/
+func (a *Agent) SetUser(user string) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	a.user = user
+}
+
+/*
+Close shuts down the agent.
+*/
+func (a *Agent) Close() {
+	_ = a.conn.Close()
+	<-a.done
+}
+
+func (a *Agent) serve() {
+	defer close(a.done)
+	buf := make([]byte, 65535)
+	for {
+		n, addr, err := a.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		atomic.AddInt64(&a.requests, 1)
+		if response := a.handle(buf[:n]); response != nil {
+			_, _ = a.conn.WriteTo(response, addr)
+		}
+	}
+}
+
+//handle returns the response to a request, or nil if the request is not answered
+func (a *Agent) handle(packet []byte) []byte {
+	tag, message, _, ok := readTLV(packet)
+	if !ok || tag != berSequence {
+		return nil
+	}
+	tag, version, message, ok := readTLV(message)
+	if !ok || tag != berInteger || len(version) != 1 {
+		return nil
+	}
+	if version[0] == version3 {
+		return a.handleV3(message)
+	}
+	if version[0] > 1 {
+		return nil
+	}
+	tag, community, message, ok := readTLV(message)
+	if !ok || tag != berOctetString || string(community) != a.community {
+		return nil
+	}
+	pduType, pdu, _, ok := readTLV(message)
+	if !ok {
+		return nil
+	}
+	response := a.respond(pduType, pdu, version[0] == 0)
+	if response == nil {
+		return nil
+	}
+	return tlv(berSequence, bytes.Join([][]byte{
+		tlv(berInteger, version),
+		tlv(berOctetString, community),
+		response,
+	}, nil))
+}
+
+//handleV3 returns the response to a SNMP v3 message without version, or nil if the message is not answered
+func (a *Agent) handleV3(message []byte) []byte {
+	a.mtx.Lock()
+	user := a.user
+	a.mtx.Unlock()
+	if user == "" {
+		return nil
+	}
+
+	tag, header, message, ok := readTLV(message)
+	if !ok || tag != berSequence {
+		return nil
+	}
+	var msgId, maxSize, flags, securityModel []byte
+	for _, field := range []*[]byte{&msgId, &maxSize, &flags, &securityModel} {
+		if _, *field, header, ok = readTLV(header); !ok {
+			return nil
+		}
+	}
+	if len(flags) != 1 || flags[0]&flagsAuthPriv != 0 || decodeInt(securityModel) != userSecurityModel {
+		return nil
+	}
+
+	tag, securityParameters, message, ok := readTLV(message)
+	if !ok || tag != berOctetString {
+		return nil
+	}
+	if tag, securityParameters, _, ok = readTLV(securityParameters); !ok || tag != berSequence {
+		return nil
+	}
+	var engineId, boots, engineTime, userName []byte
+	for _, field := range []*[]byte{&engineId, &boots, &engineTime, &userName} {
+		if _, *field, securityParameters, ok = readTLV(securityParameters); !ok {
+			return nil
+		}
+	}
+
+	tag, scopedPdu, _, ok := readTLV(message)
+	if !ok || tag != berSequence {
+		return nil
+	}
+	var contextEngineId, contextName []byte
+	for _, field := range []*[]byte{&contextEngineId, &contextName} {
+		if _, *field, scopedPdu, ok = readTLV(scopedPdu); !ok {
+			return nil
+		}
+	}
+	pduType, pdu, _, ok := readTLV(scopedPdu)
+	if !ok {
+		return nil
+	}
+
+	var response []byte
+	switch {
+	case len(engineId) == 0:
+		//the engine id is discovered with a request without engine id, which is answered with a report
+		tag, requestId, _, ok := readTLV(pdu)
+		if !ok || tag != berInteger {
+			return nil
+		}
+		var body bytes.Buffer
+		body.Write(tlv(berInteger, requestId))
+		body.Write(tlv(berInteger, []byte{0}))
+		body.Write(tlv(berInteger, []byte{0}))
+		body.Write(tlv(berSequence, encodeVarBinds([]agentRecord{{
+			oid:   usmStatsUnknownEngineIds,
+			value: tlv(byte(snmprec.Counter32), encodeUint(1)),
+		}})))
+		response = tlv(pduReport, body.Bytes())
+	case !bytes.Equal(engineId, agentEngineId) || string(userName) != user:
+		return nil
+	default:
+		if response = a.respond(pduType, pdu, false); response == nil {
+			return nil
+		}
+	}
+
+	uptime := int64(time.Since(a.started) / time.Second)
+	return tlv(berSequence, bytes.Join([][]byte{
+		tlv(berInteger, []byte{version3}),
+		tlv(berSequence, bytes.Join([][]byte{
+			tlv(berInteger, msgId),
+			tlv(berInteger, encodeInt(maxResponseSize)),
+			tlv(berOctetString, []byte{0}),
+			tlv(berInteger, []byte{userSecurityModel}),
+		}, nil)),
+		tlv(berOctetString, tlv(berSequence, bytes.Join([][]byte{
+			tlv(berOctetString, agentEngineId),
+			tlv(berInteger, []byte{1}),
+			tlv(berInteger, encodeInt(uptime)),
+			tlv(berOctetString, userName),
+			tlv(berOctetString, nil),
+			tlv(berOctetString, nil),
+		}, nil))),
+		tlv(berSequence, bytes.Join([][]byte{
+			tlv(berOctetString, agentEngineId),
+			tlv(berOctetString, contextName),
+			response,
+		}, nil)),
+	}, nil))
+}
+
+//respond returns the encoded response PDU to a request PDU, or nil if the request is not answered
+func (a *Agent) respond(pduType byte, pdu []byte, v1 bool) []byte {
+	var fields [3][]byte
+	var tag byte
+	var ok bool
+	for i := range fields {
+		if tag, fields[i], pdu, ok = readTLV(pdu); !ok || tag != berInteger {
+			return nil
+		}
+	}
+	tag, varBinds, _, ok := readTLV(pdu)
+	if !ok || tag != berSequence {
+		return nil
+	}
+	var oids [][]uint64
+	for rest := varBinds; len(rest) != 0; {
+		var varBind, name []byte
+		if tag, varBind, rest, ok = readTLV(rest); !ok || tag != berSequence {
+			return nil
+		}
+		if tag, name, _, ok = readTLV(varBind); !ok || tag != berOID {
+			return nil
+		}
+		oids = append(oids, decodeOID(name))
+	}
+
+	var results []agentRecord
+	var errorIndex int
+	switch pduType {
+	case pduGet:
+		results, errorIndex = a.get(oids, v1)
+	case pduGetNext:
+		results, errorIndex = a.getNext(oids, v1)
+	case pduGetBulk:
+		if v1 {
+			return nil
+		}
+		results = a.getBulk(oids, int(decodeInt(fields[1])), int(decodeInt(fields[2])))
+	default:
+		return nil
+	}
+
+	var body bytes.Buffer
+	body.Write(tlv(berInteger, fields[0]))
+	if errorIndex != 0 {
+		//SNMP v1 reports missing values with an error and returns the variable bindings of the request
+		body.Write(tlv(berInteger, encodeInt(noSuchName)))
+		body.Write(tlv(berInteger, encodeInt(int64(errorIndex))))
+		body.Write(tlv(berSequence, varBinds))
+	} else {
+		body.Write(tlv(berInteger, []byte{0}))
+		body.Write(tlv(berInteger, []byte{0}))
+		body.Write(tlv(berSequence, encodeVarBinds(results)))
+	}
+	return tlv(pduResponse, body.Bytes())
+}
+
+//get returns the records of the OIDs. The error index is set for SNMP v1 if an OID has no record.
+func (a *Agent) get(oids [][]uint64, v1 bool) ([]agentRecord, int) {
+	results := make([]agentRecord, 0, len(oids))
+	for i, oid := range oids {
+		j := sort.Search(len(a.records), func(j int) bool {
+			return compareOIDs(a.records[j].oid, oid) >= 0
+		})
+		if j < len(a.records) && compareOIDs(a.records[j].oid, oid) == 0 {
+			results = append(results, a.records[j])
+			continue
+		}
+		if v1 {
+			return nil, i + 1
+		}
+		results = append(results, agentRecord{oid: oid, value: tlv(byte(snmprec.NoSuchObject), nil)})
+	}
+	return results, 0
+}
+
+//getNext returns the records following the OIDs. The error index is set for SNMP v1 if an OID is at the end of the MIB.
+func (a *Agent) getNext(oids [][]uint64, v1 bool) ([]agentRecord, int) {
+	results := make([]agentRecord, 0, len(oids))
+	for i, oid := range oids {
+		next, ok := a.next(oid)
+		if !ok && v1 {
+			return nil, i + 1
+		}
+		results = append(results, next)
+	}
+	return results, 0
+}
+
+//getBulk returns the records following the non repeaters once and the records following the repeaters up to
+//maxRepetitions times, the response is truncated to maxResponseSize
+func (a *Agent) getBulk(oids [][]uint64, nonRepeaters, maxRepetitions int) []agentRecord {
+	if nonRepeaters < 0 {
+		nonRepeaters = 0
+	}
+	if nonRepeaters > len(oids) {
+		nonRepeaters = len(oids)
+	}
+	results, _ := a.getNext(oids[:nonRepeaters], false)
+	repeaters := append([][]uint64(nil), oids[nonRepeaters:]...)
+	size := 0
+	for _, r := range results {
+		size += len(r.value) + len(r.oid)*2
+	}
+	for i := 0; i < maxRepetitions && len(repeaters) != 0; i++ {
+		end := true
+		for j, oid := range repeaters {
+			next, ok := a.next(oid)
+			size += len(next.value) + len(next.oid)*2
+			if size > maxResponseSize && len(results) != 0 {
+				return results
+			}
+			results = append(results, next)
+			repeaters[j] = next.oid
+			end = end && !ok
+		}
+		if end {
+			break
+		}
+	}
+	return results
+}
+
+//next returns the record following the OID, or an endOfMibView if there is none
+func (a *Agent) next(oid []uint64) (agentRecord, bool) {
+	i := sort.Search(len(a.records), func(i int) bool {
+		return compareOIDs(a.records[i].oid, oid) > 0
+	})
+	if i == len(a.records) {
+		return agentRecord{oid: oid, value: tlv(byte(snmprec.EndOfMibView), nil)}, false
+	}
+	return a.records[i], true
+}
+
+func encodeVarBinds(records []agentRecord) []byte {
+	var buf bytes.Buffer
+	for _, r := range records {
+		buf.Write(tlv(berSequence, append(tlv(berOID, encodeOID(r.oid)), r.value...)))
+	}
+	return buf.Bytes()
+}
+
+//encodeValue encodes the value of a record with its BER tag
+func encodeValue(r snmprec.Record) ([]byte, error) {
+	raw := []byte(r.Value)
+	if r.Hex {
+		var err error
+		if raw, err = hex.DecodeString(r.Value); err != nil {
+			return nil, errors.New("invalid hex value")
+		}
+	}
+
+	tag := byte(r.Tag)
+	switch r.Tag {
+	case snmprec.Integer32:
+		value, err := strconv.ParseInt(r.Value, 10, 32)
+		if err != nil {
+			return nil, errors.New("invalid Integer32 value")
+		}
+		return tlv(tag, encodeInt(value)), nil
+	case snmprec.Counter32, snmprec.Gauge32, snmprec.TimeTicks, snmprec.Counter64:
+		bitSize := 32
+		if r.Tag == snmprec.Counter64 {
+			bitSize = 64
+		}
+		value, err := strconv.ParseUint(r.Value, 10, bitSize)
+		if err != nil {
+			return nil, errors.New("invalid " + r.Tag.String() + " value")
+		}
+		return tlv(tag, encodeUint(value)), nil
+	case snmprec.OctetString, snmprec.Opaque:
+		return tlv(tag, raw), nil
+	case snmprec.ObjectIdentifier:
+		oid, ok := parseOID(r.Value)
+		if !ok {
+			return nil, errors.New("invalid ObjectIdentifier value")
+		}
+		return tlv(tag, encodeOID(oid)), nil
+	case snmprec.IpAddress:
+		if !r.Hex {
+			raw = net.ParseIP(r.Value).To4()
+		}
+		if len(raw) != 4 {
+			return nil, errors.New("invalid IpAddress value")
+		}
+		return tlv(tag, raw), nil
+	case snmprec.Null, snmprec.NoSuchObject, snmprec.NoSuchInstance, snmprec.EndOfMibView:
+		return tlv(tag, nil), nil
+	}
+	return nil, errors.New("unsupported tag " + r.Tag.String())
+}
+
+//readTLV reads a BER encoded value and returns its tag, its content and the data following it
+func readTLV(data []byte) (tag byte, value, rest []byte, ok bool) {
+	if len(data) < 2 {
+		return 0, nil, nil, false
+	}
+	length, offset := int(data[1]), 2
+	if length&0x80 != 0 {
+		n := length & 0x7f
+		if n == 0 || n > 3 || len(data) < 2+n {
+			return 0, nil, nil, false
+		}
+		length = 0
+		for _, b := range data[2 : 2+n] {
+			length = length<<8 | int(b)
+		}
+		offset += n
+	}
+	if len(data) < offset+length {
+		return 0, nil, nil, false
+	}
+	return data[0], data[offset : offset+length], data[offset+length:], true
+}
+
+//tlv encodes a value with its tag and length
+func tlv(tag byte, value []byte) []byte {
+	header := []byte{tag}
+	switch length := len(value); {
+	case length < 0x80:
+		header = append(header, byte(length))
+	case length <= 0xff:
+		header = append(header, 0x81, byte(length))
+	case length <= 0xffff:
+		header = append(header, 0x82, byte(length>>8), byte(length))
+	default:
+		header = append(header, 0x83, byte(length>>16), byte(length>>8), byte(length))
+	}
+	return append(header, value...)
+}
+
+//encodeInt encodes a signed integer in the shortest two's complement form
+func encodeInt(value int64) []byte {
+	b := []byte{byte(value)}
+	for value > 0x7f || value < -0x80 {
+		value >>= 8
+		b = append([]byte{byte(value)}, b...)
+	}
+	return b
+}
+
+//encodeUint encodes an unsigned integer, a leading zero byte keeps values with the highest bit set positive
+func encodeUint(value uint64) []byte {
+	var b []byte
+	for {
+		b = append([]byte{byte(value)}, b...)
+		if value >>= 8; value == 0 {
+			break
+		}
+	}
+	if b[0]&0x80 != 0 {
+		b = append([]byte{0}, b...)
+	}
+	return b
+}
+
+func decodeInt(b []byte) int64 {
+	var value int64
+	for i, c := range b {
+		if i == 0 && c&0x80 != 0 {
+			value = -1
+		}
+		value = value<<8 | int64(c)
+	}
+	return value
+}
+
+func encodeOID(oid []uint64) []byte {
+	if len(oid) < 2 {
+		return []byte{0}
+	}
+	b := encodeBase128(nil, oid[0]*40+oid[1])
+	for _, arc := range oid[2:] {
+		b = encodeBase128(b, arc)
+	}
+	return b
+}
+
+func encodeBase128(b []byte, value uint64) []byte {
+	var digits []byte
+	for {
+		digits = append([]byte{byte(value & 0x7f)}, digits...)
+		if value >>= 7; value == 0 {
+			break
+		}
+	}
+	for i := 0; i < len(digits)-1; i++ {
+		digits[i] |= 0x80
+	}
+	return append(b, digits...)
+}
+
+func decodeOID(b []byte) []uint64 {
+	var arcs []uint64
+	var value uint64
+	for _, c := range b {
+		value = value<<7 | uint64(c&0x7f)
+		if c&0x80 != 0 {
+			continue
+		}
+		if arcs == nil {
+			first := value / 40
+			if first > 2 {
+				first = 2
+			}
+			arcs = append(arcs, first, value-first*40)
+		} else {
+			arcs = append(arcs, value)
+		}
+		value = 0
+	}
+	return arcs
+}
+
+func parseOID(oid string) ([]uint64, bool) {
+	parts := strings.Split(strings.TrimPrefix(oid, "."), ".")
+	if len(parts) < 2 {
+		return nil, false
+	}
+	arcs := make([]uint64, 0, len(parts))
+	for _, part := range parts {
+		arc, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return nil, false
+		}
+		arcs = append(arcs, arc)
+	}
+	return arcs, true
+}
+
+func formatOID(oid []uint64) string {
+	parts := make([]string, 0, len(oid))
+	for _, arc := range oid {
+		parts = append(parts, strconv.FormatUint(arc, 10))
+	}
+	return strings.Join(parts, ".")
+}
+
+func compareOIDs(a, b []uint64) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	}
+	return 0
+}
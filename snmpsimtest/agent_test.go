@@ -0,0 +1,98 @@
+package snmpsimtest_test
+
+import (
+	"github.com/inexio/snmpsim-restapi-go-client/snmprec"
+	"github.com/inexio/snmpsim-restapi-go-client/snmpsimtest"
+	"github.com/soniah/gosnmp"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestAgent(t *testing.T) {
+	file, err := snmprec.ParseString("1.3.6.1.2.1.1.5.0|4|router\n1.3.6.1.2.1.1.3.0|67|100\n")
+	if !assert.NoError(t, err) {
+		return
+	}
+	agent, err := snmpsimtest.NewAgent("public", file)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer agent.Close()
+
+	for _, version := range []gosnmp.SnmpVersion{gosnmp.Version1, gosnmp.Version2c} {
+		snmp := &gosnmp.GoSNMP{Target: agent.Host, Port: agent.Port, Community: "public", Version: version, Timeout: time.Second}
+		if !assert.NoError(t, snmp.Connect()) {
+			continue
+		}
+		response, err := snmp.Get([]string{"1.3.6.1.2.1.1.5.0", "1.3.6.1.2.1.1.3.0"})
+		if assert.NoError(t, err) && assert.Len(t, response.Variables, 2) {
+			assert.Equal(t, []byte("router"), response.Variables[0].Value)
+			assert.Equal(t, gosnmp.TimeTicks, response.Variables[1].Type)
+		}
+
+		response, err = snmp.GetNext([]string{"1.3.6.1.2.1.1.5.0"})
+		if assert.NoError(t, err) {
+			if version == gosnmp.Version1 {
+				assert.Equal(t, gosnmp.NoSuchName, response.Error)
+			} else if assert.Len(t, response.Variables, 1) {
+				assert.Equal(t, gosnmp.EndOfMibView, response.Variables[0].Type)
+			}
+		}
+		_ = snmp.Conn.Close()
+	}
+
+	//requests with another community are not answered
+	snmp := &gosnmp.GoSNMP{Target: agent.Host, Port: agent.Port, Community: "private", Version: gosnmp.Version2c,
+		Timeout: 50 * time.Millisecond}
+	if assert.NoError(t, snmp.Connect()) {
+		_, err = snmp.Get([]string{"1.3.6.1.2.1.1.5.0"})
+		assert.Error(t, err)
+		_ = snmp.Conn.Close()
+	}
+
+	//SNMP v3 requests are answered for the user of the agent if they use neither authentication nor privacy
+	v3 := func(user string, flags gosnmp.SnmpV3MsgFlags, auth gosnmp.SnmpV3AuthProtocol) *gosnmp.GoSNMP {
+		return &gosnmp.GoSNMP{Target: agent.Host, Port: agent.Port, Version: gosnmp.Version3, Timeout: 50 * time.Millisecond,
+			SecurityModel: gosnmp.UserSecurityModel, MsgFlags: flags, SecurityParameters: &gosnmp.UsmSecurityParameters{
+				UserName: user, AuthenticationProtocol: auth, AuthenticationPassphrase: "auctoritas"}}
+	}
+	snmp = v3("simulator", gosnmp.NoAuthNoPriv, gosnmp.NoAuth)
+	if assert.NoError(t, snmp.Connect()) {
+		_, err = snmp.Get([]string{"1.3.6.1.2.1.1.5.0"})
+		assert.Error(t, err, "SNMP v3 request was answered without user")
+		_ = snmp.Conn.Close()
+	}
+	agent.SetUser("simulator")
+	if assert.NoError(t, snmp.Connect()) {
+		response, err := snmp.GetBulk([]string{"1.3.6.1.2.1.1"}, 0, 5)
+		if assert.NoError(t, err) && assert.Len(t, response.Variables, 3) {
+			assert.Equal(t, gosnmp.TimeTicks, response.Variables[0].Type)
+			assert.Equal(t, []byte("router"), response.Variables[1].Value)
+			assert.Equal(t, gosnmp.EndOfMibView, response.Variables[2].Type)
+		}
+		_ = snmp.Conn.Close()
+	}
+	for _, snmp := range []*gosnmp.GoSNMP{
+		v3("other", gosnmp.NoAuthNoPriv, gosnmp.NoAuth),
+		v3("simulator", gosnmp.AuthNoPriv, gosnmp.SHA),
+	} {
+		if assert.NoError(t, snmp.Connect()) {
+			_, err = snmp.Get([]string{"1.3.6.1.2.1.1.5.0"})
+			assert.Error(t, err, "SNMP v3 request was answered for %+v", snmp.SecurityParameters)
+			_ = snmp.Conn.Close()
+		}
+	}
+
+	for _, content := range []string{
+		"1.3.6.1.2.1.1.3.0|67:numeric|rate=100\n",
+		"1.3.6.1.2.1.1.3.0|67|-1\n",
+		"1.3.6.1.2.1.1.3.0|67|1\n1.3.6.1.2.1.1.3.0|67|2\n",
+	} {
+		file, err := snmprec.ParseString(content)
+		if assert.NoError(t, err) {
+			_, err = snmpsimtest.NewAgent("public", file)
+			assert.Error(t, err, content)
+		}
+	}
+}
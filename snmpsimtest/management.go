@@ -0,0 +1,585 @@
+package snmpsimtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//kind describes one type of management object
+type kind struct {
+	plural string
+	//attributes contains the json attributes of the object together with their default values
+	attributes []attribute
+	//children contains the kinds which can be linked to objects of this kind
+	children []string
+}
+
+type attribute struct {
+	name         string
+	defaultValue interface{}
+	required     bool
+	values       []string
+}
+
+var (
+	authProtocols = []string{"none", "md5", "sha", "sha224", "sha256", "sha384", "sha512"}
+	privProtocols = []string{"none", "des", "3des", "aes", "aes128", "aes192", "aes256", "aes192blmt", "aes256blmt"}
+	protocols     = []string{"udpv4", "udpv6"}
+)
+
+var kinds = map[string]kind{
+	"lab": {
+		plural:     "labs",
+		attributes: []attribute{{name: "name", required: true}, {name: "power", defaultValue: "off", values: []string{"on", "off"}}},
+		children:   []string{"agent"},
+	},
+	"agent": {
+		plural:     "agents",
+		attributes: []attribute{{name: "name", required: true}, {name: "data_dir", defaultValue: "."}},
+		children:   []string{"engine", "selector"},
+	},
+	"engine": {
+		plural:     "engines",
+		attributes: []attribute{{name: "name", required: true}, {name: "engine_id"}},
+		children:   []string{"endpoint", "user"},
+	},
+	"endpoint": {
+		plural: "endpoints",
+		attributes: []attribute{{name: "name", required: true}, {name: "protocol", defaultValue: "udpv4", values: protocols},
+			{name: "address", required: true}},
+	},
+	"user": {
+		plural: "users",
+		attributes: []attribute{{name: "user", required: true}, {name: "name"},
+			{name: "auth_key", defaultValue: nil}, {name: "auth_proto", defaultValue: "none", values: authProtocols},
+			{name: "priv_key", defaultValue: nil}, {name: "priv_proto", defaultValue: "none", values: privProtocols}},
+	},
+	"selector": {
+		plural:     "selectors",
+		attributes: []attribute{{name: "comment"}, {name: "template", required: true}},
+	},
+	"tag": {
+		plural:     "tags",
+		attributes: []attribute{{name: "name", required: true}, {name: "description"}},
+	},
+}
+
+//taggableKinds are all kinds which can be tagged, in the order they are rendered
+var taggableKinds = []string{"agent", "endpoint", "engine", "lab", "selector", "user"}
+
+//object is a management object of any kind
+type object struct {
+	kind       string
+	id         int
+	attributes map[string]interface{}
+	links      map[string][]int
+	tags       []int
+}
+
+func kindByPlural(plural string) (string, bool) {
+	for name, k := range kinds {
+		if k.plural == plural {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+func (s *Server) serveManagement(w http.ResponseWriter, r *http.Request, segments []string) {
+	if len(segments) == 0 {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if segments[0] == "recordings" {
+		s.serveRecordings(w, r, strings.Join(segments[1:], "/"))
+		return
+	}
+
+	kindName, ok := kindByPlural(segments[0])
+	if !ok {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	switch len(segments) {
+	case 1:
+		switch r.Method {
+		case http.MethodGet:
+			s.listObjects(w, r, kindName)
+		case http.MethodPost:
+			s.createObject(w, r, kindName, nil)
+		default:
+			methodNotAllowed(w)
+		}
+		return
+	case 2:
+		id, err := strconv.Atoi(segments[1])
+		if err != nil {
+			writeError(w, http.StatusNotFound, "invalid id '"+segments[1]+"'")
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			s.getObject(w, kindName, id)
+		case http.MethodPut:
+			s.updateObject(w, r, kindName, id)
+		case http.MethodDelete:
+			s.deleteObject(w, kindName, id)
+		default:
+			methodNotAllowed(w)
+		}
+		return
+	}
+
+	id, err := strconv.Atoi(segments[1])
+	if err != nil {
+		writeError(w, http.StatusNotFound, "invalid id '"+segments[1]+"'")
+		return
+	}
+
+	switch {
+	case kindName == "lab" && len(segments) == 4 && segments[2] == "power":
+		if r.Method != http.MethodPut {
+			methodNotAllowed(w)
+			return
+		}
+		s.setPower(w, id, segments[3])
+	case kindName == "tag" && len(segments) == 3 && segments[2] == "objects":
+		if r.Method != http.MethodDelete {
+			methodNotAllowed(w)
+			return
+		}
+		s.deleteTaggedObjects(w, id)
+	case kindName == "tag" && len(segments) == 3:
+		if _, ok := kinds[segments[2]]; !ok || segments[2] == "tag" || r.Method != http.MethodPost {
+			writeError(w, http.StatusNotFound, "not found")
+			return
+		}
+		s.createObject(w, r, segments[2], &id)
+	case kindName == "tag" && len(segments) == 4:
+		s.serveTagLink(w, r, id, segments[2], segments[3])
+	case len(segments) == 4 && isChildKind(kindName, segments[2]):
+		s.serveLink(w, r, kindName, id, segments[2], segments[3])
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func isChildKind(parent, child string) bool {
+	for _, c := range kinds[parent].children {
+		if c == child {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) listObjects(w http.ResponseWriter, r *http.Request, kindName string) {
+	query := r.URL.Query()
+	list := make([]map[string]interface{}, 0)
+	for _, o := range s.sortedObjects(kindName) {
+		match := true
+		for key, values := range query {
+			if isListParam(key) {
+				continue
+			}
+			value, ok := o.attributes[key]
+			if !ok {
+				//the control plane ignores unknown filters
+				continue
+			}
+			match = match && len(values) > 0 && fmt.Sprint(valueOrEmpty(value)) == values[0]
+		}
+		if match {
+			list = append(list, s.render(o, true))
+		}
+	}
+	list, err := pageList(list, query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *Server) getObject(w http.ResponseWriter, kindName string, id int) {
+	o, ok := s.objects[kindName][id]
+	if !ok {
+		writeError(w, http.StatusNotFound, kindName+" "+strconv.Itoa(id)+" not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.render(o, true))
+}
+
+func (s *Server) createObject(w http.ResponseWriter, r *http.Request, kindName string, tagId *int) {
+	if tagId != nil {
+		if _, ok := s.objects["tag"][*tagId]; !ok {
+			writeError(w, http.StatusNotFound, "tag "+strconv.Itoa(*tagId)+" not found")
+			return
+		}
+	}
+
+	values, err := decodeAttributes(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	o := &object{kind: kindName, attributes: make(map[string]interface{}), links: make(map[string][]int)}
+	for _, a := range kinds[kindName].attributes {
+		o.attributes[a.name] = a.defaultValue
+		if a.defaultValue == nil && a.name != "auth_key" && a.name != "priv_key" {
+			o.attributes[a.name] = ""
+		}
+	}
+	if err := s.applyAttributes(o, values); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.nextIds[kindName]++
+	o.id = s.nextIds[kindName]
+	if kindName == "engine" && o.attributes["engine_id"] == "" {
+		o.attributes["engine_id"] = fmt.Sprintf("80004fb805%08x", o.id)
+	}
+	if tagId != nil {
+		o.tags = append(o.tags, *tagId)
+	}
+	s.objects[kindName][o.id] = o
+	writeJSON(w, http.StatusCreated, s.render(o, true))
+}
+
+func (s *Server) updateObject(w http.ResponseWriter, r *http.Request, kindName string, id int) {
+	o, ok := s.objects[kindName][id]
+	if !ok {
+		writeError(w, http.StatusNotFound, kindName+" "+strconv.Itoa(id)+" not found")
+		return
+	}
+	values, err := decodeAttributes(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	previous := make(map[string]interface{})
+	for key, value := range o.attributes {
+		previous[key] = value
+	}
+	if err := s.applyAttributes(o, values); err != nil {
+		o.attributes = previous
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, s.render(o, true))
+}
+
+func (s *Server) deleteObject(w http.ResponseWriter, kindName string, id int) {
+	if _, ok := s.objects[kindName][id]; !ok {
+		writeError(w, http.StatusNotFound, kindName+" "+strconv.Itoa(id)+" not found")
+		return
+	}
+	s.removeObject(kindName, id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+//removeObject deletes an object together with all links and tags referring to it
+func (s *Server) removeObject(kindName string, id int) {
+	delete(s.objects[kindName], id)
+	for _, objects := range s.objects {
+		for _, o := range objects {
+			o.links[kindName] = removeId(o.links[kindName], id)
+			if kindName == "tag" {
+				o.tags = removeId(o.tags, id)
+			}
+		}
+	}
+}
+
+func (s *Server) setPower(w http.ResponseWriter, id int, state string) {
+	lab, ok := s.objects["lab"][id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "lab "+strconv.Itoa(id)+" not found")
+		return
+	}
+	if state != "on" && state != "off" {
+		writeError(w, http.StatusBadRequest, "invalid power state '"+state+"'")
+		return
+	}
+	lab.attributes["power"] = state
+	writeJSON(w, http.StatusOK, s.render(lab, true))
+}
+
+//serveLink adds or removes a child object to or from a parent object
+func (s *Server) serveLink(w http.ResponseWriter, r *http.Request, parentKind string, parentId int, childKind, childIdString string) {
+	childId, err := strconv.Atoi(childIdString)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "invalid id '"+childIdString+"'")
+		return
+	}
+	if r.Method != http.MethodPut && r.Method != http.MethodDelete {
+		methodNotAllowed(w)
+		return
+	}
+	if _, ok := s.objects[childKind][childId]; !ok {
+		writeError(w, http.StatusNotFound, childKind+" "+strconv.Itoa(childId)+" not found")
+		return
+	}
+	parent, ok := s.objects[parentKind][parentId]
+	if !ok {
+		writeError(w, http.StatusBadRequest, parentKind+" "+strconv.Itoa(parentId)+" does not exist")
+		return
+	}
+
+	linked := containsId(parent.links[childKind], childId)
+	if r.Method == http.MethodPut {
+		if linked {
+			writeError(w, http.StatusBadRequest, childKind+" "+strconv.Itoa(childId)+" is already linked to "+parentKind+" "+strconv.Itoa(parentId))
+			return
+		}
+		parent.links[childKind] = append(parent.links[childKind], childId)
+		writeJSON(w, http.StatusOK, s.render(parent, true))
+		return
+	}
+
+	if !linked {
+		writeError(w, http.StatusNotFound, childKind+" "+strconv.Itoa(childId)+" is not linked to "+parentKind+" "+strconv.Itoa(parentId))
+		return
+	}
+	parent.links[childKind] = removeId(parent.links[childKind], childId)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+//serveTagLink adds or removes a tag to or from an object
+func (s *Server) serveTagLink(w http.ResponseWriter, r *http.Request, tagId int, kindName, idString string) {
+	if _, ok := kinds[kindName]; !ok || kindName == "tag" {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if r.Method != http.MethodPut && r.Method != http.MethodDelete {
+		methodNotAllowed(w)
+		return
+	}
+	id, err := strconv.Atoi(idString)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "invalid id '"+idString+"'")
+		return
+	}
+	tag, ok := s.objects["tag"][tagId]
+	if !ok {
+		writeError(w, http.StatusNotFound, "tag "+strconv.Itoa(tagId)+" not found")
+		return
+	}
+	o, ok := s.objects[kindName][id]
+	if !ok {
+		writeError(w, http.StatusNotFound, kindName+" "+strconv.Itoa(id)+" not found")
+		return
+	}
+
+	tagged := containsId(o.tags, tagId)
+	if r.Method == http.MethodPut {
+		if tagged {
+			writeError(w, http.StatusBadRequest, kindName+" "+strconv.Itoa(id)+" is already tagged with tag "+strconv.Itoa(tagId))
+			return
+		}
+		o.tags = append(o.tags, tagId)
+	} else {
+		if !tagged {
+			writeError(w, http.StatusNotFound, kindName+" "+strconv.Itoa(id)+" is not tagged with tag "+strconv.Itoa(tagId))
+			return
+		}
+		o.tags = removeId(o.tags, tagId)
+	}
+	writeJSON(w, http.StatusOK, s.render(tag, true))
+}
+
+func (s *Server) deleteTaggedObjects(w http.ResponseWriter, tagId int) {
+	tag, ok := s.objects["tag"][tagId]
+	if !ok {
+		writeError(w, http.StatusNotFound, "tag "+strconv.Itoa(tagId)+" not found")
+		return
+	}
+	rendered := s.render(tag, true)
+	for _, kindName := range taggableKinds {
+		for _, o := range s.sortedObjects(kindName) {
+			if containsId(o.tags, tagId) {
+				s.removeObject(kindName, o.id)
+			}
+		}
+	}
+	writeJSON(w, http.StatusOK, rendered)
+}
+
+//render converts an object into its json representation, linked objects are nested and tags only contain their attributes
+func (s *Server) render(o *object, withLinks bool) map[string]interface{} {
+	result := map[string]interface{}{"id": o.id}
+	for key, value := range o.attributes {
+		result[key] = value
+	}
+	if !withLinks {
+		return result
+	}
+
+	if o.kind == "tag" {
+		for _, kindName := range taggableKinds {
+			list := make([]map[string]interface{}, 0)
+			for _, tagged := range s.sortedObjects(kindName) {
+				if containsId(tagged.tags, o.id) {
+					list = append(list, s.render(tagged, false))
+				}
+			}
+			result[kinds[kindName].plural] = list
+		}
+		return result
+	}
+
+	for _, childKind := range kinds[o.kind].children {
+		list := make([]map[string]interface{}, 0)
+		for _, id := range o.links[childKind] {
+			list = append(list, s.render(s.objects[childKind][id], true))
+		}
+		result[kinds[childKind].plural] = list
+	}
+	tags := make([]map[string]interface{}, 0)
+	for _, id := range o.tags {
+		tags = append(tags, s.render(s.objects["tag"][id], false))
+	}
+	result["tags"] = tags
+	return result
+}
+
+func (s *Server) sortedObjects(kindName string) []*object {
+	var list []*object
+	for _, o := range s.objects[kindName] {
+		list = append(list, o)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].id < list[j].id
+	})
+	return list
+}
+
+func decodeAttributes(r *http.Request) (map[string]interface{}, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]interface{})
+	if len(strings.TrimSpace(string(body))) == 0 {
+		return values, nil
+	}
+	if err := json.Unmarshal(body, &values); err != nil {
+		return nil, fmt.Errorf("invalid json body: %s", err.Error())
+	}
+	return values, nil
+}
+
+//applyAttributes validates the given values and sets them on the object
+func (s *Server) applyAttributes(o *object, values map[string]interface{}) error {
+	for _, a := range kinds[o.kind].attributes {
+		value, ok := values[a.name]
+		if !ok {
+			continue
+		}
+		if value == nil {
+			if a.name == "auth_key" || a.name == "priv_key" {
+				o.attributes[a.name] = nil
+				continue
+			}
+			return fmt.Errorf("attribute '%s' must not be null", a.name)
+		}
+		str, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("attribute '%s' must be a string", a.name)
+		}
+		if a.values != nil && !containsString(a.values, str) {
+			return fmt.Errorf("invalid value '%s' for attribute '%s'", str, a.name)
+		}
+		o.attributes[a.name] = str
+	}
+
+	for _, a := range kinds[o.kind].attributes {
+		if a.required && valueOrEmpty(o.attributes[a.name]) == "" {
+			return fmt.Errorf("attribute '%s' is required", a.name)
+		}
+	}
+	return s.validate(o)
+}
+
+//validate checks the constraints of the control plane database
+func (s *Server) validate(o *object) error {
+	switch o.kind {
+	case "endpoint":
+		host, port, err := net.SplitHostPort(o.attributes["address"].(string))
+		if err != nil || host == "" {
+			return fmt.Errorf("invalid endpoint address '%s'", o.attributes["address"])
+		}
+		if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+			return fmt.Errorf("invalid endpoint port '%s'", port)
+		}
+		for _, other := range s.objects["endpoint"] {
+			if other.id != o.id && other.attributes["address"] == o.attributes["address"] && other.attributes["protocol"] == o.attributes["protocol"] {
+				return fmt.Errorf("endpoint with address '%s' already exists", o.attributes["address"])
+			}
+		}
+	case "user":
+		for _, other := range s.objects["user"] {
+			if other.id != o.id && other.attributes["user"] == o.attributes["user"] {
+				return fmt.Errorf("user '%s' already exists", o.attributes["user"])
+			}
+		}
+	case "tag":
+		for _, other := range s.objects["tag"] {
+			if other.id != o.id && other.attributes["name"] == o.attributes["name"] {
+				return fmt.Errorf("tag '%s' already exists", o.attributes["name"])
+			}
+		}
+	case "agent":
+		dataDir := path.Clean(o.attributes["data_dir"].(string))
+		if path.IsAbs(dataDir) || dataDir == ".." || strings.HasPrefix(dataDir, "../") {
+			return fmt.Errorf("data dir '%s' is outside of the data root", o.attributes["data_dir"])
+		}
+	}
+	return nil
+}
+
+func valueOrEmpty(value interface{}) interface{} {
+	if value == nil {
+		return ""
+	}
+	return value
+}
+
+func containsId(ids []int, id int) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
+
+func removeId(ids []int, id int) []int {
+	var result []int
+	for _, i := range ids {
+		if i != id {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,464 @@
+package snmpsimtest
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+var (
+	//PacketFilters are the filters offered by the fake for packet metrics
+	PacketFilters = []string{"local_address", "peer_address", "protocol"}
+	//MessageFilters are the filters offered by the fake for message metrics
+	MessageFilters = []string{"local_address", "peer_address", "protocol", "engine_id", "security_model", "security_level",
+		"context_engine_id", "context_name", "pdu_type", "recording"}
+)
+
+/*
+PacketActivity contains packet counters for one combination of filter values. The keys of Labels must be PacketFilters.
+*/
+type PacketActivity struct {
+	Labels          map[string]string
+	FirstHit        int
+	LastHit         int
+	Total           int64
+	ParseFailures   int64
+	AuthFailures    int64
+	ContextFailures int64
+}
+
+/*
+MessageActivity contains message counters for one combination of filter values. The keys of Labels must be MessageFilters.
+*/
+type MessageActivity struct {
+	Labels     map[string]string
+	FirstHit   int
+	LastHit    int
+	Pdus       int64
+	VarBinds   int64
+	Failures   int64
+	Variations []VariationActivity
+}
+
+/*
+VariationActivity contains the counters of a variation module.
+*/
+type VariationActivity struct {
+	Name     string
+	FirstHit int
+	LastHit  int
+	Total    int64
+	Failures int64
+}
+
+/*
+AddPacketActivity adds the counters of the given activity to the counters with the same labels, like the metrics importer
+of the control plane does. Zero hit timestamps are set to the current time.
+*/
+func (s *Server) AddPacketActivity(activity PacketActivity) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	now := int(s.now().Unix())
+	first, last := hits(activity.FirstHit, activity.LastHit, now)
+	for _, a := range s.packetActivity {
+		if sameLabels(a.Labels, activity.Labels) {
+			a.FirstHit, a.LastHit = minHit(a.FirstHit, first), maxHit(a.LastHit, last)
+			a.Total += activity.Total
+			a.ParseFailures += activity.ParseFailures
+			a.AuthFailures += activity.AuthFailures
+			a.ContextFailures += activity.ContextFailures
+			return
+		}
+	}
+	activity.Labels = copyLabels(activity.Labels)
+	activity.FirstHit, activity.LastHit = first, last
+	s.packetActivity = append(s.packetActivity, &activity)
+}
+
+/*
+AddMessageActivity adds the counters of the given activity to the counters with the same labels, like the metrics importer
+of the control plane does. Zero hit timestamps are set to the current time.
+*/
+func (s *Server) AddMessageActivity(activity MessageActivity) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	now := int(s.now().Unix())
+	first, last := hits(activity.FirstHit, activity.LastHit, now)
+	var target *MessageActivity
+	for _, a := range s.messageActivity {
+		if sameLabels(a.Labels, activity.Labels) {
+			target = a
+			break
+		}
+	}
+	if target == nil {
+		target = &MessageActivity{Labels: copyLabels(activity.Labels), FirstHit: first, LastHit: last}
+		s.messageActivity = append(s.messageActivity, target)
+	}
+	target.FirstHit, target.LastHit = minHit(target.FirstHit, first), maxHit(target.LastHit, last)
+	target.Pdus += activity.Pdus
+	target.VarBinds += activity.VarBinds
+	target.Failures += activity.Failures
+	target.Variations = mergeVariations(target.Variations, activity.Variations, now)
+}
+
+/*
+ResetActivity clears all packet and message counters, like a restart of the metrics importer.
+*/
+func (s *Server) ResetActivity() {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.packetActivity = nil
+	s.messageActivity = nil
+}
+
+//process is a process watched by the supervisor of the control plane
+type process struct {
+	id       int
+	path     string
+	started  time.Time
+	hostname string
+	watchDir string
+	consoles []console
+}
+
+type console struct {
+	id        int
+	timestamp time.Time
+	text      string
+}
+
+//newSupervisorProcess returns the process which runs the command responders of all powered on labs
+func newSupervisorProcess(id int, now time.Time) *process {
+	return &process{
+		id:       id,
+		path:     "/opt/snmpsim/supervised/snmpsim-run-labs.sh",
+		started:  now,
+		hostname: "sim",
+		watchDir: "/opt/snmpsim/supervised",
+		consoles: []console{{id: 1, timestamp: now, text: "snmpsim-command-responder started\n"}},
+	}
+}
+
+func (s *Server) serveMetrics(w http.ResponseWriter, r *http.Request, segments []string) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w)
+		return
+	}
+	if len(segments) == 0 {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	switch segments[0] {
+	case "processes":
+		s.serveProcesses(w, r, segments[1:])
+	case "activity":
+		if len(segments) < 2 || (segments[1] != "packets" && segments[1] != "messages") {
+			writeError(w, http.StatusNotFound, "not found")
+			return
+		}
+		filters := PacketFilters
+		if segments[1] == "messages" {
+			filters = MessageFilters
+		}
+		switch len(segments) {
+		case 2:
+			if segments[1] == "packets" {
+				writeJSON(w, http.StatusOK, s.sumPackets(r))
+			} else {
+				writeJSON(w, http.StatusOK, s.sumMessages(r))
+			}
+		case 3:
+			if segments[2] != "filters" {
+				writeError(w, http.StatusNotFound, "not found")
+				return
+			}
+			//the control plane lists the possible values of its filters in an object keyed by the filter names
+			values := make(map[string][]string)
+			for _, filter := range filters {
+				values[filter] = s.filterValues(segments[1], filter)
+			}
+			writeJSON(w, http.StatusOK, values)
+		case 4:
+			if segments[2] != "filters" || !containsString(filters, segments[3]) {
+				writeError(w, http.StatusNotFound, "unknown filter '"+segments[len(segments)-1]+"'")
+				return
+			}
+			writeJSON(w, http.StatusOK, s.filterValues(segments[1], segments[3]))
+		default:
+			writeError(w, http.StatusNotFound, "not found")
+		}
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *Server) serveProcesses(w http.ResponseWriter, r *http.Request, segments []string) {
+	if len(segments) == 0 {
+		list := make([]map[string]interface{}, 0)
+		for _, p := range s.processes {
+			list = append(list, s.renderProcess(p))
+		}
+		list, err := pageList(list, r.URL.Query())
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, list)
+		return
+	}
+
+	var p *process
+	id, err := strconv.Atoi(segments[0])
+	for _, candidate := range s.processes {
+		if err == nil && candidate.id == id {
+			p = candidate
+		}
+	}
+	if p == nil {
+		writeError(w, http.StatusNotFound, "process "+segments[0]+" not found")
+		return
+	}
+
+	switch {
+	case len(segments) == 1:
+		writeJSON(w, http.StatusOK, s.renderProcess(p))
+	case len(segments) == 2 && segments[1] == "endpoints":
+		writeJSON(w, http.StatusOK, s.processEndpoints(p))
+	case len(segments) == 3 && segments[1] == "endpoints":
+		for _, endpoint := range s.processEndpoints(p) {
+			if strconv.Itoa(endpoint["id"].(int)) == segments[2] {
+				writeJSON(w, http.StatusOK, endpoint)
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "endpoint "+segments[2]+" not found")
+	case len(segments) == 2 && segments[1] == "console":
+		list := make([]map[string]interface{}, 0)
+		for _, c := range p.consoles {
+			list = append(list, renderConsole(c))
+		}
+		writeJSON(w, http.StatusOK, list)
+	case len(segments) == 3 && segments[1] == "console":
+		for _, c := range p.consoles {
+			if strconv.Itoa(c.id) == segments[2] {
+				writeJSON(w, http.StatusOK, renderConsole(c))
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "console page "+segments[2]+" not found")
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *Server) renderProcess(p *process) map[string]interface{} {
+	now := s.now()
+	lastConsole := p.started
+	if len(p.consoles) > 0 {
+		lastConsole = p.consoles[len(p.consoles)-1].timestamp
+	}
+	return map[string]interface{}{
+		"id":              p.id,
+		"path":            p.path,
+		"runtime":         int(now.Sub(p.started).Seconds()),
+		"cpu":             0,
+		"memory":          0,
+		"files":           len(s.processEndpoints(p)),
+		"exits":           0,
+		"changes":         0,
+		"update_interval": 1,
+		"last_update":     timestamp(now),
+		"console_pages":   map[string]interface{}{"count": len(p.consoles), "last_update": timestamp(lastConsole)},
+		"supervisor":      map[string]interface{}{"hostname": p.hostname, "watch_dir": p.watchDir},
+	}
+}
+
+//processEndpoints returns the endpoints of all powered on labs, they are served by the supervised process
+func (s *Server) processEndpoints(p *process) []map[string]interface{} {
+	list := make([]map[string]interface{}, 0)
+	seen := make(map[int]bool)
+	for _, lab := range s.sortedObjects("lab") {
+		if lab.attributes["power"] != "on" {
+			continue
+		}
+		for _, agentId := range lab.links["agent"] {
+			for _, engineId := range s.objects["agent"][agentId].links["engine"] {
+				for _, endpointId := range s.objects["engine"][engineId].links["endpoint"] {
+					if seen[endpointId] {
+						continue
+					}
+					seen[endpointId] = true
+					endpoint := s.objects["endpoint"][endpointId]
+					list = append(list, map[string]interface{}{
+						"id":       endpoint.id,
+						"protocol": endpoint.attributes["protocol"],
+						"address":  endpoint.attributes["address"],
+					})
+				}
+			}
+		}
+	}
+	return list
+}
+
+func renderConsole(c console) map[string]interface{} {
+	return map[string]interface{}{"id": c.id, "timestamp": timestamp(c.timestamp), "text": c.text}
+}
+
+func (s *Server) sumPackets(r *http.Request) map[string]interface{} {
+	var sum PacketActivity
+	for _, a := range s.packetActivity {
+		if matchesQuery(a.Labels, r, PacketFilters) {
+			sum.FirstHit, sum.LastHit = minHit(sum.FirstHit, a.FirstHit), maxHit(sum.LastHit, a.LastHit)
+			sum.Total += a.Total
+			sum.ParseFailures += a.ParseFailures
+			sum.AuthFailures += a.AuthFailures
+			sum.ContextFailures += a.ContextFailures
+		}
+	}
+	return map[string]interface{}{
+		"first_hit":        sum.FirstHit,
+		"last_hit":         sum.LastHit,
+		"total":            sum.Total,
+		"parse_failures":   sum.ParseFailures,
+		"auth_failures":    sum.AuthFailures,
+		"context_failures": sum.ContextFailures,
+	}
+}
+
+func (s *Server) sumMessages(r *http.Request) map[string]interface{} {
+	var sum MessageActivity
+	for _, a := range s.messageActivity {
+		if matchesQuery(a.Labels, r, MessageFilters) {
+			sum.FirstHit, sum.LastHit = minHit(sum.FirstHit, a.FirstHit), maxHit(sum.LastHit, a.LastHit)
+			sum.Pdus += a.Pdus
+			sum.VarBinds += a.VarBinds
+			sum.Failures += a.Failures
+			sum.Variations = mergeVariations(sum.Variations, a.Variations, 0)
+		}
+	}
+	variations := make([]map[string]interface{}, 0)
+	for _, v := range sum.Variations {
+		variations = append(variations, map[string]interface{}{
+			"name":      v.Name,
+			"first_hit": v.FirstHit,
+			"last_hit":  v.LastHit,
+			"total":     v.Total,
+			"failures":  v.Failures,
+		})
+	}
+	return map[string]interface{}{
+		"first_hit":  sum.FirstHit,
+		"last_hit":   sum.LastHit,
+		"pdus":       sum.Pdus,
+		"var_binds":  sum.VarBinds,
+		"failures":   sum.Failures,
+		"variations": variations,
+	}
+}
+
+func (s *Server) filterValues(activity, filter string) []string {
+	seen := make(map[string]bool)
+	if activity == "packets" {
+		for _, a := range s.packetActivity {
+			if value, ok := a.Labels[filter]; ok {
+				seen[value] = true
+			}
+		}
+	} else {
+		for _, a := range s.messageActivity {
+			if value, ok := a.Labels[filter]; ok {
+				seen[value] = true
+			}
+		}
+	}
+	values := make([]string, 0)
+	for value := range seen {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	return values
+}
+
+//matchesQuery checks the labels against all known filters in the query, unknown filters are ignored
+func matchesQuery(labels map[string]string, r *http.Request, filters []string) bool {
+	query := r.URL.Query()
+	for _, filter := range filters {
+		if value, ok := query[filter]; ok && len(value) > 0 && labels[filter] != value[0] {
+			return false
+		}
+	}
+	return true
+}
+
+func mergeVariations(target, add []VariationActivity, now int) []VariationActivity {
+	for _, v := range add {
+		first, last := v.FirstHit, v.LastHit
+		if now != 0 {
+			first, last = hits(v.FirstHit, v.LastHit, now)
+		}
+		merged := false
+		for i := range target {
+			if target[i].Name == v.Name {
+				target[i].FirstHit, target[i].LastHit = minHit(target[i].FirstHit, first), maxHit(target[i].LastHit, last)
+				target[i].Total += v.Total
+				target[i].Failures += v.Failures
+				merged = true
+			}
+		}
+		if !merged {
+			v.FirstHit, v.LastHit = first, last
+			target = append(target, v)
+		}
+	}
+	return target
+}
+
+func hits(first, last, now int) (int, int) {
+	if first == 0 {
+		first = now
+	}
+	if last == 0 {
+		last = now
+	}
+	return first, last
+}
+
+func minHit(a, b int) int {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+	return a
+}
+
+func maxHit(a, b int) int {
+	if b > a {
+		return b
+	}
+	return a
+}
+
+func sameLabels(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		if other, ok := b[key]; !ok || other != value {
+			return false
+		}
+	}
+	return true
+}
+
+func copyLabels(labels map[string]string) map[string]string {
+	result := make(map[string]string, len(labels))
+	for key, value := range labels {
+		result[key] = value
+	}
+	return result
+}
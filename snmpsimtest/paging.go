@@ -0,0 +1,80 @@
+package snmpsimtest
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+)
+
+//query parameters of list requests for paging and sorting
+const (
+	pageParam    = "page"
+	perPageParam = "per_page"
+	sortByParam  = "sort_by"
+	orderParam   = "order"
+)
+
+//isListParam checks whether the query parameter controls paging or sorting instead of filtering
+func isListParam(key string) bool {
+	return key == pageParam || key == perPageParam || key == sortByParam || key == orderParam
+}
+
+//pageList sorts the rendered objects and returns the requested page, the whole list is returned if no page size is given
+func pageList(list []map[string]interface{}, query url.Values) ([]map[string]interface{}, error) {
+	if sortBy := query.Get(sortByParam); sortBy != "" {
+		for _, item := range list {
+			if _, ok := item[sortBy]; !ok {
+				return nil, fmt.Errorf("can not sort by unknown attribute '%s'", sortBy)
+			}
+		}
+		descending := false
+		switch query.Get(orderParam) {
+		case "", "asc":
+		case "desc":
+			descending = true
+		default:
+			return nil, fmt.Errorf("invalid order '%s'", query.Get(orderParam))
+		}
+		sort.SliceStable(list, func(i, j int) bool {
+			if descending {
+				return less(list[j][sortBy], list[i][sortBy])
+			}
+			return less(list[i][sortBy], list[j][sortBy])
+		})
+	}
+
+	if query.Get(perPageParam) == "" {
+		return list, nil
+	}
+	perPage, err := strconv.Atoi(query.Get(perPageParam))
+	if err != nil || perPage < 1 {
+		return nil, fmt.Errorf("invalid per_page '%s'", query.Get(perPageParam))
+	}
+	page := 1
+	if query.Get(pageParam) != "" {
+		page, err = strconv.Atoi(query.Get(pageParam))
+		if err != nil || page < 1 {
+			return nil, fmt.Errorf("invalid page '%s'", query.Get(pageParam))
+		}
+	}
+	start := (page - 1) * perPage
+	if start >= len(list) {
+		return make([]map[string]interface{}, 0), nil
+	}
+	end := start + perPage
+	if end > len(list) {
+		end = len(list)
+	}
+	return list[start:end], nil
+}
+
+//less compares two attribute values, numbers are compared numerically and everything else as string
+func less(a, b interface{}) bool {
+	aNumber, aOk := a.(int)
+	bNumber, bOk := b.(int)
+	if aOk && bOk {
+		return aNumber < bNumber
+	}
+	return fmt.Sprint(valueOrEmpty(a)) < fmt.Sprint(valueOrEmpty(b))
+}
@@ -0,0 +1,132 @@
+package snmpsimtest
+
+import (
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//recording is a simulation data file stored by the fake
+type recording struct {
+	id      int
+	content []byte
+}
+
+/*
+AddRecording stores a record file at the given path relative to the data root, replacing an existing file.
+*/
+func (s *Server) AddRecording(recordingPath string, content []byte) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.storeRecording(path.Clean(recordingPath), content)
+}
+
+/*
+Recording returns the content of the record file at the given path and whether it exists.
+*/
+func (s *Server) Recording(recordingPath string) ([]byte, bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	rec, ok := s.recordings[path.Clean(recordingPath)]
+	if !ok {
+		return nil, false
+	}
+	return rec.content, true
+}
+
+func (s *Server) storeRecording(recordingPath string, content []byte) {
+	s.nextRecId++
+	s.recordings[recordingPath] = &recording{id: s.nextRecId, content: content}
+}
+
+func (s *Server) serveRecordings(w http.ResponseWriter, r *http.Request, recordingPath string) {
+	if recordingPath == "" {
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w)
+			return
+		}
+		s.listRecordings(w)
+		return
+	}
+
+	recordingPath = path.Clean(recordingPath)
+	if path.IsAbs(recordingPath) || recordingPath == ".." || strings.HasPrefix(recordingPath, "../") {
+		writeError(w, http.StatusBadRequest, "invalid recording path '"+recordingPath+"'")
+		return
+	}
+
+	rec, exists := s.recordings[recordingPath]
+	switch r.Method {
+	case http.MethodGet:
+		if !exists {
+			writeError(w, http.StatusNotFound, "recording '"+recordingPath+"' not found")
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		//like a reverse proxy in front of the control plane, recordings are compressed if the client accepts it
+		if strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			w.Header().Set("Content-Encoding", "gzip")
+			w.WriteHeader(http.StatusOK)
+			gzipWriter := gzip.NewWriter(w)
+			_, _ = gzipWriter.Write(rec.content)
+			_ = gzipWriter.Close()
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(rec.content)))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(rec.content)
+	case http.MethodPost:
+		if exists {
+			writeError(w, http.StatusConflict, "recording '"+recordingPath+"' already exists")
+			return
+		}
+		var body io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			gzipReader, err := gzip.NewReader(r.Body)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "invalid gzip body")
+				return
+			}
+			body = gzipReader
+		}
+		content, err := ioutil.ReadAll(body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "error while reading recording")
+			return
+		}
+		s.storeRecording(recordingPath, content)
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		if !exists {
+			writeError(w, http.StatusNotFound, "recording '"+recordingPath+"' not found")
+			return
+		}
+		delete(s.recordings, recordingPath)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func (s *Server) listRecordings(w http.ResponseWriter) {
+	var paths []string
+	for recordingPath := range s.recordings {
+		paths = append(paths, recordingPath)
+	}
+	sort.Strings(paths)
+
+	list := make([]map[string]interface{}, 0)
+	for _, recordingPath := range paths {
+		list = append(list, map[string]interface{}{
+			"id":   s.recordings[recordingPath].id,
+			"name": path.Base(recordingPath),
+			"path": recordingPath,
+		})
+	}
+	writeJSON(w, http.StatusOK, list)
+}
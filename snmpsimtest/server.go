@@ -0,0 +1,149 @@
+/*
+Package snmpsimtest provides an in-process fake of the snmpsim control plane for tests.
+
+The fake implements the management api (snmpsim/mgmt/v1) and the metrics api (snmpsim/metrics/v1) on top of an
+httptest.Server and keeps all objects in memory, so code using the snmpsimclient package can be tested without a
+running snmpsim-control-plane:
+
+	server := snmpsimtest.NewServer()
+	defer server.Close()
+
+	client, err := snmpsimclient.NewManagementClient(server.URL)
+	metricsClient, err := snmpsimclient.NewMetricsClient(server.URL)
+*/
+package snmpsimtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	//mgmtPath is the path prefix of the management api
+	mgmtPath = "/snmpsim/mgmt/v1/"
+	//metricsPath is the path prefix of the metrics api
+	metricsPath = "/snmpsim/metrics/v1/"
+)
+
+/*
+Server is a fake snmpsim control plane serving the management and the metrics api on the same address.
+It is safe for concurrent use.
+*/
+type Server struct {
+	*httptest.Server
+
+	mtx      sync.Mutex
+	username string
+	password string
+
+	objects    map[string]map[int]*object
+	nextIds    map[string]int
+	recordings map[string]*recording
+	nextRecId  int
+
+	processes       []*process
+	packetActivity  []*PacketActivity
+	messageActivity []*MessageActivity
+	now             func() time.Time
+}
+
+/*
+NewServer starts and returns a new fake control plane. The caller should call Close when finished, to shut it down.
+*/
+func NewServer() *Server {
+	s := NewUnstartedServer()
+	s.Start()
+	return s
+}
+
+/*
+NewUnstartedServer returns a new fake control plane which is not started yet. Call Start or StartTLS to start it.
+*/
+func NewUnstartedServer() *Server {
+	s := &Server{
+		objects:    make(map[string]map[int]*object),
+		nextIds:    make(map[string]int),
+		recordings: make(map[string]*recording),
+		now:        time.Now,
+	}
+	for kind := range kinds {
+		s.objects[kind] = make(map[int]*object)
+	}
+	s.processes = []*process{newSupervisorProcess(1, s.now())}
+	s.Server = httptest.NewUnstartedServer(s)
+	return s
+}
+
+/*
+SetBasicAuth makes the server require http basic auth with the given credentials. Requests without them are answered with 401.
+An empty username disables authentication again.
+*/
+func (s *Server) SetBasicAuth(username, password string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.username = username
+	s.password = password
+}
+
+/*
+ServeHTTP dispatches a request to the management or the metrics api.
+*/
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.username != "" {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != s.username || password != s.password {
+			w.Header().Set("WWW-Authenticate", `Basic realm="snmpsim"`)
+			writeError(w, http.StatusUnauthorized, "authentication required")
+			return
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(r.URL.Path, mgmtPath):
+		s.serveManagement(w, r, splitPath(strings.TrimPrefix(r.URL.Path, mgmtPath)))
+	case strings.HasPrefix(r.URL.Path, metricsPath):
+		s.serveMetrics(w, r, splitPath(strings.TrimPrefix(r.URL.Path, metricsPath)))
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+//errorResponse is the body of all error responses, it matches the ErrorResponse of the client
+type errorResponse struct {
+	Message string `json:"message"`
+	Status  int    `json:"status"`
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Message: message, Status: status})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func methodNotAllowed(w http.ResponseWriter) {
+	writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+//timestamp formats a time the way the control plane does
+func timestamp(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05-07:00")
+}
@@ -0,0 +1,135 @@
+package snmpsimtest_test
+
+import (
+	"github.com/inexio/snmpsim-restapi-go-client"
+	"github.com/inexio/snmpsim-restapi-go-client/snmpsimtest"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestServer_Management(t *testing.T) {
+	server := snmpsimtest.NewServer()
+	defer server.Close()
+
+	client, err := snmpsimclient.NewManagementClient(server.URL)
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+
+	tag, err := client.CreateTag("tag", "test tag")
+	if !assert.NoError(t, err, "error while creating tag") {
+		return
+	}
+	lab, err := client.CreateLabWithTag("lab", tag.Id)
+	if assert.NoError(t, err, "error while creating lab with tag") && assert.Len(t, lab.Tags, 1) {
+		assert.Equal(t, "tag", lab.Tags[0].Name)
+		assert.Equal(t, "off", lab.Power)
+	}
+	_, err = client.CreateLabWithTag("lab", tag.Id+1)
+	assert.True(t, errors.Is(err, snmpsimclient.ErrNotFound), "error does not match ErrNotFound")
+
+	agent, err := client.CreateAgent("agent", "agent")
+	if !assert.NoError(t, err, "error while creating agent") {
+		return
+	}
+	assert.NoError(t, client.AddAgentToLab(lab.Id, agent.Id), "error while adding agent to lab")
+	err = client.AddAgentToLab(lab.Id, agent.Id)
+	assert.True(t, errors.Is(err, snmpsimclient.ErrValidation), "adding an agent twice does not return 400")
+	assert.NoError(t, client.SetLabPower(lab.Id, true), "error while powering lab on")
+
+	lab, err = client.GetLab(lab.Id)
+	if assert.NoError(t, err, "error while getting lab") && assert.Len(t, lab.Agents, 1) {
+		assert.Equal(t, "on", lab.Power)
+		assert.Equal(t, "agent", lab.Agents[0].Name)
+	}
+
+	_, err = client.CreateEndpoint("endpoint", "no address", "udpv4")
+	assert.True(t, errors.Is(err, snmpsimclient.ErrValidation), "invalid address does not return 400")
+
+	tag, err = client.DeleteAllObjectsWithTag(tag.Id)
+	if assert.NoError(t, err, "error while deleting tagged objects") {
+		assert.Len(t, tag.Labs, 1)
+	}
+	_, err = client.GetLab(lab.Id)
+	assert.True(t, errors.Is(err, snmpsimclient.ErrNotFound), "tagged lab was not deleted")
+	agent, err = client.GetAgent(agent.Id)
+	assert.NoError(t, err, "untagged agent was deleted")
+
+	record := "1.3.6.1.2.1.1.1.0|4|fake\n"
+	assert.NoError(t, client.UploadRecordFileString(&record, "agent/public.snmprec"), "error while uploading record file")
+	err = client.UploadRecordFileString(&record, "agent/public.snmprec")
+	assert.True(t, errors.Is(err, snmpsimclient.ErrConflict), "uploading an existing record file does not return 409")
+	content, ok := server.Recording("agent/public.snmprec")
+	if assert.True(t, ok, "record file was not stored") {
+		assert.Equal(t, record, string(content))
+	}
+
+	server.SetBasicAuth("user", "password")
+	_, err = client.GetLabs(nil)
+	assert.True(t, errors.Is(err, snmpsimclient.ErrUnauthorized), "missing credentials do not return 401")
+	assert.NoError(t, client.SetUsernameAndPassword("user", "password"))
+	_, err = client.GetLabs(nil)
+	assert.NoError(t, err, "error while getting labs with credentials")
+}
+
+func TestServer_Metrics(t *testing.T) {
+	server := snmpsimtest.NewServer()
+	defer server.Close()
+
+	client, err := snmpsimclient.NewMetricsClient(server.URL)
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+
+	server.AddPacketActivity(snmpsimtest.PacketActivity{
+		Labels: map[string]string{"local_address": "127.0.0.1:1161", "protocol": "udpv4"},
+		Total:  10,
+	})
+	server.AddPacketActivity(snmpsimtest.PacketActivity{
+		Labels:       map[string]string{"local_address": "127.0.0.1:1162", "protocol": "udpv4"},
+		Total:        5,
+		AuthFailures: 1,
+	})
+	server.AddPacketActivity(snmpsimtest.PacketActivity{
+		Labels: map[string]string{"local_address": "127.0.0.1:1161", "protocol": "udpv4"},
+		Total:  2,
+	})
+
+	packets, err := client.GetPackets(nil)
+	if assert.NoError(t, err, "error while getting packets") {
+		assert.Equal(t, int64(17), *packets.Total)
+		assert.Equal(t, int64(1), *packets.AuthFailures)
+	}
+	packets, err = client.GetPackets(map[string]string{"local_address": "127.0.0.1:1161"})
+	if assert.NoError(t, err, "error while getting filtered packets") {
+		assert.Equal(t, int64(12), *packets.Total)
+	}
+	values, err := client.GetPossibleValuesForPacketFilter("local_address")
+	if assert.NoError(t, err, "error while getting filter values") {
+		assert.Equal(t, []string{"127.0.0.1:1161", "127.0.0.1:1162"}, values)
+	}
+
+	server.AddMessageActivity(snmpsimtest.MessageActivity{
+		Labels:     map[string]string{"context_name": "public"},
+		Pdus:       3,
+		VarBinds:   6,
+		Variations: []snmpsimtest.VariationActivity{{Name: "numeric", Total: 2}},
+	})
+	messages, err := client.GetMessages(map[string]string{"context_name": "public"})
+	if assert.NoError(t, err, "error while getting messages") && assert.Len(t, messages.Variations, 1) {
+		assert.Equal(t, int64(3), *messages.Pdus)
+		assert.Equal(t, "numeric", *messages.Variations[0].Name)
+	}
+
+	server.ResetActivity()
+	packets, err = client.GetPackets(nil)
+	if assert.NoError(t, err, "error while getting packets") {
+		assert.Equal(t, int64(0), *packets.Total)
+	}
+
+	processes, err := client.GetProcesses(nil)
+	if assert.NoError(t, err, "error while getting processes") && assert.Len(t, processes, 1) {
+		assert.Equal(t, "sim", processes[0].Supervisor.Hostname)
+	}
+}
@@ -0,0 +1,211 @@
+package snmpsimclient
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+//instrumentationName is the name of the tracer and the meter of the clients
+const instrumentationName = "github.com/inexio/snmpsim-restapi-go-client"
+
+//names of the metrics recorded by the clients
+const (
+	requestDurationMetric = "snmpsim.client.request.duration"
+	requestErrorsMetric   = "snmpsim.client.request.errors"
+)
+
+//attribute keys of the spans and metrics
+const (
+	operationKey  = attribute.Key("snmpsim.operation")
+	retryCountKey = attribute.Key("snmpsim.retry_count")
+	methodKey     = attribute.Key("http.request.method")
+	statusCodeKey = attribute.Key("http.response.status_code")
+	pathKey       = attribute.Key("url.path")
+	errorTypeKey  = attribute.Key("error.type")
+)
+
+/*
+WithTracerProvider makes the client create a span for every call of the api. The span is named after the called method,
+e.g. "ManagementClient.AddEngineToAgent", and carries the ids of the involved objects, the http method, the status code
+and the number of retries as attributes.
+*/
+func WithTracerProvider(provider trace.TracerProvider) ClientOption {
+	return func(o *clientOptions) error {
+		if provider == nil {
+			return errors.New("invalid tracer provider")
+		}
+		o.tracerProvider = provider
+		return nil
+	}
+}
+
+/*
+WithMeterProvider makes the client record the duration of every call of the api in the histogram
+"snmpsim.client.request.duration" and count failed calls in the counter "snmpsim.client.request.errors".
+*/
+func WithMeterProvider(provider metric.MeterProvider) ClientOption {
+	return func(o *clientOptions) error {
+		if provider == nil {
+			return errors.New("invalid meter provider")
+		}
+		o.meterProvider = provider
+		return nil
+	}
+}
+
+//telemetry creates the spans and records the metrics of requests, a nil telemetry does nothing
+type telemetry struct {
+	tracer   trace.Tracer
+	duration metric.Float64Histogram
+	errors   metric.Int64Counter
+}
+
+//newTelemetry creates the telemetry of a client, it returns nil if no provider is given
+func newTelemetry(tracerProvider trace.TracerProvider, meterProvider metric.MeterProvider) (*telemetry, error) {
+	if tracerProvider == nil && meterProvider == nil {
+		return nil, nil
+	}
+	t := &telemetry{}
+	if tracerProvider != nil {
+		t.tracer = tracerProvider.Tracer(instrumentationName)
+	}
+	if meterProvider != nil {
+		meter := meterProvider.Meter(instrumentationName)
+		var err error
+		t.duration, err = meter.Float64Histogram(requestDurationMetric,
+			metric.WithDescription("Duration of calls of the snmpsim api including retries."), metric.WithUnit("s"))
+		if err != nil {
+			return nil, errors.Wrap(err, "error while creating duration histogram")
+		}
+		t.errors, err = meter.Int64Counter(requestErrorsMetric,
+			metric.WithDescription("Number of failed calls of the snmpsim api."))
+		if err != nil {
+			return nil, errors.Wrap(err, "error while creating error counter")
+		}
+	}
+	return t, nil
+}
+
+//requestTelemetry is the telemetry of a single request
+type requestTelemetry struct {
+	t         *telemetry
+	span      trace.Span
+	start     time.Time
+	operation string
+	method    string
+}
+
+//start starts the span of a request and returns the context of the span
+func (t *telemetry) start(ctx context.Context, method, path string) (context.Context, *requestTelemetry) {
+	if t == nil {
+		return ctx, nil
+	}
+	r := &requestTelemetry{t: t, start: time.Now(), operation: operationName(method), method: method}
+	if t.tracer != nil {
+		attributes := append([]attribute.KeyValue{
+			operationKey.String(r.operation),
+			methodKey.String(method),
+			pathKey.String("/" + path),
+		}, idAttributes(path)...)
+		ctx, r.span = t.tracer.Start(ctx, r.operation, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attributes...))
+	}
+	return ctx, r
+}
+
+//end ends the span of the request and records its metrics. The status code is 0 if no response was received.
+func (r *requestTelemetry) end(ctx context.Context, statusCode, attempts int, err error) {
+	if r == nil {
+		return
+	}
+	attributes := []attribute.KeyValue{operationKey.String(r.operation), methodKey.String(r.method)}
+	if statusCode != 0 {
+		attributes = append(attributes, statusCodeKey.Int(statusCode))
+	}
+	failed := err != nil || statusCode >= 400
+	if failed {
+		errorType := strconv.Itoa(statusCode)
+		var requestErr *RequestError
+		if errors.As(err, &requestErr) {
+			errorType = reflect.TypeOf(requestErr.Err).String()
+		}
+		attributes = append(attributes, errorTypeKey.String(errorType))
+	}
+
+	if r.span != nil {
+		r.span.SetAttributes(retryCountKey.Int(attempts - 1))
+		r.span.SetAttributes(attributes...)
+		if err != nil {
+			r.span.RecordError(err)
+			r.span.SetStatus(codes.Error, err.Error())
+		} else if failed {
+			r.span.SetStatus(codes.Error, "http status "+strconv.Itoa(statusCode))
+		}
+		r.span.End()
+	}
+	if r.t.duration != nil {
+		set := metric.WithAttributes(attributes...)
+		r.t.duration.Record(ctx, time.Since(r.start).Seconds(), set)
+		if failed {
+			r.t.errors.Add(ctx, 1, set)
+		}
+	}
+}
+
+//clientPackage is the import path of this package as it appears in function names of stack frames
+var clientPackage = reflect.TypeOf(client{}).PkgPath()
+
+//operationName returns the name of the exported method of this package which made the request, e.g.
+//"ManagementClient.AddEngineToAgent". Context variants are named like the method without context.
+func operationName(method string) string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for {
+		frame, more := frames.Next()
+		//methods of this package look like "<package>.(*ManagementClient).GetLabContext"
+		if name := strings.TrimPrefix(frame.Function, clientPackage+".(*"); name != frame.Function {
+			parts := strings.Split(name, ").")
+			if len(parts) == 2 && isExported(parts[0]) && isExported(parts[1]) && !strings.Contains(parts[1], ".") {
+				return parts[0] + "." + strings.TrimSuffix(parts[1], "Context")
+			}
+		}
+		if !more {
+			return "HTTP " + method
+		}
+	}
+}
+
+func isExported(name string) bool {
+	return name != "" && unicode.IsUpper([]rune(name)[0])
+}
+
+//idAttributes returns an attribute for every object id in the path, e.g. "snmpsim.agent.id" for "agents/3"
+func idAttributes(path string) []attribute.KeyValue {
+	var attributes []attribute.KeyValue
+	segments := strings.Split(path, "/")
+	for i := 1; i < len(segments); i++ {
+		id, err := strconv.Atoi(segments[i])
+		if err != nil {
+			continue
+		}
+		name := segments[i-1]
+		for _, r := range []resource{labResource, engineResource, agentResource, endpointResource, userResource,
+			selectorResource, tagResource, processResource} {
+			if name == r.plural {
+				name = r.name
+				break
+			}
+		}
+		attributes = append(attributes, attribute.Int("snmpsim."+name+".id", id))
+	}
+	return attributes
+}
@@ -0,0 +1,115 @@
+package snmpsimclient
+
+import (
+	"context"
+	"github.com/inexio/snmpsim-restapi-go-client/snmpsimtest"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"testing"
+)
+
+func TestClient_Telemetry(t *testing.T) {
+	server := snmpsimtest.NewServer()
+	defer server.Close()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	client, err := NewManagementClient(server.URL, WithTracerProvider(tracerProvider), WithMeterProvider(meterProvider),
+		WithRetryPolicy(NoRetryPolicy()))
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+	agent, err := client.CreateAgent("agent", "data")
+	if !assert.NoError(t, err) {
+		return
+	}
+	engine, err := client.CreateEngine("engine", "0102030405060708")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, client.AddEngineToAgentContext(context.Background(), agent.Id, engine.Id))
+	_, err = client.GetLab(1234)
+	assert.Error(t, err)
+
+	spans := exporter.GetSpans()
+	if assert.Len(t, spans, 4) {
+		assert.Equal(t, "ManagementClient.CreateAgent", spans[0].Name)
+		link := spans[2]
+		assert.Equal(t, "ManagementClient.AddEngineToAgent", link.Name)
+		attributes := attribute.NewSet(link.Attributes...)
+		for key, expected := range map[attribute.Key]attribute.Value{
+			"snmpsim.agent.id":          attribute.IntValue(agent.Id),
+			"snmpsim.engine.id":         attribute.IntValue(engine.Id),
+			"http.request.method":       attribute.StringValue("PUT"),
+			"http.response.status_code": attribute.IntValue(200),
+			"snmpsim.retry_count":       attribute.IntValue(0),
+		} {
+			value, ok := attributes.Value(key)
+			if assert.True(t, ok, "attribute %s is missing", key) {
+				assert.Equal(t, expected, value, "attribute %s", key)
+			}
+		}
+		assert.Equal(t, codes.Unset, link.Status.Code)
+		assert.Equal(t, "ManagementClient.GetLab", spans[3].Name)
+		assert.Equal(t, codes.Error, spans[3].Status.Code)
+	}
+
+	var metrics metricdata.ResourceMetrics
+	if !assert.NoError(t, reader.Collect(context.Background(), &metrics)) || !assert.Len(t, metrics.ScopeMetrics, 1) {
+		return
+	}
+	for _, m := range metrics.ScopeMetrics[0].Metrics {
+		switch m.Name {
+		case requestDurationMetric:
+			histogram, ok := m.Data.(metricdata.Histogram[float64])
+			if assert.True(t, ok) {
+				var count uint64
+				for _, point := range histogram.DataPoints {
+					count += point.Count
+				}
+				assert.Equal(t, uint64(4), count)
+			}
+		case requestErrorsMetric:
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			if assert.True(t, ok) && assert.Len(t, sum.DataPoints, 1) {
+				assert.Equal(t, int64(1), sum.DataPoints[0].Value)
+				operation, _ := sum.DataPoints[0].Attributes.Value(operationKey)
+				assert.Equal(t, "ManagementClient.GetLab", operation.AsString())
+			}
+		default:
+			t.Errorf("unexpected metric %s", m.Name)
+		}
+	}
+}
+
+func TestOperationName(t *testing.T) {
+	server := snmpsimtest.NewServer()
+	defer server.Close()
+
+	exporter := tracetest.NewInMemoryExporter()
+	client, err := NewMetricsClient(server.URL, WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))))
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+	pager := client.ListProcesses(context.Background(), ListOptions{})
+	for pager.Next() {
+	}
+	assert.NoError(t, pager.Err())
+	_, err = client.FilterCatalog().PacketFilters()
+	assert.NoError(t, err)
+
+	var names []string
+	for _, span := range exporter.GetSpans() {
+		names = append(names, span.Name)
+	}
+	assert.Equal(t, []string{"ProcessPager.Next", "FilterCatalog.PacketFilters"}, names)
+	assert.Equal(t, "HTTP GET", operationName("GET"))
+}
@@ -0,0 +1,472 @@
+package snmpsimclient
+
+import (
+	"bytes"
+	"context"
+	"github.com/pkg/errors"
+	"strconv"
+	"strings"
+)
+
+/*
+Transaction records every object created and every link added through it. If one of its calls fails, everything done so far is
+undone in reverse order (e.g. RemoveEndpointFromEngine, DeleteEngine, ...) and a TransactionError is returned.
+After a successful build Commit has to be called, Rollback undoes the transaction on demand.
+A Transaction must not be used concurrently.
+*/
+type Transaction struct {
+	client *ManagementClient
+	ctx    context.Context
+	undo   []undoStep
+	done   bool
+}
+
+//undoStep reverts a single call of a transaction
+type undoStep struct {
+	description string
+	undo        func(ctx context.Context) error
+}
+
+/*
+TransactionError is returned when a call of a transaction failed. It contains the original error and all errors that occurred while rolling back.
+*/
+type TransactionError struct {
+	Err            error
+	RollbackErrors []error
+}
+
+func (t *TransactionError) Error() string {
+	msg := "transaction failed: " + t.Err.Error()
+	if len(t.RollbackErrors) != 0 {
+		var rollbackErrors []string
+		for _, err := range t.RollbackErrors {
+			rollbackErrors = append(rollbackErrors, err.Error())
+		}
+		msg += " // rollback failed (" + strconv.Itoa(len(t.RollbackErrors)) + " errors): " + strings.Join(rollbackErrors, "; ")
+	}
+	return msg
+}
+
+/*
+Unwrap returns the original error.
+*/
+func (t *TransactionError) Unwrap() error {
+	return t.Err
+}
+
+/*
+ErrTransactionDone is returned when a transaction is used after it was committed or rolled back.
+*/
+var ErrTransactionDone = errors.New("transaction was already committed or rolled back")
+
+/*
+NewTransaction starts a new transaction.
+*/
+func (c *ManagementClient) NewTransaction() *Transaction {
+	return c.NewTransactionContext(context.Background())
+}
+
+/*
+NewTransactionContext starts a new transaction whose calls carry the given context through to the http requests.
+*/
+func (c *ManagementClient) NewTransactionContext(ctx context.Context) *Transaction {
+	return &Transaction{client: c, ctx: ctx}
+}
+
+/*
+InTransaction runs the given function in a new transaction. If the function returns an error, the transaction is rolled back,
+otherwise it is committed.
+*/
+func (c *ManagementClient) InTransaction(build func(tx *Transaction) error) error {
+	return c.InTransactionContext(context.Background(), build)
+}
+
+/*
+InTransactionContext is like InTransaction but carries the given context through to the http requests.
+*/
+func (c *ManagementClient) InTransactionContext(ctx context.Context, build func(tx *Transaction) error) error {
+	tx := c.NewTransactionContext(ctx)
+	err := build(tx)
+	if err != nil {
+		if tx.done {
+			//a failed call already rolled the transaction back
+			return err
+		}
+		return tx.fail(err)
+	}
+	tx.Commit()
+	return nil
+}
+
+/*
+Commit ends the transaction and keeps all changes.
+*/
+func (t *Transaction) Commit() {
+	t.undo = nil
+	t.done = true
+}
+
+/*
+Rollback undoes all changes of the transaction in reverse order. All errors which occur are returned together.
+*/
+func (t *Transaction) Rollback() error {
+	return t.RollbackContext(context.Background())
+}
+
+/*
+RollbackContext is like Rollback but carries the given context through to the http requests.
+*/
+func (t *Transaction) RollbackContext(ctx context.Context) error {
+	if t.done {
+		return ErrTransactionDone
+	}
+	errs := t.rollback(ctx)
+	if len(errs) != 0 {
+		return &TransactionError{Err: errors.New("rollback failed"), RollbackErrors: errs}
+	}
+	return nil
+}
+
+func (t *Transaction) rollback(ctx context.Context) []error {
+	var errs []error
+	for i := len(t.undo) - 1; i >= 0; i-- {
+		if err := t.undo[i].undo(ctx); err != nil {
+			errs = append(errs, errors.Wrap(err, "error while trying to "+t.undo[i].description))
+		}
+	}
+	t.undo = nil
+	t.done = true
+	return errs
+}
+
+//fail rolls back the transaction after the given error occurred. The rollback is not bound to the context of the transaction, so that it also happens after a cancellation.
+func (t *Transaction) fail(err error) error {
+	return &TransactionError{Err: err, RollbackErrors: t.rollback(context.Background())}
+}
+
+func (t *Transaction) record(description string, undo func(ctx context.Context) error) {
+	t.undo = append(t.undo, undoStep{description: description, undo: undo})
+}
+
+/*
+CreateLab creates a new lab, it is deleted on rollback.
+*/
+func (t *Transaction) CreateLab(name string) (Lab, error) {
+	return t.createLab(name, nil)
+}
+
+/*
+CreateLabWithTag creates a new lab tagged with the given tag, it is deleted on rollback.
+*/
+func (t *Transaction) CreateLabWithTag(name string, tagId int) (Lab, error) {
+	return t.createLab(name, &tagId)
+}
+
+func (t *Transaction) createLab(name string, tagId *int) (Lab, error) {
+	if t.done {
+		return Lab{}, ErrTransactionDone
+	}
+	lab, err := t.client.createLab(t.ctx, &name, tagId)
+	if err != nil {
+		return Lab{}, t.fail(err)
+	}
+	t.record("delete lab "+strconv.Itoa(lab.Id), func(ctx context.Context) error {
+		return t.client.DeleteLabContext(ctx, lab.Id)
+	})
+	return lab, nil
+}
+
+/*
+CreateEngine creates a new engine, it is deleted on rollback.
+*/
+func (t *Transaction) CreateEngine(name, engineId string) (Engine, error) {
+	return t.createEngine(name, engineId, nil)
+}
+
+/*
+CreateEngineWithTag creates a new engine tagged with the given tag, it is deleted on rollback.
+*/
+func (t *Transaction) CreateEngineWithTag(name, engineId string, tagId int) (Engine, error) {
+	return t.createEngine(name, engineId, &tagId)
+}
+
+func (t *Transaction) createEngine(name, engineId string, tagId *int) (Engine, error) {
+	if t.done {
+		return Engine{}, ErrTransactionDone
+	}
+	engine, err := t.client.createEngine(t.ctx, &name, &engineId, tagId)
+	if err != nil {
+		return Engine{}, t.fail(err)
+	}
+	t.record("delete engine "+strconv.Itoa(engine.Id), func(ctx context.Context) error {
+		return t.client.DeleteEngineContext(ctx, engine.Id)
+	})
+	return engine, nil
+}
+
+/*
+CreateAgent creates a new agent, it is deleted on rollback.
+*/
+func (t *Transaction) CreateAgent(name, dataDir string) (Agent, error) {
+	return t.createAgent(name, dataDir, nil)
+}
+
+/*
+CreateAgentWithTag creates a new agent tagged with the given tag, it is deleted on rollback.
+*/
+func (t *Transaction) CreateAgentWithTag(name, dataDir string, tagId int) (Agent, error) {
+	return t.createAgent(name, dataDir, &tagId)
+}
+
+func (t *Transaction) createAgent(name, dataDir string, tagId *int) (Agent, error) {
+	if t.done {
+		return Agent{}, ErrTransactionDone
+	}
+	agent, err := t.client.createAgent(t.ctx, &name, &dataDir, tagId)
+	if err != nil {
+		return Agent{}, t.fail(err)
+	}
+	t.record("delete agent "+strconv.Itoa(agent.Id), func(ctx context.Context) error {
+		return t.client.DeleteAgentContext(ctx, agent.Id)
+	})
+	return agent, nil
+}
+
+/*
+CreateEndpoint creates a new endpoint, it is deleted on rollback.
+*/
+func (t *Transaction) CreateEndpoint(name, address, protocol string) (Endpoint, error) {
+	return t.createEndpoint(name, address, protocol, nil)
+}
+
+/*
+CreateEndpointWithTag creates a new endpoint tagged with the given tag, it is deleted on rollback.
+*/
+func (t *Transaction) CreateEndpointWithTag(name, address, protocol string, tagId int) (Endpoint, error) {
+	return t.createEndpoint(name, address, protocol, &tagId)
+}
+
+func (t *Transaction) createEndpoint(name, address, protocol string, tagId *int) (Endpoint, error) {
+	if t.done {
+		return Endpoint{}, ErrTransactionDone
+	}
+	endpoint, err := t.client.createEndpoint(t.ctx, &name, &address, &protocol, tagId)
+	if err != nil {
+		return Endpoint{}, t.fail(err)
+	}
+	t.record("delete endpoint "+strconv.Itoa(endpoint.Id), func(ctx context.Context) error {
+		return t.client.DeleteEndpointContext(ctx, endpoint.Id)
+	})
+	return endpoint, nil
+}
+
+/*
+CreateUser creates a new user, it is deleted on rollback.
+*/
+func (t *Transaction) CreateUser(user, name, authKey, authProto, privKey, privProto string) (User, error) {
+	return t.createUser(user, name, authKey, authProto, privKey, privProto, nil)
+}
+
+/*
+CreateUserWithTag creates a new user tagged with the given tag, it is deleted on rollback.
+*/
+func (t *Transaction) CreateUserWithTag(user, name, authKey, authProto, privKey, privProto string, tagId int) (User, error) {
+	return t.createUser(user, name, authKey, authProto, privKey, privProto, &tagId)
+}
+
+func (t *Transaction) createUser(user, name, authKey, authProto, privKey, privProto string, tagId *int) (User, error) {
+	if t.done {
+		return User{}, ErrTransactionDone
+	}
+	newUser, err := t.client.createUser(t.ctx, &user, &name, &authKey, &authProto, &privKey, &privProto, tagId)
+	if err != nil {
+		return User{}, t.fail(err)
+	}
+	t.record("delete user "+strconv.Itoa(newUser.Id), func(ctx context.Context) error {
+		return t.client.DeleteUserContext(ctx, newUser.Id)
+	})
+	return newUser, nil
+}
+
+/*
+CreateTag creates a new tag, it is deleted on rollback.
+*/
+func (t *Transaction) CreateTag(name, description string) (Tag, error) {
+	if t.done {
+		return Tag{}, ErrTransactionDone
+	}
+	tag, err := t.client.CreateTagContext(t.ctx, name, description)
+	if err != nil {
+		return Tag{}, t.fail(err)
+	}
+	t.record("delete tag "+strconv.Itoa(tag.Id), func(ctx context.Context) error {
+		return t.client.DeleteTagContext(ctx, tag.Id)
+	})
+	return tag, nil
+}
+
+/*
+AddAgentToLab adds an Agent to a Lab, it is removed on rollback.
+*/
+func (t *Transaction) AddAgentToLab(labId, agentId int) error {
+	return t.call("remove agent "+strconv.Itoa(agentId)+" from lab "+strconv.Itoa(labId),
+		func(ctx context.Context) error { return t.client.AddAgentToLabContext(ctx, labId, agentId) },
+		func(ctx context.Context) error { return t.client.RemoveAgentFromLabContext(ctx, labId, agentId) })
+}
+
+/*
+AddEngineToAgent adds an Engine to an Agent, it is removed on rollback.
+*/
+func (t *Transaction) AddEngineToAgent(agentId, engineId int) error {
+	return t.call("remove engine "+strconv.Itoa(engineId)+" from agent "+strconv.Itoa(agentId),
+		func(ctx context.Context) error { return t.client.AddEngineToAgentContext(ctx, agentId, engineId) },
+		func(ctx context.Context) error { return t.client.RemoveEngineFromAgentContext(ctx, agentId, engineId) })
+}
+
+/*
+AddUserToEngine adds an User to an Engine, it is removed on rollback.
+*/
+func (t *Transaction) AddUserToEngine(engineId, userId int) error {
+	return t.call("remove user "+strconv.Itoa(userId)+" from engine "+strconv.Itoa(engineId),
+		func(ctx context.Context) error { return t.client.AddUserToEngineContext(ctx, engineId, userId) },
+		func(ctx context.Context) error { return t.client.RemoveUserFromEngineContext(ctx, engineId, userId) })
+}
+
+/*
+AddEndpointToEngine adds an Endpoint to an Engine, it is removed on rollback.
+*/
+func (t *Transaction) AddEndpointToEngine(engineId, endpointId int) error {
+	return t.call("remove endpoint "+strconv.Itoa(endpointId)+" from engine "+strconv.Itoa(engineId),
+		func(ctx context.Context) error { return t.client.AddEndpointToEngineContext(ctx, engineId, endpointId) },
+		func(ctx context.Context) error {
+			return t.client.RemoveEndpointFromEngineContext(ctx, engineId, endpointId)
+		})
+}
+
+/*
+AddTagToLab adds a tag to a lab, it is removed on rollback.
+*/
+func (t *Transaction) AddTagToLab(labId, tagId int) error {
+	return t.call("remove tag "+strconv.Itoa(tagId)+" from lab "+strconv.Itoa(labId),
+		func(ctx context.Context) error { return t.client.AddTagToLabContext(ctx, labId, tagId) },
+		func(ctx context.Context) error { return t.client.RemoveTagFromLabContext(ctx, labId, tagId) })
+}
+
+/*
+AddTagToAgent adds a tag to an agent, it is removed on rollback.
+*/
+func (t *Transaction) AddTagToAgent(agentId, tagId int) error {
+	return t.call("remove tag "+strconv.Itoa(tagId)+" from agent "+strconv.Itoa(agentId),
+		func(ctx context.Context) error { return t.client.AddTagToAgentContext(ctx, agentId, tagId) },
+		func(ctx context.Context) error { return t.client.RemoveTagFromAgentContext(ctx, agentId, tagId) })
+}
+
+/*
+AddTagToEngine adds a tag to an engine, it is removed on rollback.
+*/
+func (t *Transaction) AddTagToEngine(engineId, tagId int) error {
+	return t.call("remove tag "+strconv.Itoa(tagId)+" from engine "+strconv.Itoa(engineId),
+		func(ctx context.Context) error { return t.client.AddTagToEngineContext(ctx, engineId, tagId) },
+		func(ctx context.Context) error { return t.client.RemoveTagFromEngineContext(ctx, engineId, tagId) })
+}
+
+/*
+AddTagToEndpoint adds a tag to an endpoint, it is removed on rollback.
+*/
+func (t *Transaction) AddTagToEndpoint(endpointId, tagId int) error {
+	return t.call("remove tag "+strconv.Itoa(tagId)+" from endpoint "+strconv.Itoa(endpointId),
+		func(ctx context.Context) error { return t.client.AddTagToEndpointContext(ctx, endpointId, tagId) },
+		func(ctx context.Context) error { return t.client.RemoveTagFromEndpointContext(ctx, endpointId, tagId) })
+}
+
+/*
+AddTagToUser adds a tag to an user, it is removed on rollback.
+*/
+func (t *Transaction) AddTagToUser(userId, tagId int) error {
+	return t.call("remove tag "+strconv.Itoa(tagId)+" from user "+strconv.Itoa(userId),
+		func(ctx context.Context) error { return t.client.AddTagToUserContext(ctx, userId, tagId) },
+		func(ctx context.Context) error { return t.client.RemoveTagFromUserContext(ctx, userId, tagId) })
+}
+
+/*
+SetLabPower activates or deactivates a lab, the previous power state is restored on rollback.
+*/
+func (t *Transaction) SetLabPower(labId int, power bool) error {
+	if t.done {
+		return ErrTransactionDone
+	}
+	lab, err := t.client.GetLabContext(t.ctx, labId)
+	if err != nil {
+		return t.fail(errors.Wrap(err, "error while getting lab "+strconv.Itoa(labId)))
+	}
+	previous := lab.Power == "on"
+	undo := func(ctx context.Context) error {
+		return t.client.SetLabPowerContext(ctx, labId, previous)
+	}
+	if previous == power {
+		//the power state is not changed, so there is nothing to restore
+		undo = func(ctx context.Context) error { return nil }
+	}
+	return t.call("reset power of lab "+strconv.Itoa(labId),
+		func(ctx context.Context) error { return t.client.SetLabPowerContext(ctx, labId, power) },
+		undo)
+}
+
+/*
+UploadRecordFile uploads the given record file to the api. On rollback it is deleted, or the previous recording is
+restored if the upload replaced one.
+*/
+func (t *Transaction) UploadRecordFile(localPath, remotePath string) error {
+	return t.uploadRecordFile(remotePath, func(ctx context.Context) error {
+		return t.client.UploadRecordFileContext(ctx, localPath, remotePath)
+	})
+}
+
+/*
+UploadRecordFileString uploads the given record data to the api. On rollback it is deleted, or the previous recording
+is restored if the upload replaced one.
+*/
+func (t *Transaction) UploadRecordFileString(recordContents *string, remotePath string) error {
+	return t.uploadRecordFile(remotePath, func(ctx context.Context) error {
+		return t.client.UploadRecordFileStringContext(ctx, recordContents, remotePath)
+	})
+}
+
+//uploadRecordFile runs the upload of a record file and records how to restore the previous state of the remote path
+func (t *Transaction) uploadRecordFile(remotePath string, upload func(ctx context.Context) error) error {
+	if t.done {
+		return ErrTransactionDone
+	}
+	var previous bytes.Buffer
+	existed := true
+	if err := t.client.DownloadRecording(t.ctx, remotePath, &previous); errors.Is(err, ErrNotFound) {
+		existed = false
+	} else if err != nil {
+		return t.fail(errors.Wrap(err, "error while getting record file "+remotePath))
+	}
+
+	if !existed {
+		return t.call("delete record file "+remotePath, upload, func(ctx context.Context) error {
+			return t.client.DeleteRecordFileContext(ctx, remotePath)
+		})
+	}
+	return t.call("restore record file "+remotePath, upload, func(ctx context.Context) error {
+		err := t.client.DeleteRecordFileContext(ctx, remotePath)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		return t.client.UploadRecording(ctx, remotePath, bytes.NewReader(previous.Bytes()))
+	})
+}
+
+//call runs a call which does not create an object and records how to undo it
+func (t *Transaction) call(description string, do, undo func(ctx context.Context) error) error {
+	if t.done {
+		return ErrTransactionDone
+	}
+	if err := do(t.ctx); err != nil {
+		return t.fail(err)
+	}
+	t.record(description, undo)
+	return nil
+}
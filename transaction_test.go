@@ -0,0 +1,147 @@
+package snmpsimclient
+
+import (
+	"github.com/inexio/snmpsim-restapi-go-client/snmpsimtest"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestTransaction_Rollback(t *testing.T) {
+	var mtx sync.Mutex
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/"+mgmtEndpointPath)
+		mtx.Lock()
+		requests = append(requests, r.Method+" "+path)
+		mtx.Unlock()
+		switch {
+		case r.Method == "POST":
+			w.WriteHeader(201)
+			_, _ = w.Write([]byte(`{"id": 1}`))
+		case r.Method == "PUT" && strings.HasPrefix(path, "engines/1/endpoint/"):
+			w.WriteHeader(409)
+		case r.Method == "DELETE" && path == "users/1":
+			w.WriteHeader(500)
+		case r.Method == "DELETE":
+			w.WriteHeader(204)
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewManagementClient(server.URL, WithRetryPolicy(NoRetryPolicy()))
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+
+	err = client.InTransaction(func(tx *Transaction) error {
+		engine, err := tx.CreateEngine("engine", "")
+		if err != nil {
+			return err
+		}
+		user, err := tx.CreateUser("user", "user", "", "", "", "")
+		if err != nil {
+			return err
+		}
+		endpoint, err := tx.CreateEndpoint("endpoint", "127.0.0.1:1161", "")
+		if err != nil {
+			return err
+		}
+		if err = tx.AddUserToEngine(engine.Id, user.Id); err != nil {
+			return err
+		}
+		return tx.AddEndpointToEngine(engine.Id, endpoint.Id)
+	})
+	if !assert.Error(t, err, "no error returned for a failed transaction") {
+		return
+	}
+	assert.True(t, errors.Is(err, ErrConflict), "original error is not preserved")
+	var txErr *TransactionError
+	if assert.True(t, errors.As(err, &txErr), "error is not a transaction error") {
+		if assert.Len(t, txErr.RollbackErrors, 1, "rollback errors are not reported") {
+			assert.True(t, errors.Is(txErr.RollbackErrors[0], ErrServer))
+		}
+	}
+
+	assert.Equal(t, []string{
+		"POST engines",
+		"POST users",
+		"POST endpoints",
+		"PUT engines/1/user/1",
+		"PUT engines/1/endpoint/1",
+		"DELETE engines/1/user/1",
+		"DELETE endpoints/1",
+		"DELETE users/1",
+		"DELETE engines/1",
+	}, requests, "rollback was not done in reverse order")
+
+	//Committed transactions are not rolled back
+	requests = nil
+	tx := client.NewTransaction()
+	_, err = tx.CreateLab("lab")
+	assert.NoError(t, err, "error while creating lab")
+	tx.Commit()
+	assert.Equal(t, ErrTransactionDone, tx.Rollback())
+	_, err = tx.CreateLab("lab")
+	assert.Equal(t, ErrTransactionDone, err)
+	assert.Equal(t, []string{"POST labs"}, requests)
+}
+
+func TestTransaction_RollbackRestoresPreviousState(t *testing.T) {
+	fake := snmpsimtest.NewUnstartedServer()
+	fake.AddRecording("tests/existing.snmprec", []byte("1.3.6.1.2.1.1.1.0|4|old\n"))
+	//the fake rejects uploads to existing recordings, so the test server wraps it to make POSTs overwrite them like
+	//some versions of the api do
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" && strings.HasPrefix(r.URL.Path, "/"+recordingsPath+"/") {
+			fake.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("DELETE", r.URL.Path, nil))
+		}
+		fake.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	client, err := NewManagementClient(server.URL)
+	if !assert.NoError(t, err, "error while creating a new api client") {
+		return
+	}
+	on, err := client.CreateLab("on")
+	if !assert.NoError(t, err, "error while creating lab") {
+		return
+	}
+	assert.NoError(t, client.SetLabPower(on.Id, true), "error while powering on lab")
+	off, err := client.CreateLab("off")
+	if !assert.NoError(t, err, "error while creating lab") {
+		return
+	}
+
+	tx := client.NewTransaction()
+	assert.NoError(t, tx.SetLabPower(on.Id, true), "error while powering on lab")
+	assert.NoError(t, tx.SetLabPower(off.Id, true), "error while powering on lab")
+	content := "1.3.6.1.2.1.1.1.0|4|new\n"
+	assert.NoError(t, tx.UploadRecordFileString(&content, "tests/existing.snmprec"), "error while uploading record file")
+	assert.NoError(t, tx.UploadRecordFileString(&content, "tests/new.snmprec"), "error while uploading record file")
+	recording, _ := fake.Recording("tests/existing.snmprec")
+	assert.Equal(t, content, string(recording), "record file was not overwritten")
+	assert.NoError(t, tx.Rollback(), "error during rollback")
+
+	lab, err := client.GetLab(on.Id)
+	if assert.NoError(t, err, "error while getting lab") {
+		assert.Equal(t, "on", lab.Power, "lab which was already on was switched off")
+	}
+	lab, err = client.GetLab(off.Id)
+	if assert.NoError(t, err, "error while getting lab") {
+		assert.Equal(t, "off", lab.Power, "power of lab was not restored")
+	}
+	recording, ok := fake.Recording("tests/existing.snmprec")
+	if assert.True(t, ok, "overwritten record file was deleted") {
+		assert.Equal(t, "1.3.6.1.2.1.1.1.0|4|old\n", string(recording), "overwritten record file was not restored")
+	}
+	_, ok = fake.Recording("tests/new.snmprec")
+	assert.False(t, ok, "uploaded record file was not deleted")
+}